@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"news-aggregator/server/app"
+	"news-aggregator/server/service"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+// fetch the news of the sources of the local storage, or of a single one with --source.
+func fetch(args []string) error {
+	fs := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	var config app.Config
+	config.RegisterFetchFlags(fs)
+	source := fs.String("source", "", "Fetch the news of a single source by name instead of all sources. Usage: --source=bbc")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	storage, err := app.OpenStorage(config)
+	if err != nil {
+		log.Printf("Error opening storage: %v", err)
+		return err
+	}
+	defer storage.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fetcher := config.Fetch(storage)
+	var report service.Report
+	if *source == "" {
+		report, err = fetcher.UpdateNews(ctx)
+	} else {
+		report, err = fetchSource(ctx, fetcher, *source)
+	}
+	if len(report.Sources) > 0 {
+		if printErr := printReport(os.Stdout, report); printErr != nil {
+			return printErr
+		}
+	}
+	return err
+}
+
+// fetchSource of the name with the fetcher.
+func fetchSource(ctx context.Context, fetcher service.Fetch, name string) (service.Report, error) {
+	report := service.Report{Started: time.Now()}
+	source, err := fetcher.SourceManager.GetSource(name)
+	if err != nil {
+		log.Printf("Error getting source %s: %v", name, err)
+		return report, err
+	}
+	report.Sources = []service.SourceReport{fetcher.UpdateSource(ctx, source)}
+	return report, report.Err()
+}
+
+// printReport of a fetch as a table.
+func printReport(w io.Writer, report service.Report) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "SOURCE\tFETCHED\tNEW\tDUPLICATE\tNOT MODIFIED\tDURATION\tERROR")
+	for _, s := range report.Sources {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%t\t%s\t%s\n",
+			s.Source, s.Fetched, s.New, s.Duplicate, s.NotModified, s.Duration.Round(time.Millisecond), s.Error)
+	}
+	return table.Flush()
+}
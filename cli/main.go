@@ -1,67 +1,61 @@
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"log"
-	"news-aggregator/internal"
-	"news-aggregator/internal/initializers"
-	"news-aggregator/internal/sort"
-	"news-aggregator/internal/validator"
+	"os"
+	"strings"
 )
 
+// usage of the commands of the CLI.
+const usage = `Usage: news <command> [flags]
+
+Commands:
+  search    Print the news matching the flags, the command run without one.
+  export    Write the news matching the flags to the standard output as JSON.
+  sources   Manage the sources with add, list, update and rm.
+  fetch     Fetch the news of the sources into the storage.
+  serve     Start the aggregator server.
+
+Run 'news <command> --help' for the flags of a command.
+`
+
+// commands of the CLI by name.
+var commands = map[string]func(args []string) error{
+	"search":  search,
+	"export":  export,
+	"sources": sources,
+	"fetch":   fetch,
+	"serve":   serve,
+}
+
 // main is the entry point of the news-aggregator CLI application.
 func main() {
-	help := flag.Bool("help", false, "Show all available arguments and their descriptions.")
-	sources := flag.String("sources", "", "Select the desired news sources to get the news from. Usage: --sources=bbc,usatoday")
-	keywords := flag.String("keywords", "", "Specify the keywords to filter the news by. Usage: --keywords=Ukraine,China")
-	dateStart := flag.String("date-start", "", "Specify the start date to filter the news by. Usage: --date-start=2024-05-18")
-	dateEnd := flag.String("date-end", "", "Specify the end date to filter the news by. Usage: --date-end=2024-05-19")
-	sortOrder := flag.String("sort-order", "ASC", "Specify the sort order for the news items (ASC or DESC). The default is ASC. Usage: --sort-order=ASC")
-	sortBy := flag.String("sort-by", "source", "Specify the sort criteria for the news items (date or source). The default is source. Usage: --sort-by=source")
-	flag.Parse()
-	if *help {
-		flag.Usage()
+	err := run(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
 		return
 	}
-	resources, err := initializers.LoadSources("server-news/")
 	if err != nil {
-		return
-	}
-	availableSources := make([]string, 0)
-	for sourceName := range resources {
-		availableSources = append(availableSources, sourceName)
-	}
-	sortOptions := sort.Options{
-		Criterion: *sortBy,
-		Order:     *sortOrder,
-	}
-	config := validator.Config{
-		Sources:          *sources,
-		AvailableSources: availableSources,
-		DateStart:        *dateStart,
-		DateEnd:          *dateEnd,
-		SortOptions:      sortOptions,
+		log.Println(err)
+		os.Exit(1)
 	}
+}
 
-	v := validator.NewValidator(config)
-	err = v.Validate()
-	if err != nil {
-		log.Println(err)
-		return
+// run the command of the arguments. Without a command the flags are those of search.
+func run(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return search(args)
 	}
-	a := internal.NewAggregator(
-		resources,
-		*sources,
-		initializers.InitializeFilters(keywords, dateStart, dateEnd),
-		sortOptions)
-	news, err := a.Aggregate()
-	if err != nil {
-		print(err)
-		return
+	if args[0] == "help" {
+		fmt.Print(usage)
+		return nil
 	}
-	err = a.Print(news, *keywords)
-	if err != nil {
-		log.Println(err)
-		return
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
 	}
+	return command(args[1:])
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	clientv1 "news-aggregator/client/v1"
+	"news-aggregator/internal/entity"
+	"strings"
+)
+
+// pageLimit of the news requested in a single page from the server.
+const pageLimit = 1000
+
+// remoteConfig of the aggregator server queried instead of the local storage.
+type remoteConfig struct {
+	ServerURL  string
+	CACertFile string
+	Token      string
+}
+
+// register the flags of the server.
+func (c *remoteConfig) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.ServerURL, "server", "", "Query a running aggregator server instead of the local storage. Usage: --server=https://localhost:8443")
+	fs.StringVar(&c.CACertFile, "ca-cert", "", "Specify a PEM file of CA certificates trusted for the --server besides the system ones. Usage: --ca-cert=certs/ca.crt")
+	fs.StringVar(&c.Token, "token", "", "Specify a bearer token sent to the --server in the Authorization header. Usage: --token=$AGGREGATOR_TOKEN")
+}
+
+// client of the server.
+func (c remoteConfig) client() (*clientv1.Client, error) {
+	aggregator, err := clientv1.New(clientv1.Config{
+		ServerURL:  c.ServerURL,
+		CACertFile: c.CACertFile,
+		Token:      c.Token,
+	})
+	if err != nil {
+		log.Printf("Error creating the client of %s: %v", c.ServerURL, err)
+		return nil, err
+	}
+	return aggregator, nil
+}
+
+// fetchRemoteNews matching the query from the /news endpoint of the server,
+// following the pages until the last one. The server validates the query.
+func fetchRemoteNews(ctx context.Context, config remoteConfig, query clientv1.NewsQuery) ([]entity.News, error) {
+	aggregator, err := config.client()
+	if err != nil {
+		return nil, err
+	}
+	query.Limit = pageLimit
+	var news []entity.News
+	for {
+		page, err := aggregator.News(ctx, query)
+		if err != nil {
+			log.Printf("Error fetching news from %s: %v", config.ServerURL, err)
+			return nil, err
+		}
+		for _, item := range page.Items {
+			news = append(news, newsOf(item))
+		}
+		if page.NextCursor == "" {
+			return news, nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// newsOf the response of the server.
+func newsOf(item clientv1.News) entity.News {
+	return entity.News{
+		Title:       entity.Title(item.Title),
+		Description: entity.Description(item.Description),
+		Link:        entity.Link(item.Link),
+		Date:        item.Date,
+		Source:      item.Source,
+		Language:    item.Language,
+		Canonical:   entity.Link(item.Canonical),
+		GUID:        item.GUID,
+		Authors:     item.Authors,
+		Categories:  item.Categories,
+		Image:       entity.Link(item.Image),
+		Content:     item.Content,
+	}
+}
+
+// splitList of comma-separated values, nil when empty.
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
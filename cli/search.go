@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	clientv1 "news-aggregator/client/v1"
+	"news-aggregator/internal"
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/initializers"
+	"news-aggregator/internal/safefile"
+	"news-aggregator/internal/sort"
+	"news-aggregator/internal/template"
+	"news-aggregator/internal/validator"
+	"os"
+)
+
+// queryFlags of the news printed by search and written by export.
+type queryFlags struct {
+	sources    string
+	keywords   string
+	dateStart  string
+	dateEnd    string
+	sortOrder  string
+	sortBy     string
+	category   string
+	author     string
+	group      string
+	newsFolder string
+	remote     remoteConfig
+	// output format, template file and file of the printed news.
+	output       string
+	templateFile string
+	out          string
+}
+
+// register the flags of the query and of its output, in the format given by default.
+func (q *queryFlags) register(fs *flag.FlagSet, output template.Format) {
+	fs.StringVar(&q.sources, "sources", "", "Select the desired news sources to get the news from. Usage: --sources=bbc,usatoday")
+	fs.StringVar(&q.keywords, "keywords", "", "Specify the keyword query to filter the news by, with quoted phrases, AND, OR, NOT, -word, parentheses and title: or description: prefixes. Usage: --keywords='Ukraine,China'")
+	fs.StringVar(&q.dateStart, "date-start", "", "Specify the start date to filter the news by. Usage: --date-start=2024-05-18")
+	fs.StringVar(&q.dateEnd, "date-end", "", "Specify the end date to filter the news by. Usage: --date-end=2024-05-19")
+	fs.StringVar(&q.sortOrder, "sort-order", "ASC", "Specify the sort order for the news items (ASC or DESC). The default is ASC. Usage: --sort-order=ASC")
+	fs.StringVar(&q.sortBy, "sort-by", "source", "Specify the sort criteria for the news items (date, source or relevance to the keywords). The default is source. Usage: --sort-by=source")
+	fs.StringVar(&q.category, "category", "", "Specify comma-separated categories to filter the news by, news of any of them are kept. Usage: --category=Sport,Politics")
+	fs.StringVar(&q.author, "author", "", "Specify comma-separated authors to filter the news by, news of any of them are kept. Usage: --author='Jane Doe'")
+	fs.StringVar(&q.group, "group", "", "Group near-duplicate news from different sources into stories with --group=story. Usage: --group=story")
+	fs.StringVar(&q.newsFolder, "news-folder", "server-news/", "Path to the folder of the stored news of the sources. Default is 'server-news/'.")
+	q.remote.register(fs)
+	fs.StringVar(&q.output, "output", string(output), fmt.Sprintf("Specify the format of the news: text, json, ndjson, csv, markdown or html. The default is %s. Usage: --output=csv", output))
+	fs.StringVar(&q.templateFile, "template", "", "Render the news with a Go template file instead of the output format, given the data and functions of internal/template/news.tmpl. Usage: --template=report.tmpl")
+	fs.StringVar(&q.out, "out", "", "Write the news to a file instead of the standard output. Usage: --out=news.html")
+}
+
+// search prints the news matching the flags through the template of the news by default.
+func search(args []string) error {
+	return printNews("search", args, template.Text)
+}
+
+// export writes the news matching the flags as JSON by default.
+func export(args []string) error {
+	return printNews("export", args, template.JSON)
+}
+
+// printNews matching the flags of the command in the output format or template given by them.
+// A file given by --out is replaced once the news are rendered.
+func printNews(command string, args []string, output template.Format) error {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	var q queryFlags
+	q.register(fs, output)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	format, err := template.ParseFormat(q.output)
+	if err != nil {
+		return err
+	}
+	a, news, err := q.aggregate(context.Background())
+	if err != nil {
+		return err
+	}
+	out := template.Output{Format: format, TemplateFile: q.templateFile}
+	if q.out == "" {
+		return a.Print(os.Stdout, news, q.keywords, out)
+	}
+	var rendered bytes.Buffer
+	if err := a.Print(&rendered, news, q.keywords, out); err != nil {
+		return err
+	}
+	if err := safefile.WriteFile(q.out, rendered.Bytes(), 0o644); err != nil {
+		log.Printf("Error writing news to %s: %v", q.out, err)
+		return err
+	}
+	return nil
+}
+
+// aggregate the news matching the flags, from the server when one is given.
+func (q queryFlags) aggregate(ctx context.Context) (internal.Aggregate, []entity.News, error) {
+	if q.group != "" && q.group != "story" {
+		return nil, nil, errors.New("invalid group. Please use `story` or leave it empty")
+	}
+	sortOptions := sort.Options{
+		Criterion: q.sortBy,
+		Order:     q.sortOrder,
+	}
+	newsFilters := initializers.InitializeFilters(&q.keywords, &q.dateStart, &q.dateEnd, &q.category, &q.author)
+	if q.remote.ServerURL == "" {
+		return q.aggregateLocal(newsFilters, sortOptions)
+	}
+	news, err := fetchRemoteNews(ctx, q.remote, clientv1.NewsQuery{
+		Sources:    splitList(q.sources),
+		Keywords:   q.keywords,
+		DateStart:  q.dateStart,
+		DateEnd:    q.dateEnd,
+		SortBy:     q.sortBy,
+		SortOrder:  q.sortOrder,
+		Categories: splitList(q.category),
+		Authors:    splitList(q.author),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return internal.NewAggregator(nil, q.sources, newsFilters, sortOptions, q.group), news, nil
+}
+
+// aggregateLocal news of the news folder.
+func (q queryFlags) aggregateLocal(newsFilters []initializers.NewsFilter, sortOptions sort.Options) (internal.Aggregate, []entity.News, error) {
+	resources, err := initializers.LoadSources(q.newsFolder)
+	if err != nil {
+		return nil, nil, err
+	}
+	availableSources := make([]string, 0)
+	for sourceName := range resources {
+		availableSources = append(availableSources, sourceName)
+	}
+	config := validator.Config{
+		Sources:          q.sources,
+		AvailableSources: availableSources,
+		Keywords:         q.keywords,
+		DateStart:        q.dateStart,
+		DateEnd:          q.dateEnd,
+		SortOptions:      sortOptions,
+	}
+
+	v := validator.NewValidator(config)
+	err = v.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	a := internal.NewAggregator(resources, q.sources, newsFilters, sortOptions, q.group)
+	news, err := a.Aggregate()
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, news, nil
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"news-aggregator/server/app"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// serve the API of the local storage with the flags of the server until interrupted.
+func serve(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	var config app.Config
+	config.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return app.Serve(ctx, config)
+}
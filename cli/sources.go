@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	clientv1 "news-aggregator/client/v1"
+	"news-aggregator/server/app"
+	"news-aggregator/server/handlers"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// sourcesUsage of the sources command.
+const sourcesUsage = `Usage: news sources <command> [flags] [arguments]
+
+Commands:
+  list                 List the sources.
+  add <name> <url>     Add a source of a feed or page.
+  update <name>        Update the flags given of a source.
+  rm <name>            Remove a source.
+
+The sources of the local storage are managed unless --server is given.
+`
+
+// sourceArguments is the number of positional arguments of the sources commands.
+var sourceArguments = map[string]int{"list": 0, "add": 2, "update": 1, "rm": 1}
+
+// sourceFlags of the sources commands.
+type sourceFlags struct {
+	storage app.Config
+	remote  remoteConfig
+	// url, interval, language and retention of the added or updated source.
+	url      string
+	interval time.Duration
+	language string
+	maxAge   time.Duration
+	maxCount int
+}
+
+// register the flags of the sources command, with the settings of a source for add and update.
+func (s *sourceFlags) register(fs *flag.FlagSet, command string) {
+	s.storage.RegisterStorageFlags(fs)
+	s.remote.register(fs)
+	if command != "add" && command != "update" {
+		return
+	}
+	if command == "update" {
+		fs.StringVar(&s.url, "url", "", "Specify the new URL of the feed or page of the source. Usage: --url=https://feeds.bbci.co.uk/news/rss.xml")
+	}
+	fs.DurationVar(&s.interval, "interval", 0, "Specify the interval between fetches of the source, 0 for the default one. Usage: --interval=30m")
+	fs.StringVar(&s.language, "language", "", "Specify the language of the news of the source as a 2-letter code. Usage: --language=en")
+	fs.DurationVar(&s.maxAge, "max-age", 0, "Specify the maximum age of the stored news of the source, 0 keeps them. Usage: --max-age=720h")
+	fs.IntVar(&s.maxCount, "max-count", 0, "Specify the maximum number of stored news of the source, 0 keeps them all. Usage: --max-count=100")
+}
+
+// retention of the flags, nil when no limit is given.
+func (s sourceFlags) retention() *clientv1.Retention {
+	if s.maxAge == 0 && s.maxCount == 0 {
+		return nil
+	}
+	return &clientv1.Retention{MaxAge: clientv1.Interval(s.maxAge), MaxCount: s.maxCount}
+}
+
+// sources manages the sources of the local storage or of a server through its API,
+// so both are validated by the handlers of the server.
+func sources(args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "--help" || args[0] == "-help" || args[0] == "-h" {
+		fmt.Print(sourcesUsage)
+		return nil
+	}
+	command := args[0]
+	count, ok := sourceArguments[command]
+	if !ok {
+		fmt.Fprint(os.Stderr, sourcesUsage)
+		return fmt.Errorf("unknown sources command %q", command)
+	}
+	fs := flag.NewFlagSet("sources "+command, flag.ContinueOnError)
+	var s sourceFlags
+	s.register(fs, command)
+	arguments, err := parseInterspersed(fs, args[1:])
+	if err != nil {
+		return err
+	}
+	if len(arguments) != count {
+		fs.Usage()
+		return fmt.Errorf("sources %s expects %d arguments, got %d", command, count, len(arguments))
+	}
+
+	aggregator, closeStorage, err := s.client()
+	if err != nil {
+		return err
+	}
+	defer closeStorage()
+	ctx := context.Background()
+	switch command {
+	case "list":
+		list, err := aggregator.Sources(ctx)
+		if err != nil {
+			return err
+		}
+		return printSources(os.Stdout, list)
+	case "add":
+		source, err := aggregator.CreateSource(ctx, clientv1.Source{
+			Name:      arguments[0],
+			URL:       arguments[1],
+			Interval:  clientv1.Interval(s.interval),
+			Language:  s.language,
+			Retention: s.retention(),
+		})
+		if err != nil {
+			return err
+		}
+		return printSources(os.Stdout, []clientv1.Source{source})
+	case "update":
+		source, err := aggregator.PatchSource(ctx, arguments[0], s.patch(fs), "")
+		if err != nil {
+			return err
+		}
+		return printSources(os.Stdout, []clientv1.Source{source})
+	default:
+		if err := aggregator.DeleteSource(ctx, arguments[0], ""); err != nil {
+			return err
+		}
+		fmt.Printf("Removed source %s\n", arguments[0])
+		return nil
+	}
+}
+
+// patch of the settings whose flags are given.
+func (s sourceFlags) patch(fs *flag.FlagSet) clientv1.SourcePatch {
+	var patch clientv1.SourcePatch
+	retention := false
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "url":
+			patch.URL = &s.url
+		case "interval":
+			interval := clientv1.Interval(s.interval)
+			patch.Interval = &interval
+		case "language":
+			patch.Language = &s.language
+		case "max-age", "max-count":
+			retention = true
+		}
+	})
+	if retention {
+		if patch.Retention = s.retention(); patch.Retention == nil {
+			patch.Reset = append(patch.Reset, "retention")
+		}
+	}
+	return patch
+}
+
+// client of the server, or of the handlers of the server serving the local storage.
+// The returned function closes the storage.
+func (s sourceFlags) client() (*clientv1.Client, func(), error) {
+	if s.remote.ServerURL != "" {
+		aggregator, err := s.remote.client()
+		return aggregator, func() {}, err
+	}
+	storage, err := app.OpenStorage(s.storage)
+	if err != nil {
+		log.Printf("Error opening storage: %v", err)
+		return nil, nil, err
+	}
+	closeStorage := func() {
+		if err := storage.Close(); err != nil {
+			log.Printf("Error closing storage: %v", err)
+		}
+	}
+	api := handlers.API{Sources: handlers.SourceHandler{SourceManager: storage.Sources}}
+	aggregator, err := clientv1.New(clientv1.Config{
+		ServerURL:  "http://localhost",
+		HTTPClient: handlerClient{handler: api.Handler()},
+		MaxRetries: -1,
+	})
+	if err != nil {
+		closeStorage()
+		return nil, nil, err
+	}
+	return aggregator, closeStorage, nil
+}
+
+// handlerClient sends the requests of a client to a handler in the same process.
+type handlerClient struct {
+	handler http.Handler
+}
+
+// Do the request with the handler.
+func (c handlerClient) Do(r *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	c.handler.ServeHTTP(recorder, r)
+	return recorder.Result(), nil
+}
+
+// printSources as a table.
+func printSources(w io.Writer, sources []clientv1.Source) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tURL\tINTERVAL\tLANGUAGE\tRETENTION")
+	for _, source := range sources {
+		interval, retention := "default", "default"
+		if source.Interval != 0 {
+			interval = source.Interval.String()
+		}
+		if source.Retention != nil {
+			retention = fmt.Sprintf("max-age=%s max-count=%d", source.Retention.MaxAge, source.Retention.MaxCount)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", source.Name, source.URL, interval, source.Language, retention)
+	}
+	return table.Flush()
+}
+
+// parseInterspersed flags of the command with its positional arguments in between them,
+// it returns the positional arguments.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var arguments []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return arguments, nil
+		}
+		arguments = append(arguments, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
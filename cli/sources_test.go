@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	clientv1 "news-aggregator/client/v1"
+	"news-aggregator/internal/entity"
+	"news-aggregator/server/managers"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInterspersed(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantArguments []string
+		wantLanguage  string
+	}{
+		{name: "flags first", args: []string{"--language=en", "bbc", "https://bbc.com"}, wantArguments: []string{"bbc", "https://bbc.com"}, wantLanguage: "en"},
+		{name: "flags between", args: []string{"bbc", "--language", "uk", "https://bbc.com"}, wantArguments: []string{"bbc", "https://bbc.com"}, wantLanguage: "uk"},
+		{name: "no flags", args: []string{"bbc"}, wantArguments: []string{"bbc"}},
+		{name: "empty", args: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			language := fs.String("language", "", "")
+
+			arguments, err := parseInterspersed(fs, tt.args)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantArguments, arguments)
+			assert.Equal(t, tt.wantLanguage, *language)
+		})
+	}
+}
+
+func TestSources(t *testing.T) {
+	pathToSources := filepath.Join(t.TempDir(), "sources.json")
+	storage := []string{"--path-to-source", pathToSources, "--news-folder", t.TempDir()}
+	manager := managers.CreateSourceFolder(pathToSources)
+
+	assert.NoError(t, run(append([]string{"sources", "add", "bbc", "https://bbc.com/rss", "--interval=30m", "--language=EN"}, storage...)))
+	source, err := manager.GetSource("bbc")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PathToFile("https://bbc.com/rss"), source.PathToFile)
+	assert.Equal(t, "en", source.Language)
+
+	assert.ErrorContains(t, run(append([]string{"sources", "add", "bbc", "https://bbc.com"}, storage...)), "already exists")
+	assert.ErrorContains(t, run(append([]string{"sources", "add", "bbc news", "https://bbc.com"}, storage...)), "400 Bad Request")
+	assert.ErrorContains(t, run(append([]string{"sources", "add", "bbc"}, storage...)), "expects 2 arguments")
+
+	assert.NoError(t, run(append([]string{"sources", "update", "bbc", "--url=https://bbc.co.uk/rss", "--max-count=10"}, storage...)))
+	source, err = manager.GetSource("bbc")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PathToFile("https://bbc.co.uk/rss"), source.PathToFile)
+	assert.Equal(t, 10, source.Retention.MaxCount)
+	assert.Equal(t, "en", source.Language)
+
+	assert.NoError(t, run(append([]string{"sources", "update", "bbc", "--max-count=0"}, storage...)))
+	source, err = manager.GetSource("bbc")
+	assert.NoError(t, err)
+	assert.Nil(t, source.Retention)
+
+	assert.NoError(t, run(append([]string{"sources", "rm", "bbc"}, storage...)))
+	_, err = manager.GetSource("bbc")
+	assert.ErrorIs(t, err, managers.ErrSourceNotFound)
+	assert.ErrorContains(t, run(append([]string{"sources", "rm", "bbc"}, storage...)), "404 Not Found")
+	assert.ErrorContains(t, run([]string{"sources", "move"}), "unknown sources command")
+}
+
+func TestPrintSources(t *testing.T) {
+	var out bytes.Buffer
+
+	err := printSources(&out, []clientv1.Source{
+		{Name: "bbc", URL: "https://bbc.com/rss", Interval: clientv1.Interval(30 * time.Minute), Language: "en"},
+		{Name: "cnn", URL: "https://cnn.com/rss", Retention: &clientv1.Retention{MaxCount: 10}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `NAME  URL                  INTERVAL  LANGUAGE  RETENTION
+bbc   https://bbc.com/rss  30m0s     en        default
+cnn   https://cnn.com/rss  default             max-age=0s max-count=10
+`, out.String())
+}
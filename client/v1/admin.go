@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SourceStatus of the fetch schedule of a source.
+type SourceStatus struct {
+	Source   string    `json:"source"`
+	Interval Interval  `json:"interval"`
+	LastRun  time.Time `json:"lastRun,omitempty"`
+	NextRun  time.Time `json:"nextRun"`
+	// Failures in a row of the last fetches, LastError is the error of the last one.
+	Failures  int    `json:"failures"`
+	LastError string `json:"lastError,omitempty"`
+	Running   bool   `json:"running"`
+}
+
+// SourceRetention of the stored news of a source in a RetentionReport.
+type SourceRetention struct {
+	Source    string    `json:"source"`
+	Retention Retention `json:"retention"`
+	Kept      int       `json:"kept"`
+	// Deleted links of the news, on a dry run of the news that would be deleted.
+	Deleted []string `json:"deleted,omitempty"`
+	// Archived daily files, on a dry run the files that would be archived.
+	Archived []string `json:"archived,omitempty"`
+	// Error of the source when it could not be pruned.
+	Error string `json:"error,omitempty"`
+}
+
+// RetentionReport of a run of the retention, with one entry per source with stored news.
+type RetentionReport struct {
+	Started time.Time         `json:"started"`
+	DryRun  bool              `json:"dryRun,omitempty"`
+	Sources []SourceRetention `json:"sources"`
+}
+
+// Schedule gets the fetch schedule of every source.
+func (c *Client) Schedule(ctx context.Context) ([]SourceStatus, error) {
+	var schedule []SourceStatus
+	_, err := c.do(ctx, request{method: http.MethodGet, path: []string{"schedule"}}, &schedule)
+	return schedule, err
+}
+
+// PruneNews applies the retention to the stored news and archives old daily files.
+// With dryRun nothing is changed and the report lists what would be deleted and archived.
+// When some sources could not be pruned the report is returned together with an ErrServer error.
+// The server requires its admin token, given as the Token of the Config.
+func (c *Client) PruneNews(ctx context.Context, dryRun bool) (RetentionReport, error) {
+	var report RetentionReport
+	query := url.Values{"dry-run": {strconv.FormatBool(dryRun)}}
+	_, err := c.do(ctx, request{method: http.MethodPost, path: []string{"admin", "retention"}, query: query}, &report)
+	var e *Error
+	if errors.As(err, &e) && e.StatusCode == http.StatusInternalServerError {
+		_ = json.Unmarshal(e.Body, &report)
+	}
+	return report, err
+}
+
+// OpenAPI gets the OpenAPI document of the API.
+func (c *Client) OpenAPI(ctx context.Context) (json.RawMessage, error) {
+	var document json.RawMessage
+	_, err := c.do(ctx, request{method: http.MethodGet, path: []string{"openapi.json"}}, &document)
+	return document, err
+}
@@ -0,0 +1,219 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Version of the client, sent in the User-Agent header of its requests.
+const Version = "1.1.0" // x-release-please-version
+
+const (
+	// DefaultTimeout of a request, including the reading of its response.
+	DefaultTimeout = 30 * time.Second
+	// DefaultMaxRetries of a request failing with a server error.
+	DefaultMaxRetries = 3
+	// DefaultBackoff before the first retry of a request, doubled by every further retry.
+	DefaultBackoff = 200 * time.Millisecond
+	// maxBackoff between two attempts of a request, also limiting a Retry-After of the server.
+	maxBackoff = 30 * time.Second
+	// maxErrorBody is the size limit of the body of an error response kept in an Error.
+	maxErrorBody = 64 << 10
+)
+
+// HTTPClient sends the requests of a Client, an *http.Client by default.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Config of a Client, only the ServerURL is required.
+type Config struct {
+	// ServerURL of the news aggregator like https://news-aggregator:8443,
+	// the endpoints are resolved relative to its path.
+	ServerURL string
+	// CACertFile of PEM certificates trusted for the server besides the system certificates,
+	// for instance the CA of a self-signed server certificate.
+	CACertFile string
+	// InsecureSkipVerify disables the verification of the server certificate.
+	InsecureSkipVerify bool
+	// Token sent as a bearer token in the Authorization header of the requests,
+	// for instance to a server behind an authenticating proxy. None when empty.
+	Token string
+	// Timeout of a request, DefaultTimeout when zero.
+	Timeout time.Duration
+	// MaxRetries of a request failing with a server error, DefaultMaxRetries when zero and none when negative.
+	MaxRetries int
+	// Backoff before the first retry, DefaultBackoff when zero.
+	Backoff time.Duration
+	// HTTPClient sending the requests instead of an *http.Client with the TLS settings and the Timeout above.
+	HTTPClient HTTPClient
+}
+
+// Client of the HTTP API of a news aggregator server, safe for concurrent use.
+type Client struct {
+	serverURL  *url.URL
+	httpClient HTTPClient
+	token      string
+	maxRetries int
+	backoff    time.Duration
+}
+
+// New client of the server of the config.
+func New(config Config) (*Client, error) {
+	serverURL, err := url.Parse(config.ServerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL: %w", err)
+	}
+	if (serverURL.Scheme != "http" && serverURL.Scheme != "https") || serverURL.Host == "" {
+		return nil, fmt.Errorf("invalid server URL %q, expected an absolute http or https URL", config.ServerURL)
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		tlsConfig, err := loadTLSConfig(config.CACertFile, config.InsecureSkipVerify)
+		if err != nil {
+			return nil, err
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		httpClient = &http.Client{Timeout: orDefault(config.Timeout, DefaultTimeout), Transport: transport}
+	}
+	maxRetries := orDefault(config.MaxRetries, DefaultMaxRetries)
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	return &Client{
+		serverURL:  serverURL,
+		httpClient: httpClient,
+		token:      config.Token,
+		maxRetries: maxRetries,
+		backoff:    orDefault(config.Backoff, DefaultBackoff),
+	}, nil
+}
+
+// request to the server at the path relative to the server URL, whose elements are escaped.
+type request struct {
+	method string
+	path   []string
+	query  url.Values
+	// ifMatch precondition of the request, none when empty.
+	ifMatch     string
+	contentType string
+	// body encoded as JSON, none when nil.
+	body any
+}
+
+// endpoint URL of the path and query.
+func (c *Client) endpoint(path []string, query url.Values) *url.URL {
+	escaped := make([]string, len(path))
+	for i, element := range path {
+		escaped[i] = url.PathEscape(element)
+	}
+	endpoint := c.serverURL.JoinPath(escaped...)
+	endpoint.RawQuery = query.Encode()
+	return endpoint
+}
+
+// do the request and decode the JSON body of its response into the result unless it is nil.
+// Requests that can be repeated are retried with an exponential backoff when the server fails
+// with a 5xx status or cannot be reached. It returns the headers of the response.
+func (c *Client) do(ctx context.Context, r request, result any) (http.Header, error) {
+	var body []byte
+	if r.body != nil {
+		var err error
+		if body, err = json.Marshal(r.body); err != nil {
+			return nil, err
+		}
+	}
+	for attempt := 0; ; attempt++ {
+		header, temporary, err := c.send(ctx, r, body, result)
+		if err == nil || !temporary || !idempotent(r.method) || attempt >= c.maxRetries {
+			return header, err
+		}
+		wait := c.backoff << attempt
+		var e *Error
+		if errors.As(err, &e) && e.RetryAfter > wait {
+			wait = e.RetryAfter
+		}
+		wait = min(wait, maxBackoff)
+		log.Printf("Retrying %s %s in %s: %v", r.method, c.endpoint(r.path, r.query).Redacted(), wait, err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return header, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// send a single attempt of the request, reporting if its failure is temporary.
+func (c *Client) send(ctx context.Context, r request, body []byte, result any) (http.Header, bool, error) {
+	endpoint := c.endpoint(r.path, r.query)
+	req, err := http.NewRequestWithContext(ctx, r.method, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "news-aggregator-client/"+Version)
+	if body != nil {
+		req.Header.Set("Content-Type", orDefault(r.contentType, "application/json"))
+	}
+	if r.ifMatch != "" {
+		req.Header.Set("If-Match", r.ifMatch)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		var certErr *tls.CertificateVerificationError
+		return nil, ctx.Err() == nil && !errors.As(err, &certErr), err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Failed to close response body: %v", err)
+		}
+	}(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		e := responseError(r.method, endpoint, resp)
+		return resp.Header, e.StatusCode >= 500, e
+	}
+	if result == nil {
+		return resp.Header, false, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return resp.Header, false, fmt.Errorf("failed to decode response of %s %s: %w", r.method, endpoint.Redacted(), err)
+	}
+	return resp.Header, false, nil
+}
+
+// idempotent reports if a request of the method can be repeated without changing its outcome.
+// The JSON merge patches of the API are idempotent too.
+func idempotent(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func orDefault[T comparable](value, defaultValue T) T {
+	var zero T
+	if value == zero {
+		return defaultValue
+	}
+	return value
+}
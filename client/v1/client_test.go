@@ -0,0 +1,255 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/story"
+	"news-aggregator/server/service"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{name: "https", config: Config{ServerURL: "https://localhost:8443"}},
+		{name: "path", config: Config{ServerURL: "http://aggregator/api/"}},
+		{name: "missing scheme", config: Config{ServerURL: "://bad-url"}, wantErr: "missing protocol scheme"},
+		{name: "relative", config: Config{ServerURL: "localhost:8443"}, wantErr: "expected an absolute http or https URL"},
+		{name: "missing CA file", config: Config{ServerURL: "https://localhost", CACertFile: filepath.Join(t.TempDir(), "ca.crt")}, wantErr: "failed to read CA certificates"},
+		{name: "invalid CA file", config: Config{ServerURL: "https://localhost", CACertFile: writeFile(t, "not a certificate")}, wantErr: "no PEM certificates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.config)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, c)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, c)
+		})
+	}
+}
+
+func TestClient_Endpoint(t *testing.T) {
+	c, err := New(Config{ServerURL: "https://aggregator:8443/api/"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "https://aggregator:8443/api/v1/sources/bbc%2Fnews", c.endpoint(sourcePath("bbc/news"), nil).String())
+}
+
+func TestClient_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	caCert := writeFile(t, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})))
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{name: "untrusted", config: Config{ServerURL: server.URL}, wantErr: true},
+		{name: "CA file", config: Config{ServerURL: server.URL, CACertFile: caCert}},
+		{name: "insecure", config: Config{ServerURL: server.URL, InsecureSkipVerify: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.config)
+			assert.NoError(t, err)
+
+			_, err = c.Sources(context.Background())
+			assert.Equal(t, tt.wantErr, err != nil, "Sources() error = %v", err)
+		})
+	}
+}
+
+func TestClient_Token(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{name: "token", token: "secret", want: "Bearer secret"},
+		{name: "none", token: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authorization string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorization = r.Header.Get("Authorization")
+				_, _ = w.Write([]byte(`[]`))
+			}))
+			defer server.Close()
+			c, err := New(Config{ServerURL: server.URL, Token: tt.token})
+			assert.NoError(t, err)
+
+			_, err = c.Schedule(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, authorization)
+		})
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		statuses   []int
+		maxRetries int
+		wantCalls  int32
+		wantErr    error
+	}{
+		{name: "recovers", method: http.MethodGet, statuses: []int{503, 500, 200}, wantCalls: 3},
+		{name: "gives up", method: http.MethodPut, statuses: []int{500, 500, 500}, maxRetries: 2, wantCalls: 3, wantErr: ErrServer},
+		{name: "disabled", method: http.MethodGet, statuses: []int{503, 200}, maxRetries: -1, wantCalls: 1, wantErr: ErrServer},
+		{name: "client error", method: http.MethodGet, statuses: []int{404, 200}, wantCalls: 1, wantErr: ErrNotFound},
+		{name: "not idempotent", method: http.MethodPost, statuses: []int{502, 200}, wantCalls: 1, wantErr: ErrServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[calls.Add(1)-1]
+				if status != http.StatusOK {
+					http.Error(w, http.StatusText(status), status)
+					return
+				}
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+			c, err := New(Config{ServerURL: server.URL, MaxRetries: tt.maxRetries, Backoff: time.Millisecond})
+			assert.NoError(t, err)
+
+			_, err = c.do(context.Background(), request{method: tt.method, path: []string{"test"}}, nil)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+		})
+	}
+}
+
+func TestClient_RetriesCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	c, err := New(Config{ServerURL: server.URL, Backoff: time.Millisecond})
+	assert.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = c.Schedule(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		wantMessage string
+		wantIs      error
+	}{
+		{name: "JSON error object", contentType: "application/json", body: `{"status":409,"error":"Conflict","message":"Source with name bbc already exists"}`, status: http.StatusConflict, wantMessage: "Source with name bbc already exists", wantIs: ErrConflict},
+		{name: "text", contentType: "text/plain", body: "invalid limit\n", status: http.StatusBadRequest, wantMessage: "invalid limit", wantIs: ErrBadRequest},
+		{name: "precondition", body: "", status: http.StatusPreconditionFailed, wantIs: ErrPreconditionFailed},
+		{name: "server", body: "", status: http.StatusBadGateway, wantIs: ErrServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			c, err := New(Config{ServerURL: server.URL, MaxRetries: -1})
+			assert.NoError(t, err)
+
+			err = c.DeleteSource(context.Background(), "bbc", "")
+
+			var e *Error
+			assert.True(t, errors.As(err, &e))
+			assert.Equal(t, tt.status, e.StatusCode)
+			assert.Equal(t, tt.wantMessage, e.Message)
+			assert.Equal(t, http.MethodDelete, e.Method)
+			assert.Equal(t, server.URL+"/v1/sources/bbc", e.URL)
+			assert.ErrorIs(t, err, tt.wantIs)
+			assert.True(t, IsStatus(err, tt.status))
+			assert.False(t, errors.Is(err, ErrNotFound))
+		})
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestTypes_JSON checks that the types of the client decode and encode again every field of the JSON of the server.
+func TestTypes_JSON(t *testing.T) {
+	date := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	news := entity.News{
+		Title: "Title", Description: "Description", Link: "https://bbc.com/1", Date: date, Source: "bbc",
+		Language: "en", Canonical: "https://bbc.com/1", GUID: "1", Authors: []string{"Jane Doe"},
+		Categories: []string{"World"}, Image: "https://bbc.com/1.jpg", Content: "Content",
+	}
+	retention := entity.Retention{MaxAge: entity.Interval(24 * time.Hour), MaxCount: 10}
+	tests := []struct {
+		name   string
+		server any
+		client any
+	}{
+		{name: "news", server: news, client: &News{}},
+		{name: "story", server: story.Story{Title: "Title", Description: "Description", Date: date, Sources: []string{"bbc"}, News: []entity.News{news}}, client: &Story{}},
+		{name: "scrape profile", server: entity.ScrapeProfile{
+			ItemSelector: "article", TitleSelector: "h2", TitleAttr: "title", LinkSelector: "a", LinkAttr: "href",
+			DescriptionSelector: "p", DescriptionAttr: "title", DateSelector: "time", DateAttr: "datetime", DateLayout: time.RFC3339,
+			BaseURL: "https://bbc.com", AuthorSelector: ".author", CategorySelector: ".tag", ImageSelector: "img", ImageAttr: "src", ContentSelector: ".body",
+		}, client: &ScrapeProfile{}},
+		{name: "retention", server: retention, client: &Retention{}},
+		{name: "source status", server: service.SourceStatus{
+			Source: "bbc", Interval: entity.Interval(time.Hour), LastRun: date, NextRun: date.Add(time.Hour), Failures: 1, LastError: "timeout", Running: true,
+		}, client: &SourceStatus{}},
+		{name: "retention report", server: service.RetentionReport{Started: date, DryRun: true, Sources: []service.SourceRetention{
+			{Source: "bbc", Retention: retention, Kept: 1, Deleted: []entity.Link{"https://bbc.com/1"}, Archived: []string{"2024-05-01.json"}, Error: "failed"},
+		}}, client: &RetentionReport{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := json.Marshal(tt.server)
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(want, tt.client))
+			got, err := json.Marshal(tt.client)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(want), string(got))
+		})
+	}
+}
@@ -0,0 +1,22 @@
+// Package v1 is the Go client of the HTTP API of the news aggregator server.
+//
+// A Client has a method for every endpoint described by the OpenAPI document the server
+// serves at /openapi.json: the /v1/sources resources, the news and stories of /news,
+// the fetch schedule and the retention of the stored news. The package is versioned with
+// the API, breaking changes of the API get a new package next to this one.
+//
+// Requests take a context, requests that can safely be repeated are retried with an
+// exponential backoff when the server fails with a 5xx status or cannot be reached.
+// Responses with an unexpected status are returned as an *Error, which matches the
+// sentinel errors like ErrNotFound with errors.Is:
+//
+//	aggregator, err := v1.New(v1.Config{ServerURL: "https://localhost:8443", CACertFile: "ca.crt"})
+//	...
+//	source, err := aggregator.Source(ctx, "bbc")
+//	if errors.Is(err, v1.ErrNotFound) {
+//		source, err = aggregator.CreateSource(ctx, v1.Source{Name: "bbc", URL: "https://feeds.bbci.co.uk/news/rss.xml"})
+//	}
+//
+// The entities of the requests and responses are declared by the package with the JSON
+// of the API, so the client depends on neither the server nor its internal packages.
+package v1
@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by an *Error of the status code with errors.Is.
+var (
+	ErrBadRequest         = errors.New("bad request")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrTooLarge           = errors.New("request entity too large")
+	ErrUnsupportedMedia   = errors.New("unsupported media type")
+	// ErrServer is matched by all 5xx status codes.
+	ErrServer = errors.New("server error")
+)
+
+var statusErrors = map[int]error{
+	http.StatusBadRequest:            ErrBadRequest,
+	http.StatusUnauthorized:          ErrUnauthorized,
+	http.StatusForbidden:             ErrForbidden,
+	http.StatusNotFound:              ErrNotFound,
+	http.StatusConflict:              ErrConflict,
+	http.StatusPreconditionFailed:    ErrPreconditionFailed,
+	http.StatusRequestEntityTooLarge: ErrTooLarge,
+	http.StatusUnsupportedMediaType:  ErrUnsupportedMedia,
+}
+
+// Error is a response of the server with an unexpected status code.
+type Error struct {
+	Method     string
+	URL        string
+	StatusCode int
+	// Message of the JSON error object of the response, or its text when it is not one.
+	Message string
+	// Body of the response, at most its first 64 KiB.
+	Body []byte
+	// RetryAfter of the Retry-After header of the response, zero when it has none.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	message := fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		message += ": " + e.Message
+	}
+	return message
+}
+
+// Is reports if the target is the sentinel error of the status code.
+func (e *Error) Is(target error) bool {
+	if target == ErrServer {
+		return e.StatusCode >= 500 && e.StatusCode <= 599
+	}
+	statusErr, ok := statusErrors[e.StatusCode]
+	return ok && statusErr == target
+}
+
+// IsStatus reports if the error is a response with the status code.
+func IsStatus(err error, statusCode int) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == statusCode
+}
+
+// responseError of the response to a request of the method to the URL.
+func responseError(method string, endpoint *url.URL, resp *http.Response) *Error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	e := &Error{
+		Method:     method,
+		URL:        endpoint.Redacted(),
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(body)),
+		Body:       body,
+	}
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+		e.Message = apiErr.Message
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		e.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return e
+}
@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// News of a source stored by the server.
+type News struct {
+	Title       string
+	Description string
+	Link        string
+	Date        time.Time
+	Source      string
+	// Language of the news as an ISO 639-1 code, empty when unknown.
+	Language string `json:",omitempty"`
+	// Canonical link of the news given by its feed or page.
+	Canonical  string   `json:",omitempty"`
+	GUID       string   `json:",omitempty"`
+	Authors    []string `json:",omitempty"`
+	Categories []string `json:",omitempty"`
+	Image      string   `json:",omitempty"`
+	Content    string   `json:",omitempty"`
+}
+
+// Story of near-duplicate news from different sources.
+type Story struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Date        time.Time `json:"date"`
+	// Sources publishing the story, in the order of its news.
+	Sources []string `json:"sources"`
+	News    []News   `json:"news"`
+}
+
+// NewsQuery of GET /news, the zero values are not sent.
+type NewsQuery struct {
+	// Sources of the news by name, all sources when empty.
+	Sources []string
+	// Keywords query with quoted phrases, AND, OR, NOT, -word, parentheses and field prefixes.
+	Keywords string
+	// DateStart and DateEnd of the news like 2024-05-01.
+	DateStart string
+	DateEnd   string
+	// SortBy date, source or relevance.
+	SortBy string
+	// SortOrder asc or desc.
+	SortOrder string
+	// Categories and Authors keep news of any of them.
+	Categories []string
+	Authors    []string
+	// Limit of the news of a page, the default limit of the server when zero.
+	Limit int
+	// Cursor of the page, the NextCursor of the previous page.
+	Cursor string
+}
+
+// NewsPage is a page of news with the total number of matching news.
+type NewsPage struct {
+	Items []News `json:"items"`
+	Total int    `json:"total"`
+	// NextCursor of the next page, empty on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// StoryPage is a page of news grouped into stories. The total and cursor count news, not stories.
+type StoryPage struct {
+	Items      []Story `json:"items"`
+	Total      int     `json:"total"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
+
+// News gets a page of the news matching the query.
+func (c *Client) News(ctx context.Context, query NewsQuery) (NewsPage, error) {
+	var page NewsPage
+	_, err := c.do(ctx, request{method: http.MethodGet, path: []string{"news"}, query: query.values()}, &page)
+	return page, err
+}
+
+// Stories gets a page of the news matching the query grouped into stories of near-duplicate news.
+func (c *Client) Stories(ctx context.Context, query NewsQuery) (StoryPage, error) {
+	values := query.values()
+	values.Set("group", "story")
+	var page StoryPage
+	_, err := c.do(ctx, request{method: http.MethodGet, path: []string{"news"}, query: values}, &page)
+	return page, err
+}
+
+// NewsURL of the news matching the query, for instance to link them.
+func (c *Client) NewsURL(query NewsQuery) string {
+	return c.endpoint([]string{"news"}, query.values()).String()
+}
+
+// values of the query parameters.
+func (q NewsQuery) values() url.Values {
+	values := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	set("sources", strings.Join(q.Sources, ","))
+	set("keywords", q.Keywords)
+	set("date-start", q.DateStart)
+	set("date-end", q.DateEnd)
+	set("sort-by", q.SortBy)
+	set("sort-order", q.SortOrder)
+	set("category", strings.Join(q.Categories, ","))
+	set("author", strings.Join(q.Authors, ","))
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	set("cursor", q.Cursor)
+	return values
+}
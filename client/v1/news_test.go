@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewsQuery_values(t *testing.T) {
+	tests := []struct {
+		name  string
+		query NewsQuery
+		want  string
+	}{
+		{name: "empty", query: NewsQuery{}, want: ""},
+		{
+			name: "all",
+			query: NewsQuery{
+				Sources:    []string{"bbc", "cnn"},
+				Keywords:   `"climate change" -sport`,
+				DateStart:  "2024-05-01",
+				DateEnd:    "2024-05-31",
+				SortBy:     "date",
+				SortOrder:  "desc",
+				Categories: []string{"World"},
+				Authors:    []string{"Jane Doe"},
+				Limit:      20,
+				Cursor:     "abc",
+			},
+			want: "author=Jane+Doe&category=World&cursor=abc&date-end=2024-05-31&date-start=2024-05-01" +
+				"&keywords=%22climate+change%22+-sport&limit=20&sort-by=date&sort-order=desc&sources=bbc%2Ccnn",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.query.values().Encode())
+		})
+	}
+}
+
+func TestClient_News(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/news", r.URL.Path)
+		assert.Equal(t, "bbc", r.URL.Query().Get("sources"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("group") == "story" {
+			_, _ = w.Write([]byte(`{"items":[{"title":"Title","sources":["bbc"],"news":[{"Title":"Title","Source":"bbc"}]}],"total":1}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"items":[{"Title":"Title","Link":"https://bbc.com/1","Source":"bbc"}],"total":3,"nextCursor":"next"}`))
+	}))
+	defer server.Close()
+	c, err := New(Config{ServerURL: server.URL})
+	assert.NoError(t, err)
+	query := NewsQuery{Sources: []string{"bbc"}, Limit: 1}
+
+	page, err := c.News(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, "next", page.NextCursor)
+	assert.Equal(t, []News{{Title: "Title", Link: "https://bbc.com/1", Source: "bbc"}}, page.Items)
+
+	stories, err := c.Stories(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Len(t, stories.Items, 1)
+	assert.Equal(t, []string{"bbc"}, stories.Items[0].Sources)
+
+	assert.Equal(t, server.URL+"/news?limit=1&sources=bbc", c.NewsURL(query))
+}
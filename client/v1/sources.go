@@ -0,0 +1,173 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ScrapeProfile of the CSS selectors of the news of an HTML page, given instead of a feed.
+// Attrs name the attribute holding the value, the text of the element when empty.
+type ScrapeProfile struct {
+	ItemSelector        string
+	TitleSelector       string `json:",omitempty"`
+	TitleAttr           string `json:",omitempty"`
+	LinkSelector        string `json:",omitempty"`
+	LinkAttr            string `json:",omitempty"`
+	DescriptionSelector string `json:",omitempty"`
+	DescriptionAttr     string `json:",omitempty"`
+	DateSelector        string `json:",omitempty"`
+	DateAttr            string `json:",omitempty"`
+	DateLayout          string `json:",omitempty"`
+	BaseURL             string `json:",omitempty"`
+	AuthorSelector      string `json:",omitempty"`
+	CategorySelector    string `json:",omitempty"`
+	ImageSelector       string `json:",omitempty"`
+	ImageAttr           string `json:",omitempty"`
+	ContentSelector     string `json:",omitempty"`
+}
+
+// Interval is a duration encoded in JSON as a Go duration string like "30m".
+type Interval time.Duration
+
+func (i Interval) String() string {
+	return time.Duration(i).String()
+}
+
+// MarshalJSON encodes the interval as a duration string.
+func (i Interval) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON decodes a duration string like "1h30m".
+func (i *Interval) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*i = Interval(d)
+	return nil
+}
+
+// Retention of the stored news of a source, the zero fields do not limit them.
+type Retention struct {
+	// MaxAge of the kept news by their date.
+	MaxAge Interval `json:",omitempty"`
+	// MaxCount of the newest kept news.
+	MaxCount int `json:",omitempty"`
+}
+
+// Source of news, the /v1/sources resource.
+type Source struct {
+	Name string `json:"name"`
+	// URL of the feed or the HTML page of the source.
+	URL    string         `json:"url"`
+	Scrape *ScrapeProfile `json:"scrape,omitempty"`
+	// Interval between fetches of the source, the default interval of the server when zero.
+	Interval Interval `json:"interval,omitempty"`
+	// Language of the news of the source as an ISO 639-1 code, the language of its feed when empty.
+	Language  string     `json:"language,omitempty"`
+	Retention *Retention `json:"retention,omitempty"`
+	// Version of the source, set by the server and ignored in requests.
+	Version int64 `json:"version,omitempty"`
+	// ETag of the version of the source, the If-Match of a change of exactly this version.
+	ETag string `json:"-"`
+}
+
+// SourcePatch changes the given fields of a source, the nil ones are kept.
+type SourcePatch struct {
+	URL       *string
+	Scrape    *ScrapeProfile
+	Interval  *Interval
+	Language  *string
+	Retention *Retention
+	// Reset settings to their defaults by the names of their JSON fields, like "language".
+	Reset []string
+}
+
+// MarshalJSON encodes the patch as a JSON merge patch, the reset settings are null.
+func (p SourcePatch) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]any)
+	for _, name := range p.Reset {
+		fields[name] = nil
+	}
+	if p.URL != nil {
+		fields["url"] = p.URL
+	}
+	if p.Scrape != nil {
+		fields["scrape"] = p.Scrape
+	}
+	if p.Interval != nil {
+		fields["interval"] = p.Interval
+	}
+	if p.Language != nil {
+		fields["language"] = p.Language
+	}
+	if p.Retention != nil {
+		fields["retention"] = p.Retention
+	}
+	return json.Marshal(fields)
+}
+
+// Sources lists all sources.
+func (c *Client) Sources(ctx context.Context) ([]Source, error) {
+	var sources []Source
+	_, err := c.do(ctx, request{method: http.MethodGet, path: []string{"v1", "sources"}}, &sources)
+	return sources, err
+}
+
+// Source gets the source with the name, ErrNotFound when there is none.
+func (c *Client) Source(ctx context.Context, name string) (Source, error) {
+	return c.sourceRequest(ctx, request{method: http.MethodGet, path: sourcePath(name)})
+}
+
+// CreateSource creates the source with its settings, ErrConflict when its name is taken.
+func (c *Client) CreateSource(ctx context.Context, source Source) (Source, error) {
+	return c.sourceRequest(ctx, request{method: http.MethodPost, path: []string{"v1", "sources"}, body: source})
+}
+
+// ReplaceSource replaces the URL and all settings of the source with the name of the given one,
+// settings it does not have are reset to their defaults. The source has to match the If-Match
+// ETag when it is given, ErrPreconditionFailed otherwise.
+func (c *Client) ReplaceSource(ctx context.Context, source Source, ifMatch string) (Source, error) {
+	return c.sourceRequest(ctx, request{method: http.MethodPut, path: sourcePath(source.Name), ifMatch: ifMatch, body: source})
+}
+
+// PatchSource changes the fields of the patch of the source with the name. The source has to match
+// the If-Match ETag when it is given, ErrPreconditionFailed otherwise.
+func (c *Client) PatchSource(ctx context.Context, name string, patch SourcePatch, ifMatch string) (Source, error) {
+	return c.sourceRequest(ctx, request{
+		method:      http.MethodPatch,
+		path:        sourcePath(name),
+		ifMatch:     ifMatch,
+		contentType: "application/merge-patch+json",
+		body:        patch,
+	})
+}
+
+// DeleteSource removes the source with the name, its stored news are kept. The source has to match
+// the If-Match ETag when it is given, ErrPreconditionFailed otherwise.
+func (c *Client) DeleteSource(ctx context.Context, name, ifMatch string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: sourcePath(name), ifMatch: ifMatch}, nil)
+	return err
+}
+
+// sourceRequest responded with a source and its ETag.
+func (c *Client) sourceRequest(ctx context.Context, r request) (Source, error) {
+	var source Source
+	header, err := c.do(ctx, r, &source)
+	if err != nil {
+		return Source{}, err
+	}
+	source.ETag = header.Get("ETag")
+	return source, nil
+}
+
+func sourcePath(name string) []string {
+	return []string{"v1", "sources", name}
+}
@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"context"
+	"net/http/httptest"
+	"news-aggregator/server/handlers"
+	"news-aggregator/server/managers"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newSourcesClient of a server of the API with the sources of a temporary file.
+func newSourcesClient(t *testing.T) *Client {
+	sources := handlers.SourceHandler{SourceManager: managers.CreateSourceFolder(filepath.Join(t.TempDir(), "sources.json"))}
+	server := httptest.NewServer(handlers.API{Sources: sources}.Handler())
+	t.Cleanup(server.Close)
+	c, err := New(Config{ServerURL: server.URL, MaxRetries: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestClient_Sources(t *testing.T) {
+	ctx := context.Background()
+	c := newSourcesClient(t)
+
+	created, err := c.CreateSource(ctx, Source{
+		Name:      "bbc",
+		URL:       "https://feeds.bbci.co.uk/news/rss.xml",
+		Interval:  Interval(30 * time.Minute),
+		Language:  "EN",
+		Retention: &Retention{MaxCount: 100},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "en", created.Language)
+	assert.Equal(t, Interval(30*time.Minute), created.Interval)
+	assert.NotEmpty(t, created.ETag)
+
+	_, err = c.CreateSource(ctx, Source{Name: "bbc", URL: "https://bbc.com"})
+	assert.ErrorIs(t, err, ErrConflict)
+
+	got, err := c.Source(ctx, "bbc")
+	assert.NoError(t, err)
+	assert.Equal(t, created, got)
+
+	url := "https://bbc.com/news"
+	patched, err := c.PatchSource(ctx, "bbc", SourcePatch{URL: &url, Reset: []string{"language", "retention"}}, got.ETag)
+	assert.NoError(t, err)
+	assert.Equal(t, url, patched.URL)
+	assert.Empty(t, patched.Language)
+	assert.Nil(t, patched.Retention)
+	assert.Equal(t, Interval(30*time.Minute), patched.Interval)
+	assert.Greater(t, patched.Version, got.Version)
+
+	_, err = c.ReplaceSource(ctx, Source{Name: "bbc", URL: "https://bbc.co.uk"}, got.ETag)
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+	replaced, err := c.ReplaceSource(ctx, Source{Name: "bbc", URL: "https://bbc.co.uk"}, patched.ETag)
+	assert.NoError(t, err)
+	assert.Equal(t, Interval(0), replaced.Interval)
+
+	sources, err := c.Sources(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bbc"}, sourceNames(sources))
+
+	_, err = c.CreateSource(ctx, Source{Name: "bbc news", URL: "https://bbc.com"})
+	assert.ErrorIs(t, err, ErrBadRequest)
+
+	assert.ErrorIs(t, c.DeleteSource(ctx, "bbc", patched.ETag), ErrPreconditionFailed)
+	assert.NoError(t, c.DeleteSource(ctx, "bbc", replaced.ETag))
+	assert.ErrorIs(t, c.DeleteSource(ctx, "bbc", ""), ErrNotFound)
+	_, err = c.Source(ctx, "bbc")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSourcePatch_MarshalJSON(t *testing.T) {
+	language := "uk"
+	tests := []struct {
+		name  string
+		patch SourcePatch
+		want  string
+	}{
+		{name: "empty", patch: SourcePatch{}, want: `{}`},
+		{name: "set", patch: SourcePatch{Language: &language, Retention: &Retention{MaxCount: 10}}, want: `{"language":"uk","retention":{"MaxCount":10}}`},
+		{name: "reset", patch: SourcePatch{Reset: []string{"interval", "scrape"}}, want: `{"interval":null,"scrape":null}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.patch.MarshalJSON()
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func sourceNames(sources []Source) []string {
+	names := make([]string, 0, len(sources))
+	for _, source := range sources {
+		names = append(names, source.Name)
+	}
+	return names
+}
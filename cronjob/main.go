@@ -1,30 +1,101 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"news-aggregator/server/managers"
 	"news-aggregator/server/service"
+	"time"
 )
 
 func main() {
+	mode := flag.String("mode", "fetch", "Job to run: 'fetch' the news of all sources or apply the 'retention' to the stored news. Default is 'fetch'.")
 	pathToSourcesFile := flag.String("path-to-source", "../sources.json", "Path to the file containing news sources. Default is 'server/sources.json'.")
 	pathToNews := flag.String("news-folder", "../server-news/", "Path to the folder where news files are stored. Default is 'server-news/'.")
+	workers := flag.Int("workers", 4, "Number of sources fetched concurrently. Default is 4.")
+	timeout := flag.Duration("timeout", 30*time.Second, "Timeout for fetching a single source. Default is 30s.")
+	pathToFeedCache := flag.String("feed-cache", "../feed_cache.json", "Path to the file storing ETag, Last-Modified and content hash of fetched feeds. Default is '../feed_cache.json'.")
+	maxAge := flag.Duration("retention-max-age", 0, "Default maximum age of the stored news of a source, 0 keeps them. Default is 0.")
+	maxCount := flag.Int("retention-max-count", 0, "Default maximum number of stored news of a source, 0 keeps them all. Default is 0.")
+	archiveAfter := flag.Duration("archive-after", 0, "Age of the daily news files compacted into monthly gzip archives, 0 compacts none. Default is 0.")
+	dryRun := flag.Bool("dry-run", false, "Report the news the retention would delete and the files it would archive without changing them.")
 
 	flag.Parse()
 
+	// The files are shared with the server, a crash of either may have left them corrupt.
+	quarantined, err := managers.Recovery{
+		PathToSources:   *pathToSourcesFile,
+		PathToNews:      *pathToNews,
+		PathToFeedCache: *pathToFeedCache,
+	}.Run()
+	if err != nil {
+		log.Fatalf("Error recovering files: %v", err)
+	}
+	if len(quarantined) > 0 {
+		log.Printf("Quarantined %d corrupt files: %v", len(quarantined), quarantined)
+	}
 	sourceFolder := managers.CreateSourceFolder(*pathToSourcesFile)
 	newsFolder := managers.CreateNewsFolder(*pathToNews)
-	urlFeed := managers.UrlFeed{}
+
+	switch *mode {
+	case "fetch":
+		fetch(sourceFolder, newsFolder, *pathToFeedCache, *workers, *timeout)
+	case "retention":
+		retention := service.Retention{
+			SourceManager: sourceFolder,
+			NewsManager:   newsFolder,
+			MaxAge:        *maxAge,
+			MaxCount:      *maxCount,
+			ArchiveAfter:  *archiveAfter,
+		}
+		prune(retention, *dryRun)
+	default:
+		log.Fatalf("Unknown mode %q, expected 'fetch' or 'retention'", *mode)
+	}
+}
+
+// fetch the news of all sources into the news folder.
+func fetch(sourceFolder managers.SourceManager, newsFolder managers.NewsManager, pathToFeedCache string, workers int, timeout time.Duration) {
+	urlFeed := managers.UrlFeed{Cache: managers.CreateFeedCache(pathToFeedCache)}
 	fetcher := service.Fetch{
 		SourceManager: sourceFolder,
 		NewsManager:   newsFolder,
 		FeedManager:   urlFeed,
+		Workers:       workers,
+		Timeout:       timeout,
 	}
 
-	err := fetcher.UpdateNews()
+	report, err := fetcher.UpdateNews(context.Background())
+	for _, s := range report.Sources {
+		log.Printf("Source %s: fetched %d, new %d, duplicate %d, not modified %t, error: %q",
+			s.Source, s.Fetched, s.New, s.Duplicate, s.NotModified, s.Error)
+	}
 	if err != nil {
 		log.Printf("Error fetching news: %v", err)
 	}
+}
 
+// prune the stored news by the retention, a dry run lists what would be deleted.
+func prune(retention service.Retention, dryRun bool) {
+	report, err := retention.Run(context.Background(), dryRun)
+	verb := "deleted"
+	if dryRun {
+		verb = "would delete"
+	}
+	for _, s := range report.Sources {
+		log.Printf("Source %s: kept %d, %s %d, archived %d files, error: %q",
+			s.Source, s.Kept, verb, len(s.Deleted), len(s.Archived), s.Error)
+		if dryRun {
+			for _, link := range s.Deleted {
+				log.Printf("Source %s: would delete %s", s.Source, link)
+			}
+			for _, file := range s.Archived {
+				log.Printf("Source %s: would archive %s", s.Source, file)
+			}
+		}
+	}
+	if err != nil {
+		log.Printf("Error applying retention: %v", err)
+	}
 }
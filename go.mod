@@ -4,11 +4,14 @@ go 1.22
 
 require (
 	github.com/PuerkitoBio/goquery v1.9.2
+	github.com/blevesearch/snowballstem v0.9.0
 	github.com/golang/mock v1.6.0
 	github.com/mmcdole/gofeed v1.2.0
 	github.com/reiver/go-porterstemmer v1.0.1
 	github.com/stretchr/testify v1.9.0
 	github.com/wk8/go-ordered-map v1.0.0
+	go.etcd.io/bbolt v1.3.10
+	golang.org/x/sys v0.19.0
 )
 
 require (
@@ -3,9 +3,13 @@ package internal
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"news-aggregator/internal/entity"
+	"news-aggregator/internal/filters"
 	"news-aggregator/internal/initializers"
+	"news-aggregator/internal/parser"
+	"news-aggregator/internal/search"
 	"news-aggregator/internal/sort"
 	t "news-aggregator/internal/template"
 	"os"
@@ -18,23 +22,26 @@ type aggregator struct {
 	Sources     string
 	NewsFilters []initializers.NewsFilter
 	SortOptions sort.Options
+	// Group of the printed news, "story" groups near-duplicate news into stories.
+	Group string
 }
 
 // NewAggregator creates a new instance of an aggregator with the given resources, sources,
-// news filters, and sorting options.
-func NewAggregator(news map[string][]string, sources string, newsFilters []initializers.NewsFilter, sortParams sort.Options) Aggregate {
+// news filters, sorting options and grouping of the printed news.
+func NewAggregator(news map[string][]string, sources string, newsFilters []initializers.NewsFilter, sortParams sort.Options, group string) Aggregate {
 	return &aggregator{
 		Resources:   news,
 		Sources:     sources,
 		NewsFilters: newsFilters,
 		SortOptions: sortParams,
+		Group:       group,
 	}
 }
 
 // Aggregate aggregates news from the specified Sources and applies NewsFilters.
 type Aggregate interface {
 	Aggregate() ([]entity.News, error)
-	Print(news []entity.News, keywords string) error
+	Print(w io.Writer, news []entity.News, keywords string, output t.Output) error
 }
 
 // Aggregate news from the specified Sources and applies NewsFilters.
@@ -45,16 +52,31 @@ func (a *aggregator) Aggregate() ([]entity.News, error) {
 		return nil, err
 	}
 	news = a.applyFilters(news)
+	if strings.EqualFold(a.SortOptions.Criterion, "relevance") {
+		return a.rank(news), nil
+	}
 	return a.SortOptions.Sort(news), nil
 }
 
-// Print news according to the created template
-func (a *aggregator) Print(news []entity.News, keywords string) error {
+// rank news by their relevance to the keyword query, the most relevant first
+// unless the order is descending. Without keywords the news are kept in order.
+func (a *aggregator) rank(news []entity.News) []entity.News {
+	for _, filter := range a.NewsFilters {
+		if query, ok := filter.(*filters.Query); ok {
+			return search.Rank(news, query, strings.EqualFold(a.SortOptions.Order, "desc"))
+		}
+	}
+	return news
+}
+
+// Print news to the writer in the format of the output, or with its template file.
+func (a *aggregator) Print(w io.Writer, news []entity.News, keywords string, output t.Output) error {
 	template := t.Data{
 		News: news,
 		Header: t.Header{
 			Sources:     a.Sources,
 			SortOptions: a.SortOptions,
+			Group:       a.Group,
 		},
 	}
 	if len(a.NewsFilters) != 0 {
@@ -65,15 +87,9 @@ func (a *aggregator) Print(news []entity.News, keywords string) error {
 		filtersInfo = " filters:" + filtersInfo
 		template.Header.Filters = filtersInfo
 	}
-	tmpl, err := template.Create(keywords)
+	err := template.Render(w, keywords, output)
 	if err != nil {
-		log.Printf("Error creating template: %v", err)
-		return err
-	}
-	data := template.Prepare()
-	err = tmpl.ExecuteTemplate(os.Stdout, "news", data)
-	if err != nil {
-		log.Printf("Error executing template: %v", err)
+		log.Printf("Error rendering news: %v", err)
 		return err
 	}
 	return nil
@@ -125,12 +141,23 @@ func (a *aggregator) getResourceNews(path entity.PathToFile) ([]entity.News, err
 	}(file)
 	var articles []entity.News
 	if err := json.NewDecoder(file).Decode(&articles); err != nil {
-		log.Printf("Error decoding file %s: %v", path, err)
-		return nil, err
+		log.Printf("File %s is not a stored news file, trying feed parsers: %v", path, err)
+		return parseFeedFile(path)
 	}
 	return articles, nil
 }
 
+// parseFeedFile reads a raw feed file placed among stored news,
+// picking the parser from the registry.
+func parseFeedFile(path entity.PathToFile) ([]entity.News, error) {
+	p, err := parser.GetFileParser(path)
+	if err != nil {
+		log.Printf("Error getting file parser for %s: %v", path, err)
+		return nil, err
+	}
+	return p.Parse()
+}
+
 // applyFilters applies the configured NewsFilters to the aggregated news.
 func (a *aggregator) applyFilters(news []entity.News) []entity.News {
 	for _, current := range a.NewsFilters {
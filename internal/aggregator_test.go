@@ -4,6 +4,7 @@ import (
 	"news-aggregator/internal/entity"
 	"news-aggregator/internal/filters"
 	"news-aggregator/internal/initializers"
+	"news-aggregator/internal/sort"
 	"reflect"
 	"testing"
 	"time"
@@ -58,6 +59,21 @@ func TestAggregate(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregate_RawFeedFile(t *testing.T) {
+	a := &aggregator{
+		Resources: map[string][]string{"guardian": {"testdata/news_atom.xml"}},
+		Sources:   "guardian",
+	}
+	got, err := a.Aggregate()
+	if err != nil {
+		t.Fatalf("Aggregate() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Source != "The Guardian World" {
+		t.Errorf("Aggregate() = %v, want 2 news parsed from the Atom feed", got)
+	}
+}
+
 func TestAggregator_applyFilters(t *testing.T) {
 	type fields struct {
 		Sources     string
@@ -121,3 +137,37 @@ func TestAggregator_applyFilters(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregator_rank(t *testing.T) {
+	query, err := filters.ParseQuery("bridge OR ship")
+	if err != nil {
+		t.Fatal(err)
+	}
+	news := []entity.News{
+		{Title: "Weather", Link: "1"},
+		{Title: "Ship removed", Description: "The ship that struck the bridge is removed.", Link: "2"},
+		{Title: "Bridge reopens", Link: "3"},
+	}
+	tests := []struct {
+		name    string
+		filters []initializers.NewsFilter
+		order   string
+		want    []entity.Link
+	}{
+		{"most relevant first", []initializers.NewsFilter{query}, "ASC", []entity.Link{"2", "3", "1"}},
+		{"least relevant first", []initializers.NewsFilter{query}, "DESC", []entity.Link{"1", "3", "2"}},
+		{"without keywords", nil, "ASC", []entity.Link{"1", "2", "3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &aggregator{NewsFilters: tt.filters, SortOptions: sort.Options{Criterion: "relevance", Order: tt.order}}
+			var got []entity.Link
+			for _, item := range a.rank(append([]entity.News(nil), news...)) {
+				got = append(got, item.Link)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rank() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package canonical
+
+import (
+	"net/url"
+	"news-aggregator/internal/entity"
+	"path"
+	"strings"
+)
+
+// trackingParameters dropped from the query besides the utm_* ones, in lower case.
+var trackingParameters = map[string]bool{
+	"fbclid":      true,
+	"gclid":       true,
+	"gclsrc":      true,
+	"dclid":       true,
+	"msclkid":     true,
+	"yclid":       true,
+	"igshid":      true,
+	"mc_cid":      true,
+	"mc_eid":      true,
+	"_ga":         true,
+	"_gl":         true,
+	"ocid":        true,
+	"cmpid":       true,
+	"ref":         true,
+	"ref_src":     true,
+	"at_medium":   true,
+	"at_campaign": true,
+	"amp":         true,
+	"outputtype":  true,
+}
+
+// hostPrefixes of the mobile and AMP versions of a site.
+var hostPrefixes = []string{"www.", "m.", "mobile.", "amp."}
+
+// ampCacheSuffix of the hosts of the Google AMP cache.
+const ampCacheSuffix = ".cdn.ampproject.org"
+
+// URL returns the canonical form of an absolute http or https link:
+// the scheme is https, the host is lower case without the www, mobile or AMP prefix
+// and default port, AMP path segments and the trailing slash are removed,
+// tracking parameters and the fragment are dropped and the other parameters sorted.
+// Other links are returned trimmed and otherwise unchanged.
+func URL(link string) string {
+	link = strings.TrimSpace(link)
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" || !isHTTP(u.Scheme) {
+		return link
+	}
+	u.Scheme = "https"
+	u.Host = host(u.Hostname(), u.Port())
+	if strings.HasSuffix(u.Host, ampCacheSuffix) {
+		if cached, ok := fromAMPCache(u.Path); ok {
+			u.Host = host(cached.Hostname(), cached.Port())
+			u.Path = cached.Path
+		}
+	}
+	u.Path = cleanPath(u.Path)
+	u.RawPath = ""
+	u.RawQuery = cleanQuery(u.Query())
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.User = nil
+	return u.String()
+}
+
+// Link of the news identifying it across sources: the canonical form of the
+// canonical URL it declares or else of its link.
+func Link(item entity.News) entity.Link {
+	if item.Canonical != "" {
+		return entity.Link(URL(string(item.Canonical)))
+	}
+	return entity.Link(URL(string(item.Link)))
+}
+
+// IsURL reports if the value is an absolute http or https URL.
+func IsURL(value string) bool {
+	u, err := url.Parse(strings.TrimSpace(value))
+	return err == nil && u.Host != "" && isHTTP(u.Scheme)
+}
+
+func isHTTP(scheme string) bool {
+	return strings.EqualFold(scheme, "http") || strings.EqualFold(scheme, "https")
+}
+
+// host in lower case without the trailing dot, the default port and a mobile or AMP prefix.
+func host(name, port string) string {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	for _, prefix := range hostPrefixes {
+		// The prefix is kept when the rest is not a domain of its own, like www.com.
+		if rest := strings.TrimPrefix(name, prefix); rest != name && strings.Contains(rest, ".") {
+			name = rest
+			break
+		}
+	}
+	if port != "" && port != "80" && port != "443" {
+		return name + ":" + port
+	}
+	return name
+}
+
+// fromAMPCache extracts the original URL of a page served by the AMP cache,
+// whose paths look like /c/s/example.com/article or /v/s/example.com/article.
+func fromAMPCache(p string) (*url.URL, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(p, "/"), "/", 3)
+	if len(parts) < 2 || (parts[0] != "c" && parts[0] != "v") {
+		return nil, false
+	}
+	rest := strings.Join(parts[1:], "/")
+	rest = strings.TrimPrefix(rest, "s/")
+	u, err := url.Parse("https://" + rest)
+	if err != nil || u.Host == "" {
+		return nil, false
+	}
+	return u, true
+}
+
+// cleanPath resolves dot segments and drops AMP segments and the trailing slash.
+func cleanPath(p string) string {
+	if p == "" || p == "/" {
+		return "/"
+	}
+	segments := strings.Split(path.Clean("/"+p), "/")[1:]
+	if len(segments) > 0 && segments[0] == "amp" {
+		segments = segments[1:]
+	}
+	if n := len(segments); n > 0 && segments[n-1] == "amp" {
+		segments = segments[:n-1]
+	}
+	if n := len(segments); n > 0 {
+		last := segments[n-1]
+		last = strings.Replace(last, ".amp.", ".", 1)
+		segments[n-1] = strings.TrimSuffix(last, ".amp")
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// cleanQuery without tracking parameters, encoded in the order of the parameter names.
+func cleanQuery(values url.Values) string {
+	for name := range values {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "utm_") || trackingParameters[lower] {
+			values.Del(name)
+		}
+	}
+	return values.Encode()
+}
@@ -0,0 +1,55 @@
+package canonical
+
+import (
+	"news-aggregator/internal/entity"
+	"testing"
+)
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{name: "Canonical", link: "https://example.com/news/1", want: "https://example.com/news/1"},
+		{name: "Tracking parameters", link: "https://example.com/news/1?utm_source=rss&utm_Medium=feed&id=7&fbclid=abc", want: "https://example.com/news/1?id=7"},
+		{name: "Sorted parameters", link: "https://example.com/news?page=2&id=7", want: "https://example.com/news?id=7&page=2"},
+		{name: "Scheme and host", link: "HTTP://WWW.Example.COM:80/news/1", want: "https://example.com/news/1"},
+		{name: "Trailing slash", link: "https://example.com/news/1/", want: "https://example.com/news/1"},
+		{name: "Root", link: "https://example.com", want: "https://example.com/"},
+		{name: "Dot segments", link: "https://example.com/news/./sport/../1", want: "https://example.com/news/1"},
+		{name: "Fragment", link: "https://example.com/news/1#comments", want: "https://example.com/news/1"},
+		{name: "Mobile host", link: "https://m.example.com/news/1", want: "https://example.com/news/1"},
+		{name: "AMP host", link: "https://amp.example.com/news/1", want: "https://example.com/news/1"},
+		{name: "AMP segment", link: "https://example.com/news/1/amp/", want: "https://example.com/news/1"},
+		{name: "AMP prefix", link: "https://example.com/amp/news/1", want: "https://example.com/news/1"},
+		{name: "AMP extension", link: "https://example.com/news/1.amp.html", want: "https://example.com/news/1.html"},
+		{name: "AMP parameter", link: "https://example.com/news/1?amp=1", want: "https://example.com/news/1"},
+		{name: "AMP cache", link: "https://example-com.cdn.ampproject.org/c/s/www.example.com/news/1/amp", want: "https://example.com/news/1"},
+		{name: "Other port", link: "https://example.com:8443/news/1", want: "https://example.com:8443/news/1"},
+		{name: "Short domain", link: "https://www.com/news", want: "https://www.com/news"},
+		{name: "Relative", link: " /news/1?utm_source=rss ", want: "/news/1?utm_source=rss"},
+		{name: "Not a URL", link: "urn:uuid:1225c695", want: "urn:uuid:1225c695"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := URL(tt.link); got != tt.want {
+				t.Errorf("URL(%q) = %q, want %q", tt.link, got, tt.want)
+			}
+			if got := URL(URL(tt.link)); got != tt.want {
+				t.Errorf("URL() is not idempotent for %q: %q", tt.link, got)
+			}
+		})
+	}
+}
+
+func TestLink(t *testing.T) {
+	item := entity.News{Link: "http://example.com/news/1?utm_source=rss"}
+	if got, want := Link(item), entity.Link("https://example.com/news/1"); got != want {
+		t.Errorf("Link() = %q, want %q", got, want)
+	}
+	item.Canonical = "https://www.example.com/articles/1/"
+	if got, want := Link(item), entity.Link("https://example.com/articles/1"); got != want {
+		t.Errorf("Link() with a canonical URL = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,3 @@
+// Package canonical normalises article URLs so that the same article is recognised
+// whatever tracking parameters, scheme, mobile or AMP host it was linked with.
+package canonical
@@ -1,6 +1,7 @@
 // Package entity define the structure of news article and resource models.
 // Includes structures like News, which represent a single news article with attributes like
-// Title, Description, Link and Date. Additionally, it includes the Source structure,
-// which encapsulates information about the news resource, including its SourceName,
-// PathToFile.
+// Title, Description, Link, Date, Language, its Canonical URL, GUID, Authors, Categories,
+// Image and Content. Additionally, it includes the Source structure, which encapsulates
+// information about the news resource, including its SourceName, PathToFile, an optional
+// ScrapeProfile for HTML pages, the fetch Interval, its Language and the Retention of its news.
 package entity
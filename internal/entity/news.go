@@ -21,11 +21,27 @@ func (t Title) String() string {
 	return string(t)
 }
 
-// News article structure with title, description, link, and date.
+// News article structure with title, description, link, date and the optional metadata
+// the feed gives, such as authors, categories, an image and the full content.
 type News struct {
 	Title       Title
 	Description Description
 	Link        Link
 	Date        time.Time
 	Source      string
+	// Language of the news as an ISO 639-1 code like "en", empty when not known.
+	Language string `json:",omitempty"`
+	// Canonical URL the feed or page declares for the article, empty when it declares none
+	// or it is the Link.
+	Canonical Link `json:",omitempty"`
+	// GUID identifying the news within its feed, empty when the feed gives none.
+	GUID string `json:",omitempty"`
+	// Authors of the news by name.
+	Authors []string `json:",omitempty"`
+	// Categories or tags of the news.
+	Categories []string `json:",omitempty"`
+	// Image illustrating the news.
+	Image Link `json:",omitempty"`
+	// Content of the news as given by the feed, usually HTML.
+	Content string `json:",omitempty"`
 }
@@ -1,5 +1,10 @@
 package entity
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // SourceName represents the name of a news source.
 type SourceName string
 
@@ -8,4 +13,79 @@ type PathToFile string
 type Source struct {
 	Name       SourceName
 	PathToFile PathToFile
+	Scrape     *ScrapeProfile `json:",omitempty"`
+	Interval   Interval       `json:",omitempty"`
+	// Language of the news of the source, overriding the language given by its feed.
+	Language string `json:",omitempty"`
+	// Retention of the stored news of the source, nil keeps the default retention.
+	Retention *Retention `json:",omitempty"`
+	// Version of the source, incremented by every change.
+	Version int64 `json:",omitempty"`
+}
+
+// Retention limits the stored news of a source, zero values keep all news.
+type Retention struct {
+	// MaxAge of the news, encoded in JSON as a duration string like "720h".
+	MaxAge Interval `json:",omitempty"`
+	// MaxCount of the newest news kept.
+	MaxCount int `json:",omitempty"`
+}
+
+// IsZero reports if the retention keeps all news.
+func (r Retention) IsZero() bool {
+	return r.MaxAge == 0 && r.MaxCount == 0
+}
+
+// Interval between fetches of a source, zero means the default interval of the scheduler.
+// It is encoded in JSON as a duration string like "15m".
+type Interval time.Duration
+
+func (i Interval) String() string {
+	return time.Duration(i).String()
+}
+
+// MarshalJSON encodes the interval as a duration string.
+func (i Interval) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON decodes the interval from a duration string.
+func (i *Interval) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*i = Interval(d)
+	return nil
+}
+
+// ScrapeProfile describes how news are extracted from an HTML page of a source.
+// Selectors are CSS selectors evaluated inside every item matched by ItemSelector;
+// an empty selector refers to the item itself. When an attribute is set its value
+// is used, otherwise the text of the selected element. Authors, categories, the image
+// and the content are only scraped when their selector is set.
+type ScrapeProfile struct {
+	ItemSelector        string
+	TitleSelector       string `json:",omitempty"`
+	TitleAttr           string `json:",omitempty"`
+	LinkSelector        string `json:",omitempty"`
+	LinkAttr            string `json:",omitempty"`
+	DescriptionSelector string `json:",omitempty"`
+	DescriptionAttr     string `json:",omitempty"`
+	DateSelector        string `json:",omitempty"`
+	DateAttr            string `json:",omitempty"`
+	DateLayout          string `json:",omitempty"`
+	BaseURL             string `json:",omitempty"`
+	// AuthorSelector and CategorySelector select every author and category of the item.
+	AuthorSelector   string `json:",omitempty"`
+	CategorySelector string `json:",omitempty"`
+	// ImageSelector selects the image of the item, read from ImageAttr or "src".
+	ImageSelector string `json:",omitempty"`
+	ImageAttr     string `json:",omitempty"`
+	// ContentSelector selects the element whose inner HTML is the content of the item.
+	ContentSelector string `json:",omitempty"`
 }
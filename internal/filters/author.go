@@ -0,0 +1,31 @@
+package filters
+
+import (
+	"news-aggregator/internal/entity"
+	"strings"
+)
+
+// Author filters news by any of the authors, compared case-insensitively.
+type Author struct {
+	Authors []string
+}
+
+// Filter news by any of the authors.
+func (a *Author) Filter(news []entity.News) []entity.News {
+	var filtered []entity.News
+	for _, item := range news {
+		if a.Match(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// Match reports if the news is by any of the authors.
+func (a *Author) Match(item entity.News) bool {
+	return containsAny(item.Authors, a.Authors)
+}
+
+func (a *Author) String() string {
+	return " author=" + strings.Join(a.Authors, ",")
+}
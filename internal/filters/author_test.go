@@ -0,0 +1,32 @@
+package filters
+
+import (
+	"news-aggregator/internal/entity"
+	"reflect"
+	"testing"
+)
+
+func TestAuthor_Filter(t *testing.T) {
+	news := []entity.News{
+		{Title: "Rates held", Authors: []string{"Jane Doe", "John Roe"}},
+		{Title: "Cup final", Authors: []string{"Ann Smith"}},
+		{Title: "No authors"},
+	}
+	tests := []struct {
+		name    string
+		authors []string
+		want    []entity.News
+	}{
+		{name: "should filter news of an author ignoring case", authors: []string{"john roe"}, want: news[:1]},
+		{name: "should filter news of any of the authors", authors: []string{"Ann Smith", "Jane Doe"}, want: news[:2]},
+		{name: "should return nil without matching news", authors: []string{"Jane"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Author{Authors: tt.authors}
+			if got := a.Filter(news); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
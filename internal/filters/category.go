@@ -0,0 +1,43 @@
+package filters
+
+import (
+	"news-aggregator/internal/entity"
+	"strings"
+)
+
+// Category filters news with any of the categories, compared case-insensitively.
+type Category struct {
+	Categories []string
+}
+
+// Filter news with any of the categories.
+func (c *Category) Filter(news []entity.News) []entity.News {
+	var filtered []entity.News
+	for _, item := range news {
+		if c.Match(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// Match reports if the news has any of the categories.
+func (c *Category) Match(item entity.News) bool {
+	return containsAny(item.Categories, c.Categories)
+}
+
+func (c *Category) String() string {
+	return " category=" + strings.Join(c.Categories, ",")
+}
+
+// containsAny reports if the values contain any of the wanted ones, ignoring case.
+func containsAny(values, wanted []string) bool {
+	for _, value := range values {
+		for _, w := range wanted {
+			if strings.EqualFold(strings.TrimSpace(value), w) {
+				return true
+			}
+		}
+	}
+	return false
+}
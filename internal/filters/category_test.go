@@ -0,0 +1,32 @@
+package filters
+
+import (
+	"news-aggregator/internal/entity"
+	"reflect"
+	"testing"
+)
+
+func TestCategory_Filter(t *testing.T) {
+	news := []entity.News{
+		{Title: "Rates held", Categories: []string{"Economy", "Banks"}},
+		{Title: "Cup final", Categories: []string{"Sport"}},
+		{Title: "No categories"},
+	}
+	tests := []struct {
+		name       string
+		categories []string
+		want       []entity.News
+	}{
+		{name: "should filter news of a category ignoring case", categories: []string{"economy"}, want: news[:1]},
+		{name: "should filter news of any of the categories", categories: []string{"sport", "banks"}, want: news[:2]},
+		{name: "should return nil without matching news", categories: []string{"weather"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Category{Categories: tt.categories}
+			if got := c.Filter(news); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,3 +1,3 @@
 // Package filters provides API for filtering news data based on input parameters.
-// Includes filtering by keywords and date range.
+// Includes filtering by keywords, date range, categories and authors.
 package filters
@@ -17,18 +17,29 @@ func (k *Keyword) Filter(news []entity.News) []entity.News {
 	var filtered []entity.News
 	keywords := getStemKeywords(k)
 	for _, item := range news {
-		titles := strings.Split(strings.ToLower(string(item.Title)), " ")
-		description := strings.Split(strings.ToLower(string(item.Description)), " ")
-		for _, stemmedKeyword := range keywords {
-			if slices.Contains(titles, stemmedKeyword) || slices.Contains(description, stemmedKeyword) {
-				filtered = append(filtered, item)
-				break
-			}
+		if matchKeywords(item, keywords) {
+			filtered = append(filtered, item)
 		}
 	}
 	return filtered
 }
 
+// Match reports if one of the keywords occurs in the title or description of the news.
+func (k *Keyword) Match(item entity.News) bool {
+	return matchKeywords(item, getStemKeywords(k))
+}
+
+func matchKeywords(item entity.News, keywords []string) bool {
+	titles := strings.Split(strings.ToLower(string(item.Title)), " ")
+	description := strings.Split(strings.ToLower(string(item.Description)), " ")
+	for _, stemmedKeyword := range keywords {
+		if slices.Contains(titles, stemmedKeyword) || slices.Contains(description, stemmedKeyword) {
+			return true
+		}
+	}
+	return false
+}
+
 func getStemKeywords(k *Keyword) []string {
 	var stemmedWords = make([]string, 0)
 	for _, keyword := range k.Keywords {
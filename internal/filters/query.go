@@ -0,0 +1,425 @@
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/language"
+	"strings"
+	"unicode"
+)
+
+// Fields of the news a query term can be restricted to with a "field:" prefix.
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+)
+
+// Query filters news by a keyword query parsed with ParseQuery.
+type Query struct {
+	// Text of the query as given by the user.
+	Text       string
+	Expression Expression
+}
+
+// Expression is a node of a parsed keyword query.
+type Expression interface {
+	match(d *document) bool
+	String() string
+}
+
+// ParseQuery parses the keyword query language:
+//
+//	interest rates          both words (implicit AND)
+//	rates AND bonds         both words
+//	rates OR bonds          either word, a comma is an OR as well
+//	"interest rates"        the exact phrase
+//	NOT rates, -rates       news without the word
+//	(rates OR bonds) -fed   grouping with parentheses
+//	title:rates             the word in the title, description: for the description
+//
+// Operators are upper case. Words are matched case-insensitively against the words of
+// the title and description, ignoring punctuation, after stemming them with the stemmer
+// of the language of the news.
+func ParseQuery(text string) (*Query, error) {
+	tokens, err := lex(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("invalid keywords: the query is empty")
+	}
+	p := &queryParser{tokens: tokens}
+	expr, err := p.parseOr("")
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid keywords: unexpected %s", p.tokens[p.pos])
+	}
+	return &Query{Text: text, Expression: expr}, nil
+}
+
+// Filter news matching the query.
+func (q *Query) Filter(news []entity.News) []entity.News {
+	var filtered []entity.News
+	for _, item := range news {
+		if q.Match(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// Match reports if the title and description of the news satisfy the query.
+func (q *Query) Match(item entity.News) bool {
+	return q.Expression.match(newDocument(item))
+}
+
+func (q *Query) String() string {
+	return "keywords=" + q.Text
+}
+
+// Phrases the query searches for, negated ones excluded.
+func (q *Query) Phrases() []string {
+	var phrases []string
+	collectPhrases(q.Expression, &phrases)
+	return phrases
+}
+
+func collectPhrases(expr Expression, phrases *[]string) {
+	switch e := expr.(type) {
+	case phrase:
+		*phrases = append(*phrases, strings.Join(e.words, " "))
+	case and:
+		collectPhrases(e.left, phrases)
+		collectPhrases(e.right, phrases)
+	case or:
+		collectPhrases(e.left, phrases)
+		collectPhrases(e.right, phrases)
+	}
+}
+
+// Candidates narrows down the news the query can match. The lookup returns the IDs
+// of the news containing all of the given words, or nil when it cannot tell.
+// Candidates is nil when the query may match news without any of its words.
+func (q *Query) Candidates(lookup func(words []string) map[string]bool) map[string]bool {
+	return candidates(q.Expression, lookup)
+}
+
+func candidates(expr Expression, lookup func(words []string) map[string]bool) map[string]bool {
+	switch e := expr.(type) {
+	case phrase:
+		return lookup(e.words)
+	case and:
+		left, right := candidates(e.left, lookup), candidates(e.right, lookup)
+		if left == nil {
+			return right
+		}
+		if right == nil {
+			return left
+		}
+		result := make(map[string]bool)
+		for id := range left {
+			if right[id] {
+				result[id] = true
+			}
+		}
+		return result
+	case or:
+		left, right := candidates(e.left, lookup), candidates(e.right, lookup)
+		if left == nil || right == nil {
+			return nil
+		}
+		result := make(map[string]bool, len(left)+len(right))
+		for id := range left {
+			result[id] = true
+		}
+		for id := range right {
+			result[id] = true
+		}
+		return result
+	}
+	return nil
+}
+
+// document holds the stemmed words of the fields of a news in its language.
+type document struct {
+	language string
+	fields   map[string][]string
+}
+
+// newDocument of the news, the language is detected from the text when the news has none.
+func newDocument(item entity.News) *document {
+	lang := item.Language
+	if lang == "" {
+		lang = language.Detect(string(item.Title) + " " + string(item.Description))
+	}
+	d := &document{language: lang}
+	d.fields = map[string][]string{
+		FieldTitle:       d.stems(language.Words(string(item.Title))),
+		FieldDescription: d.stems(language.Words(string(item.Description))),
+	}
+	return d
+}
+
+// stems of the words in the language of the document.
+func (d *document) stems(words []string) []string {
+	stems := make([]string, len(words))
+	for i, word := range words {
+		stems[i] = language.Stem(d.language, word)
+	}
+	return stems
+}
+
+// words of the field, or of all fields for an empty field name.
+func (d *document) words(field string) [][]string {
+	if field != "" {
+		return [][]string{d.fields[field]}
+	}
+	return [][]string{d.fields[FieldTitle], d.fields[FieldDescription]}
+}
+
+// phrase matches consecutive words, a single word is a phrase of one word.
+// The words are stemmed in the language of the news they are matched against.
+type phrase struct {
+	field string
+	words []string
+}
+
+func (p phrase) match(d *document) bool {
+	stems := d.stems(p.words)
+	for _, words := range d.words(p.field) {
+		for i := 0; i+len(stems) <= len(words); i++ {
+			if equalWords(words[i:i+len(stems)], stems) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p phrase) String() string {
+	text := strings.Join(p.words, " ")
+	if len(p.words) > 1 {
+		text = `"` + text + `"`
+	}
+	if p.field != "" {
+		return p.field + ":" + text
+	}
+	return text
+}
+
+func equalWords(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type and struct{ left, right Expression }
+
+func (a and) match(d *document) bool { return a.left.match(d) && a.right.match(d) }
+
+func (a and) String() string { return "(" + a.left.String() + " AND " + a.right.String() + ")" }
+
+type or struct{ left, right Expression }
+
+func (o or) match(d *document) bool { return o.left.match(d) || o.right.match(d) }
+
+func (o or) String() string { return "(" + o.left.String() + " OR " + o.right.String() + ")" }
+
+type not struct{ expr Expression }
+
+func (n not) match(d *document) bool { return !n.expr.match(d) }
+
+func (n not) String() string { return "NOT " + n.expr.String() }
+
+// token kinds of the query language.
+const (
+	tokenWord = iota
+	tokenPhrase
+	tokenField
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind int
+	text string
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenPhrase:
+		return `"` + t.text + `"`
+	case tokenField:
+		return t.text + ":"
+	}
+	return t.text
+}
+
+// lex the query into tokens.
+func lex(text string) ([]token, error) {
+	var tokens []token
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenOpen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenClose, ")"})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokenOr, ","})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("invalid keywords: unterminated phrase")
+			}
+			tokens = append(tokens, token{tokenPhrase, string(runes[i+1 : end])})
+			i = end + 1
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]):
+			tokens = append(tokens, token{tokenNot, "-"})
+			i++
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`(),"`, runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			i = end
+			if name, rest, found := strings.Cut(word, ":"); found && isField(name) {
+				tokens = append(tokens, token{tokenField, strings.ToLower(name)})
+				// The rest of the word is lexed again, it may start with a negation.
+				i -= len([]rune(rest))
+				continue
+			}
+			switch word {
+			case "AND":
+				tokens = append(tokens, token{tokenAnd, word})
+			case "OR":
+				tokens = append(tokens, token{tokenOr, word})
+			case "NOT":
+				tokens = append(tokens, token{tokenNot, word})
+			default:
+				tokens = append(tokens, token{tokenWord, word})
+			}
+		}
+	}
+	return tokens, nil
+}
+
+func isField(name string) bool {
+	name = strings.ToLower(name)
+	return name == FieldTitle || name == FieldDescription
+}
+
+// queryParser is a recursive descent parser of the query tokens.
+// OR binds weaker than AND, which binds weaker than NOT.
+type queryParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *queryParser) peek() (token, bool) {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos], true
+	}
+	return token{}, false
+}
+
+func (p *queryParser) parseOr(field string) (Expression, error) {
+	left, err := p.parseAnd(field)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != tokenOr {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd(field)
+		if err != nil {
+			return nil, err
+		}
+		left = or{left, right}
+	}
+}
+
+func (p *queryParser) parseAnd(field string) (Expression, error) {
+	left, err := p.parseNot(field)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind == tokenOr || t.kind == tokenClose {
+			return left, nil
+		}
+		if t.kind == tokenAnd {
+			p.pos++
+		}
+		right, err := p.parseNot(field)
+		if err != nil {
+			return nil, err
+		}
+		left = and{left, right}
+	}
+}
+
+func (p *queryParser) parseNot(field string) (Expression, error) {
+	t, ok := p.peek()
+	if ok && t.kind == tokenNot {
+		p.pos++
+		expr, err := p.parseNot(field)
+		if err != nil {
+			return nil, err
+		}
+		return not{expr}, nil
+	}
+	return p.parsePrimary(field)
+}
+
+func (p *queryParser) parsePrimary(field string) (Expression, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, errors.New("invalid keywords: unexpected end of the query")
+	}
+	p.pos++
+	switch t.kind {
+	case tokenOpen:
+		expr, err := p.parseOr(field)
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.peek(); !ok || t.kind != tokenClose {
+			return nil, errors.New("invalid keywords: missing )")
+		}
+		p.pos++
+		return expr, nil
+	case tokenField:
+		return p.parseNot(t.text)
+	case tokenWord, tokenPhrase:
+		words := language.Words(t.text)
+		if len(words) == 0 {
+			return nil, fmt.Errorf("invalid keywords: %s has no words to search", t)
+		}
+		return phrase{field: field, words: words}, nil
+	}
+	return nil, fmt.Errorf("invalid keywords: unexpected %s", t)
+}
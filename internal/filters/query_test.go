@@ -0,0 +1,185 @@
+package filters
+
+import (
+	"news-aggregator/internal/entity"
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr string
+	}{
+		{name: "Single word", query: "Ukraine", want: "ukraine"},
+		{name: "Comma separated words", query: "Ukraine,China", want: "(ukraine OR china)"},
+		{name: "Implicit AND", query: "interest rates", want: "(interest AND rates)"},
+		{name: "Precedence", query: "a OR b AND c", want: "(a OR (b AND c))"},
+		{name: "Parentheses", query: "(a OR b) AND c", want: "((a OR b) AND c)"},
+		{name: "Phrase", query: `"Interest Rates"`, want: `"interest rates"`},
+		{name: "Negations", query: "a -b NOT c", want: "((a AND NOT b) AND NOT c)"},
+		{name: "Field prefixes", query: `title:"rate cut" description:-fed`, want: `(title:"rate cut" AND NOT description:fed)`},
+		{name: "Field of a group", query: "Title:(a OR b)", want: "(title:a OR title:b)"},
+		{name: "Punctuated word", query: "covid-19", want: `"covid 19"`},
+		{name: "Lower-case operators are words", query: "rock and roll", want: "((rock AND and) AND roll)"},
+		{name: "Empty query", query: "  ", wantErr: "invalid keywords: the query is empty"},
+		{name: "Unterminated phrase", query: `"rates`, wantErr: "invalid keywords: unterminated phrase"},
+		{name: "Missing parenthesis", query: "(a OR b", wantErr: "invalid keywords: missing )"},
+		{name: "Unexpected parenthesis", query: "a)", wantErr: "invalid keywords: unexpected )"},
+		{name: "Dangling operator", query: "a AND", wantErr: "invalid keywords: unexpected end of the query"},
+		{name: "No words", query: `""`, wantErr: `invalid keywords: "" has no words to search`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQuery(tt.query)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ParseQuery() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+			if got.Expression.String() != tt.want {
+				t.Errorf("ParseQuery() = %s, want %s", got.Expression, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_Match(t *testing.T) {
+	item := entity.News{
+		Title:       "Central bank cuts interest rates",
+		Description: "The Fed lowered rates, markets rallied (again).",
+	}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"rates", true},
+		{"rate", true},
+		{"RALLY", true},
+		{"again", true},
+		{"crypto", false},
+		{"crypto,markets", true},
+		{"bank AND crypto", false},
+		{`"interest rates"`, true},
+		{`"rates interest"`, false},
+		{`"lowered rates markets"`, true},
+		{"title:fed", false},
+		{"description:fed", true},
+		{"-fed", false},
+		{"NOT crypto", true},
+		{"(crypto OR bank) -stocks", true},
+		{`title:("interest rates" OR crypto) description:rallied`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+			if got := query.Match(item); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_Phrases(t *testing.T) {
+	query, err := ParseQuery(`title:"Interest Rates" AND (fed OR ecb) -crypto`)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	want := []string{"interest rates", "fed", "ecb"}
+	if got := query.Phrases(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Phrases() = %v, want %v", got, want)
+	}
+}
+
+func TestQuery_Candidates(t *testing.T) {
+	postings := map[string]map[string]bool{
+		"rates": {"1": true, "2": true},
+		"fed":   {"2": true, "3": true},
+	}
+	// lookup cannot tell for words that are not indexed.
+	lookup := func(words []string) map[string]bool {
+		var result map[string]bool
+		for _, word := range words {
+			ids, ok := postings[word]
+			if !ok {
+				continue
+			}
+			if result == nil {
+				result = ids
+				continue
+			}
+			both := make(map[string]bool)
+			for id := range result {
+				if ids[id] {
+					both[id] = true
+				}
+			}
+			result = both
+		}
+		return result
+	}
+	tests := []struct {
+		query string
+		want  map[string]bool
+	}{
+		{"rates", map[string]bool{"1": true, "2": true}},
+		{"rates fed", map[string]bool{"2": true}},
+		{"rates OR fed", map[string]bool{"1": true, "2": true, "3": true}},
+		{`"the rates"`, map[string]bool{"1": true, "2": true}},
+		{"rates -fed", map[string]bool{"1": true, "2": true}},
+		{"-fed", nil},
+		{"rates OR the", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+			if got := query.Candidates(lookup); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Candidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_MatchLanguages(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		item  entity.News
+		want  bool
+	}{
+		{"Ukrainian inflection", "Україна", entity.News{Title: "Зеленський прибув до України", Language: "uk"}, true},
+		{"Ukrainian detected", "війна", entity.News{Title: "Війною зруйновано місто, повідомляє уряд"}, true},
+		{"Ukrainian apostrophe", "сім'я", entity.News{Title: "Сімʼя повернулася додому", Language: "uk"}, true},
+		{"German inflection", "Regierung", entity.News{Title: "Neue Regeln der Regierungen", Language: "de-DE"}, true},
+		{"Spanish inflection", "elección", entity.News{Title: "Las elecciones en México", Language: "es"}, true},
+		{"English stemmer not applied to German", "running", entity.News{Title: "Der Run der Anleger", Language: "de"}, false},
+		{"English by default", "rallies", entity.News{Title: "Markets rally"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+			if got := query.Match(tt.item); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,7 +15,8 @@ type NewsFilter interface {
 }
 
 // InitializeFilters based on provided parameters.
-func InitializeFilters(keywords, dateStart, dateEnd *string) []NewsFilter {
+// Categories and authors are comma-separated, news of any of them are kept.
+func InitializeFilters(keywords, dateStart, dateEnd, category, author *string) []NewsFilter {
 	var newsFilters []NewsFilter
 
 	if keywordFilter := convertKeywords(keywords); keywordFilter != nil {
@@ -27,15 +28,22 @@ func InitializeFilters(keywords, dateStart, dateEnd *string) []NewsFilter {
 	if dateEndFilter := convertDateEnd(dateEnd); dateEndFilter != nil {
 		newsFilters = append(newsFilters, dateEndFilter)
 	}
+	if categories := splitList(*category); len(categories) > 0 {
+		newsFilters = append(newsFilters, &filters.Category{Categories: categories})
+	}
+	if authors := splitList(*author); len(authors) > 0 {
+		newsFilters = append(newsFilters, &filters.Author{Authors: authors})
+	}
 
 	return newsFilters
 }
 
-// convertKeywords a comma-separated keyword string into a filter.
-func convertKeywords(keywords *string) *filters.Keyword {
+// convertKeywords query string into a filter, see filters.ParseQuery.
+// Comma-separated keywords match news with any of the keywords.
+func convertKeywords(keywords *string) *filters.Query {
 	if len(*keywords) > 0 {
-		keywordList := strings.Split(*keywords, ",")
-		return &filters.Keyword{Keywords: keywordList}
+		query, _ := filters.ParseQuery(*keywords)
+		return query
 	}
 	return nil
 }
@@ -57,3 +65,14 @@ func convertDateEnd(dateEnd *string) *filters.DateEnd {
 	}
 	return nil
 }
+
+// splitList of comma-separated values, without empty ones.
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -8,22 +8,35 @@ func TestInitializeFilters(t *testing.T) {
 	keywords := "keyword1,keyword2"
 	dateStart := "2024-06-01"
 	dateEnd := "2024-06-30"
+	category := "Sport, Europe"
+	author := "Jane Doe"
 
-	filters := InitializeFilters(&keywords, &dateStart, &dateEnd)
+	filters := InitializeFilters(&keywords, &dateStart, &dateEnd, &category, &author)
+
+	if len(filters) != 5 {
+		t.Errorf("Expected 5 filters, got %d", len(filters))
+	}
+
+	if got := filters[3].String(); got != " category=Sport,Europe" {
+		t.Errorf("Expected the category filter, got %q", got)
+	}
+
+	category, author = "", " , "
+	filters = InitializeFilters(&keywords, &dateStart, &dateEnd, &category, &author)
 
 	if len(filters) != 3 {
 		t.Errorf("Expected 3 filters, got %d", len(filters))
 	}
 
 	dateEnd = ""
-	filters = InitializeFilters(&keywords, &dateStart, &dateEnd)
+	filters = InitializeFilters(&keywords, &dateStart, &dateEnd, &category, &author)
 
 	if len(filters) != 2 {
 		t.Errorf("Expected 2 filters, got %d", len(filters))
 	}
 
 	keywords = ""
-	filters = InitializeFilters(&keywords, &dateStart, &dateEnd)
+	filters = InitializeFilters(&keywords, &dateStart, &dateEnd, &category, &author)
 
 	if len(filters) != 1 {
 		t.Errorf("Expected 1 filter, got %d", len(filters))
@@ -39,8 +52,8 @@ func TestConvertKeywords(t *testing.T) {
 		t.Errorf("Expected non-nil result for valid input")
 	}
 
-	if len(result.Keywords) != 2 {
-		t.Errorf("Expected 2 keywords, got %d", len(result.Keywords))
+	if result.Expression.String() != "(keyword1 OR keyword2)" {
+		t.Errorf("Expected keyword1 OR keyword2, got %s", result.Expression)
 	}
 
 	keywords = ""
@@ -0,0 +1,95 @@
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// scripts written in a single language of the stemmers, or without stemming.
+var scripts = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Arabic, "ar"},
+	{unicode.Greek, "el"},
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, "zh"},
+	{unicode.Tamil, "ta"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Hebrew, "he"},
+}
+
+// letters only used by one of the languages written in the same script.
+var letters = map[rune]string{
+	'і': Ukrainian, 'ї': Ukrainian, 'є': Ukrainian, 'ґ': Ukrainian,
+	'ы': "ru", 'э': "ru", 'ъ': "ru", 'ё': "ru",
+	'ß': "de", 'ñ': "es", '¿': "es", '¡': "es", 'ã': "pt", 'õ': "pt", 'œ': "fr",
+}
+
+// Detect the language of the text from its script, the letters only used
+// by a language and its stop words. It returns "" when the language is not clear.
+func Detect(text string) string {
+	text = strings.ToLower(text)
+	var cyrillic, latin int
+	counts := make(map[string]int)
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		default:
+			for _, script := range scripts {
+				if unicode.Is(script.table, r) {
+					counts[script.language]++
+					break
+				}
+			}
+		}
+		if language, ok := letters[r]; ok {
+			counts[language] += 2
+		}
+	}
+	if counts["ja"] > 0 {
+		// Japanese is written with Han characters between the kana.
+		counts["ja"] += counts["zh"]
+	}
+	if script := best(counts, []string{"ar", "el", "ko", "ja", "zh", "ta", "hi", "he"}); script != "" &&
+		counts[script] > cyrillic && counts[script] > latin {
+		return script
+	}
+
+	for _, word := range Words(text) {
+		for language, words := range stopWords {
+			if words[word] {
+				counts[language]++
+			}
+		}
+	}
+	if cyrillic > latin {
+		return best(counts, []string{Ukrainian, "ru"})
+	}
+	if latin > 0 {
+		return best(counts, []string{English, "de", "es", "fr", "it", "pt", "nl"})
+	}
+	return ""
+}
+
+// best of the languages by their count, "" on a tie or without any count.
+func best(counts map[string]int, languages []string) string {
+	result, top, tie := "", 0, false
+	for _, language := range languages {
+		switch count := counts[language]; {
+		case count > top:
+			result, top, tie = language, count, false
+		case count == top && count > 0:
+			tie = true
+		}
+	}
+	if tie {
+		return ""
+	}
+	return result
+}
@@ -0,0 +1,31 @@
+package language
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"English", "The central bank cuts interest rates as inflation cools", "en"},
+		{"German", "Die Regierung beschließt neue Regeln für den Handel mit Strom", "de"},
+		{"Spanish", "El gobierno anuncia nuevas medidas para la economía", "es"},
+		{"French", "Le gouvernement annonce des mesures pour les entreprises", "fr"},
+		{"Ukrainian", "Зеленський скасував усі закордонні поїздки через наступ", "uk"},
+		{"Ukrainian stop words", "Уряд ухвалив рішення про бюджет на наступний рік", "uk"},
+		{"Russian", "Правительство объявило о новых мерах поддержки экономики", "ru"},
+		{"Japanese", "東京で新しい法律が成立しました", "ja"},
+		{"Chinese", "中国经济增长放缓", "zh"},
+		{"Arabic", "الحكومة تعلن عن إجراءات جديدة", "ar"},
+		{"Unclear", "Apple iPhone 15", ""},
+		{"Empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package language provides the language-aware text processing of news:
+// Unicode word tokenisation, stop words, stemming with the Snowball stemmers
+// and detection of the language of a text.
+package language
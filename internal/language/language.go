@@ -0,0 +1,115 @@
+package language
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/blevesearch/snowballstem"
+	"github.com/blevesearch/snowballstem/arabic"
+	"github.com/blevesearch/snowballstem/danish"
+	"github.com/blevesearch/snowballstem/dutch"
+	"github.com/blevesearch/snowballstem/finnish"
+	"github.com/blevesearch/snowballstem/french"
+	"github.com/blevesearch/snowballstem/german"
+	"github.com/blevesearch/snowballstem/hungarian"
+	"github.com/blevesearch/snowballstem/irish"
+	"github.com/blevesearch/snowballstem/italian"
+	"github.com/blevesearch/snowballstem/norwegian"
+	"github.com/blevesearch/snowballstem/portuguese"
+	"github.com/blevesearch/snowballstem/romanian"
+	"github.com/blevesearch/snowballstem/russian"
+	"github.com/blevesearch/snowballstem/spanish"
+	"github.com/blevesearch/snowballstem/swedish"
+	"github.com/blevesearch/snowballstem/tamil"
+	"github.com/blevesearch/snowballstem/turkish"
+	"github.com/reiver/go-porterstemmer"
+)
+
+// English is the language of news whose language is not known.
+const English = "en"
+
+// Ukrainian has no Snowball stemmer, its words are stemmed by stemUkrainian.
+const Ukrainian = "uk"
+
+// snowball stemmers by ISO 639-1 language code.
+var snowball = map[string]func(env *snowballstem.Env) bool{
+	"ar": arabic.Stem,
+	"da": danish.Stem,
+	"de": german.Stem,
+	"es": spanish.Stem,
+	"fi": finnish.Stem,
+	"fr": french.Stem,
+	"ga": irish.Stem,
+	"hu": hungarian.Stem,
+	"it": italian.Stem,
+	"nb": norwegian.Stem,
+	"nl": dutch.Stem,
+	"nn": norwegian.Stem,
+	"no": norwegian.Stem,
+	"pt": portuguese.Stem,
+	"ro": romanian.Stem,
+	"ru": russian.Stem,
+	"sv": swedish.Stem,
+	"ta": tamil.Stem,
+	"tr": turkish.Stem,
+}
+
+// Normalize a language tag like "en-US" or "de_AT" to its lower-case language code.
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// Stem the lower-case word with the stemmer of the language. English and an unknown
+// language use the porter stemmer, languages without a stemmer keep the word as is.
+func Stem(language, word string) string {
+	language = Normalize(language)
+	switch language {
+	case English, "":
+		return porterstemmer.StemString(word)
+	case Ukrainian:
+		return stemUkrainian(word)
+	}
+	stem, ok := snowball[language]
+	if !ok {
+		return word
+	}
+	env := snowballstem.NewEnv(word)
+	stem(env)
+	return env.Current()
+}
+
+// Words splits the text into lower-case words of letters, marks and digits.
+// An apostrophe between Cyrillic letters is part of a Ukrainian word and dropped,
+// other apostrophes separate words.
+func Words(text string) []string {
+	var words []string
+	var word strings.Builder
+	runes := []rune(strings.ToLower(text))
+	for i, r := range runes {
+		switch {
+		case isApostrophe(r):
+			if i > 0 && i+1 < len(runes) && unicode.Is(unicode.Cyrillic, runes[i-1]) && unicode.Is(unicode.Cyrillic, runes[i+1]) {
+				continue
+			}
+		case unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r):
+			word.WriteRune(r)
+			continue
+		}
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’' || r == 'ʼ'
+}
@@ -0,0 +1,86 @@
+package language
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"en-US":  "en",
+		" de_AT": "de",
+		"UK":     "uk",
+		"":       "",
+	}
+	for tag, want := range tests {
+		if got := Normalize(tag); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		language string
+		words    []string
+		want     string
+	}{
+		{"en", []string{"rates", "rate"}, "rate"},
+		{"", []string{"rallied", "rally"}, "ralli"},
+		{"de-DE", []string{"regierungen", "regierung"}, "regier"},
+		{"es", []string{"elecciones", "elección"}, "eleccion"},
+		{"fr", []string{"gouvernements", "gouvernement"}, "gouvern"},
+		{"ru", []string{"выборы", "выборов"}, "выбор"},
+		{"uk", []string{"україна", "україни", "україні", "україну"}, "україн"},
+		{"uk", []string{"війна", "війни", "війною"}, "війн"},
+		{"uk", []string{"повідомляється", "повідомляє"}, "повідомля"},
+		{"uk", []string{"мир"}, "мир"},
+		{"zh", []string{"新闻"}, "新闻"},
+	}
+	for _, tt := range tests {
+		for _, word := range tt.words {
+			if got := Stem(tt.language, word); got != tt.want {
+				t.Errorf("Stem(%q, %q) = %q, want %q", tt.language, word, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Rates, bonds (again)!", []string{"rates", "bonds", "again"}},
+		{"Zelenskyy's trip", []string{"zelenskyy", "s", "trip"}},
+		{"Сімʼя", []string{"сімя"}},
+		{"М’ясо та сім'я", []string{"мясо", "та", "сімя"}},
+		{"Café déjà-vu", []string{"café", "déjà", "vu"}},
+		{"COVID-19", []string{"covid", "19"}},
+	}
+	for _, tt := range tests {
+		if got := Words(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Words(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestIsStopWord(t *testing.T) {
+	tests := []struct {
+		language string
+		word     string
+		want     bool
+	}{
+		{"en", "the", true},
+		{"", "the", true},
+		{"de-DE", "und", true},
+		{"de", "the", false},
+		{"uk", "що", true},
+		{"sv", "och", false},
+	}
+	for _, tt := range tests {
+		if got := IsStopWord(tt.language, tt.word); got != tt.want {
+			t.Errorf("IsStopWord(%q, %q) = %v, want %v", tt.language, tt.word, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package language
+
+// stopWords by language are too common to be indexed or scored.
+// They also tell the languages written in the same script apart.
+var stopWords = map[string]map[string]bool{
+	English: set("a", "about", "above", "after", "again", "against", "all", "am", "an", "and", "any", "are", "as", "at",
+		"be", "because", "been", "before", "being", "below", "between", "both", "but", "by",
+		"can", "could", "did", "do", "does", "doing", "down", "during", "each", "few", "for", "from", "further",
+		"had", "has", "have", "having", "he", "her", "here", "hers", "herself", "him", "himself", "his", "how",
+		"i", "if", "in", "into", "is", "it", "its", "itself", "just", "me", "more", "most", "my", "myself",
+		"no", "nor", "not", "now", "of", "off", "on", "once", "only", "or", "other", "our", "ours", "ourselves",
+		"out", "over", "own", "same", "she", "should", "so", "some", "such",
+		"than", "that", "the", "their", "theirs", "them", "themselves", "then", "there", "these", "they",
+		"this", "those", "through", "to", "too", "under", "until", "up", "very",
+		"was", "we", "were", "what", "when", "where", "which", "while", "who", "whom", "why", "will", "with",
+		"would", "you", "your", "yours", "yourself", "yourselves"),
+	"de": set("aber", "als", "am", "an", "auch", "auf", "aus", "bei", "bin", "bis", "das", "dass", "dem", "den",
+		"der", "des", "die", "doch", "dort", "durch", "ein", "eine", "einem", "einen", "einer", "eines", "er", "es",
+		"für", "hat", "hatte", "ich", "ihr", "im", "in", "ist", "mit", "nach", "nicht", "noch", "nur", "oder",
+		"sich", "sie", "sind", "so", "um", "und", "uns", "unter", "vom", "von", "vor", "war", "wie", "wir",
+		"wird", "wurde", "zu", "zum", "zur"),
+	"es": set("a", "al", "como", "con", "de", "del", "el", "en", "entre", "era", "es", "esta", "este", "fue",
+		"ha", "han", "la", "las", "le", "lo", "los", "más", "mi", "muy", "no", "nos", "o", "para", "pero", "por",
+		"que", "se", "sin", "sobre", "su", "sus", "también", "un", "una", "uno", "y", "ya"),
+	"fr": set("au", "aux", "avec", "ce", "ces", "dans", "de", "des", "du", "elle", "en", "est", "et", "il", "ils",
+		"je", "la", "le", "les", "leur", "lui", "mais", "me", "même", "ne", "nous", "on", "ou", "par", "pas",
+		"pour", "qu", "que", "qui", "sa", "se", "ses", "son", "sont", "sur", "un", "une", "vous"),
+	"it": set("a", "al", "alla", "anche", "che", "come", "con", "da", "dal", "dei", "del", "della", "di", "e",
+		"è", "gli", "ha", "i", "il", "in", "la", "le", "lo", "ma", "nel", "nella", "non", "per", "più", "si",
+		"sono", "su", "sul", "tra", "un", "una"),
+	"pt": set("a", "ao", "as", "com", "como", "da", "das", "de", "do", "dos", "e", "é", "em", "foi", "mais",
+		"mas", "na", "não", "nas", "no", "nos", "o", "os", "ou", "para", "pela", "pelo", "por", "que", "se",
+		"sem", "seu", "sua", "um", "uma"),
+	"nl": set("aan", "als", "bij", "dat", "de", "den", "der", "die", "dit", "een", "en", "er", "het", "hij",
+		"in", "is", "je", "met", "naar", "niet", "of", "om", "ook", "op", "over", "te", "tot", "uit", "van",
+		"voor", "was", "wat", "werd", "wordt", "ze", "zich", "zijn"),
+	Ukrainian: set("а", "але", "б", "би", "бо", "був", "була", "були", "було", "бути", "в", "вже", "ви", "від",
+		"він", "вона", "вони", "воно", "все", "де", "для", "до", "з", "за", "і", "із", "й", "к", "коли", "ми",
+		"на", "над", "не", "ні", "но", "о", "об", "однак", "от", "по", "під", "після", "при", "про", "та",
+		"так", "також", "те", "ти", "то", "у", "це", "цей", "через", "що", "щоб", "як", "який", "яка", "які"),
+	"ru": set("а", "без", "был", "была", "были", "было", "быть", "в", "вы", "да", "для", "до", "его", "ее",
+		"её", "если", "же", "за", "и", "из", "или", "им", "их", "к", "как", "когда", "ли", "мы", "на", "не",
+		"нет", "но", "о", "об", "он", "она", "они", "оно", "от", "по", "под", "после", "при", "про", "с", "со",
+		"так", "также", "то", "только", "у", "уже", "что", "чтобы", "это", "этот", "я"),
+}
+
+func set(words ...string) map[string]bool {
+	result := make(map[string]bool, len(words))
+	for _, word := range words {
+		result[word] = true
+	}
+	return result
+}
+
+// IsStopWord reports if the lower-case word is a stop word of the language,
+// English stop words are used when the language is not known.
+func IsStopWord(language, word string) bool {
+	language = Normalize(language)
+	if language == "" {
+		language = English
+	}
+	return stopWords[language][word]
+}
@@ -0,0 +1,36 @@
+package language
+
+import "strings"
+
+// minUkrainianStem is the shortest stem, in letters, left by stemUkrainian.
+const minUkrainianStem = 3
+
+// ukrainianReflexive suffixes of verbs, stripped before the endings.
+var ukrainianReflexive = []string{"ся", "сь"}
+
+// ukrainianEndings of nouns, adjectives and verbs.
+var ukrainianEndings = []string{
+	"ими", "іми", "ами", "ями", "ого", "ому", "ові", "еві", "ати", "ити", "іти", "ють", "ять", "єть",
+	"ий", "ій", "ої", "ою", "ею", "ах", "ях", "ам", "ям", "ом", "ем", "ів", "їв",
+	"ти", "ть", "ла", "ло", "ли", "ує",
+	"а", "я", "о", "е", "є", "и", "і", "ї", "у", "ю", "й", "ь",
+}
+
+// stemUkrainian is a light stemmer stripping a reflexive suffix and the longest
+// inflectional ending, so that "україна", "україни" and "україні" share the stem "україн".
+func stemUkrainian(word string) string {
+	for _, suffix := range ukrainianReflexive {
+		if stem, ok := strings.CutSuffix(word, suffix); ok && len([]rune(stem)) >= minUkrainianStem {
+			word = stem
+			break
+		}
+	}
+	longest := ""
+	for _, ending := range ukrainianEndings {
+		if len(ending) > len(longest) && strings.HasSuffix(word, ending) &&
+			len([]rune(word))-len([]rune(ending)) >= minUkrainianStem {
+			longest = ending
+		}
+	}
+	return strings.TrimSuffix(word, longest)
+}
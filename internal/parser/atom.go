@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"github.com/mmcdole/gofeed/atom"
+	"io"
+	"news-aggregator/internal/entity"
+	"strings"
+	"time"
+)
+
+// Atom - parser for Atom 1.0 feeds.
+type Atom struct{}
+
+func init() {
+	Register(Format{
+		Name:       "atom",
+		MimeTypes:  []string{"application/atom+xml"},
+		Extensions: []string{".atom"},
+		Sniff:      sniffAtom,
+		Parser:     &Atom{},
+	})
+}
+
+// sniffAtom checks if the document root is an Atom feed element.
+func sniffAtom(head []byte) bool {
+	return xmlRootElement(head) == "feed"
+}
+
+// Parse - implementation of a parser for files in Atom format.
+func (atomParser *Atom) Parse(ctx context.Context, r io.Reader, meta Meta) ([]entity.News, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fp := atom.Parser{}
+	feed, err := fp.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var allNews []entity.News
+	for _, entry := range feed.Entries {
+		link := atomLink(entry.Links)
+		allNews = append(allNews, entity.News{
+			Title:       entity.Title(strings.TrimSpace(entry.Title)),
+			Description: entity.Description(atomDescription(entry)),
+			Link:        entity.Link(link),
+			Date:        atomDate(entry),
+			Source:      feed.Title,
+			Canonical:   canonicalLink(link, entry.ID),
+			GUID:        strings.TrimSpace(entry.ID),
+			Authors:     atomAuthors(entry.Authors),
+			Categories:  atomCategories(entry.Categories),
+			Image:       entity.Link(atomImage(entry.Links)),
+			Content:     atomContent(entry),
+		})
+	}
+	if len(allNews) == 0 {
+		return nil, errors.New("no news found")
+	}
+	setLanguage(allNews, feed.Language)
+	return allNews, nil
+}
+
+// atomDescription prefers the entry summary and falls back to its text content.
+func atomDescription(entry *atom.Entry) string {
+	if summary := strings.TrimSpace(entry.Summary); summary != "" {
+		return summary
+	}
+	if entry.Content != nil {
+		return stripTags(entry.Content.Value)
+	}
+	return ""
+}
+
+// atomLink returns the alternate link of an entry, or the first link without a relation.
+func atomLink(links []*atom.Link) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
+
+// atomAuthors returns the names of the authors of an entry.
+func atomAuthors(authors []*atom.Person) []string {
+	var names []string
+	for _, author := range authors {
+		names = append(names, author.Name)
+	}
+	return trimValues(names)
+}
+
+// atomCategories returns the labels of the categories of an entry, or their terms without a label.
+func atomCategories(categories []*atom.Category) []string {
+	var names []string
+	for _, category := range categories {
+		if category.Label != "" {
+			names = append(names, category.Label)
+		} else {
+			names = append(names, category.Term)
+		}
+	}
+	return trimValues(names)
+}
+
+// atomImage returns the first enclosure link of an entry with an image type.
+func atomImage(links []*atom.Link) string {
+	for _, link := range links {
+		if link.Rel == "enclosure" && strings.HasPrefix(link.Type, "image/") {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// atomContent returns the content of an entry as given by the feed.
+func atomContent(entry *atom.Entry) string {
+	if entry.Content == nil {
+		return ""
+	}
+	return strings.TrimSpace(entry.Content.Value)
+}
+
+// atomDate returns the publication date of an entry, falling back to its update date.
+func atomDate(entry *atom.Entry) time.Time {
+	if entry.PublishedParsed != nil {
+		return entry.PublishedParsed.UTC()
+	}
+	if entry.UpdatedParsed != nil {
+		return entry.UpdatedParsed.UTC()
+	}
+	return time.Time{}
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"news-aggregator/internal/entity"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Unit test for atom parser.
+func TestAtom_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    []entity.News
+		wantErr bool
+	}{
+		{
+			name: "Test parsing valid Atom file",
+			file: "../testdata/news_atom.xml",
+			want: []entity.News{
+				{
+					Title:       "Iran's president Ebrahim Raisi involved in helicopter crash",
+					Description: "Rescue teams are trying to reach the site in a mountainous area of East Azerbaijan province.",
+					Link:        "https://www.theguardian.com/world/2024/may/19/iran-president-raisi-helicopter",
+					Date:        time.Date(2024, 5, 19, 12, 20, 49, 0, time.UTC),
+					Source:      "The Guardian World",
+					Language:    "en",
+					GUID:        "https://www.theguardian.com/world/2024/may/19/iran-president-raisi-helicopter",
+					Authors:     []string{"Patrick Wintour"},
+					Categories:  []string{"Iran"},
+					Image:       "https://i.guim.co.uk/img/media/raisi.jpg",
+				},
+				{
+					Title:       "Slovakia's prime minister in stable condition after shooting",
+					Description: "Robert Fico remains in a serious but stable condition.",
+					Link:        "https://www.theguardian.com/world/2024/may/18/slovakia-robert-fico-condition",
+					Date:        time.Date(2024, 5, 18, 23, 5, 19, 0, time.UTC),
+					Source:      "The Guardian World",
+					Language:    "en",
+					GUID:        "https://www.theguardian.com/world/2024/may/18/slovakia-robert-fico-condition",
+					Authors:     []string{"Jon Henley"},
+					Content:     "<p>Robert Fico remains in a serious but stable condition.</p>",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Test parsing RSS file with Atom parser",
+			file:    "../testdata/invalid_news.xml",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Test parsing missing Atom file",
+			file:    "../testdata/nonexistent_file.atom",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomParser := &FileParser{
+				Path:   entity.PathToFile(tt.file),
+				Parser: &Atom{},
+			}
+			got, err := atomParser.Parse()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
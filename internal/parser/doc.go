@@ -1,3 +1,3 @@
 // Package parser provides Api for parsing news data from various file formats.
-// It supports Rss, Json, Html file formats.
+// It supports Rss, Atom, Json, JSON Feed and Html file formats.
 package parser
@@ -0,0 +1,158 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"net/url"
+	"news-aggregator/internal/entity"
+	"strings"
+	"time"
+)
+
+// defaultLinkAttr is used to read item links when the profile sets no attribute.
+const defaultLinkAttr = "href"
+
+// defaultImageAttr is used to read item images when the profile sets no attribute.
+const defaultImageAttr = "src"
+
+// Html - generic parser for HTML pages driven by the scrape profile of a source.
+type Html struct{}
+
+// ValidateProfile checks that the scrape profile can be used by the Html parser.
+func ValidateProfile(profile *entity.ScrapeProfile) error {
+	if profile == nil {
+		return errors.New("scrape profile is missing")
+	}
+	if strings.TrimSpace(profile.ItemSelector) == "" {
+		return errors.New("scrape profile item selector is missing")
+	}
+	if profile.BaseURL != "" {
+		if u, err := url.Parse(profile.BaseURL); err != nil || !u.IsAbs() {
+			return errors.New("scrape profile base URL must be absolute")
+		}
+	}
+	return nil
+}
+
+// Parse - implementation of a parser for HTML pages described by meta.Profile.
+func (htmlParser *Html) Parse(ctx context.Context, r io.Reader, meta Meta) ([]entity.News, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	profile := meta.Profile
+	if err := ValidateProfile(profile); err != nil {
+		return nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	base := profile.BaseURL
+	if base == "" {
+		base = meta.Location
+	}
+	source := meta.SourceName
+	if source == "" {
+		source = doc.Find("title").First().Text()
+	}
+	linkAttr := profile.LinkAttr
+	if linkAttr == "" {
+		linkAttr = defaultLinkAttr
+	}
+	imageAttr := profile.ImageAttr
+	if imageAttr == "" {
+		imageAttr = defaultImageAttr
+	}
+
+	pageCanonical := resolveLink(base, doc.Find(`link[rel="canonical"]`).First().AttrOr("href", ""))
+
+	var allNews []entity.News
+	doc.Find(profile.ItemSelector).Each(func(i int, s *goquery.Selection) {
+		title := extract(s, profile.TitleSelector, profile.TitleAttr)
+		if title == "" {
+			return
+		}
+		link := resolveLink(base, extract(s, profile.LinkSelector, linkAttr))
+		item := entity.News{
+			Title:       entity.Title(title),
+			Description: entity.Description(extract(s, profile.DescriptionSelector, profile.DescriptionAttr)),
+			Link:        entity.Link(link),
+			Date:        parseDate(extract(s, profile.DateSelector, profile.DateAttr), profile.DateLayout),
+			Source:      strings.TrimSpace(source),
+			Authors:     extractAll(s, profile.AuthorSelector),
+			Categories:  extractAll(s, profile.CategorySelector),
+		}
+		if profile.ImageSelector != "" {
+			item.Image = entity.Link(resolveLink(base, extract(s, profile.ImageSelector, imageAttr)))
+		}
+		if profile.ContentSelector != "" {
+			content, _ := s.Find(profile.ContentSelector).First().Html()
+			item.Content = strings.TrimSpace(content)
+		}
+		// The canonical link of the page is the one of the news on an article page.
+		if link == "" || link == meta.Location {
+			item.Canonical = canonicalLink(link, pageCanonical)
+		}
+		allNews = append(allNews, item)
+	})
+	if len(allNews) == 0 {
+		return nil, errors.New("no news found")
+	}
+	setLanguage(allNews, doc.Find("html").AttrOr("lang", ""))
+	return allNews, nil
+}
+
+// extract returns the trimmed attribute value or text of the element matched by selector
+// within the item. An empty selector refers to the item itself.
+func extract(item *goquery.Selection, selector, attr string) string {
+	node := item
+	if selector != "" {
+		node = item.Find(selector).First()
+	}
+	if attr != "" {
+		value, _ := node.Attr(attr)
+		return strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(node.Text())
+}
+
+// extractAll returns the trimmed texts of the elements matched by selector within the item,
+// nil for an empty selector.
+func extractAll(item *goquery.Selection, selector string) []string {
+	if selector == "" {
+		return nil
+	}
+	return trimValues(item.Find(selector).Map(func(i int, s *goquery.Selection) string {
+		return s.Text()
+	}))
+}
+
+// resolveLink resolves a relative link against the base URL.
+func resolveLink(base, link string) string {
+	if link == "" || base == "" {
+		return link
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return link
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	return baseURL.ResolveReference(ref).String()
+}
+
+// parseDate parses the value with the layout, defaulting to RFC 3339.
+// News without a recognisable date are dated with the current day.
+func parseDate(value, layout string) time.Time {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	if date, err := time.Parse(layout, value); err == nil {
+		return date.UTC()
+	}
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"context"
+	"news-aggregator/internal/entity"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHtml_Parse(t *testing.T) {
+	profile := &entity.ScrapeProfile{
+		ItemSelector:        `div[data-testid="card"]`,
+		TitleSelector:       `h2[data-testid="card-headline"]`,
+		LinkSelector:        "a.card-link",
+		DescriptionSelector: `p[data-testid="card-description"]`,
+		DateSelector:        "time",
+		DateAttr:            "datetime",
+		BaseURL:             "https://www.bbc.com",
+	}
+	tests := []struct {
+		name    string
+		file    string
+		meta    Meta
+		want    []entity.News
+		wantErr bool
+	}{
+		{
+			name: "should collect news described by the scrape profile",
+			file: "../testdata/news_scrape.html",
+			meta: Meta{SourceName: "bbc_html", Profile: profile},
+			want: []entity.News{
+				{
+					Title:       "Iran's president missing after helicopter crash",
+					Description: "Rescue teams are searching fog-bound mountains in north-western Iran.",
+					Link:        "https://www.bbc.com/news/articles/c4nn0zxe84eo",
+					Date:        time.Date(2024, 5, 19, 18, 40, 0, 0, time.UTC),
+					Source:      "bbc_html",
+					Language:    "en",
+				},
+				{
+					Title:       "Manchester City win fourth straight title",
+					Description: "Pep Guardiola's side beat West Ham on the final day.",
+					Link:        "https://www.bbc.com/sport/football/articles/cz5r8wx4",
+					Date:        time.Date(2024, 5, 19, 17, 5, 0, 0, time.UTC),
+					Source:      "bbc_html",
+					Language:    "en",
+				},
+			},
+		},
+		{
+			name:    "should fail without scrape profile",
+			file:    "../testdata/news_scrape.html",
+			meta:    Meta{SourceName: "bbc_html"},
+			wantErr: true,
+		},
+		{
+			name:    "should fail if nothing matches the item selector",
+			file:    "../testdata/invalid_news.html",
+			meta:    Meta{SourceName: "bbc_html", Profile: profile},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := os.Open(tt.file)
+			if err != nil {
+				t.Fatalf("failed to open %s: %v", tt.file, err)
+			}
+			defer file.Close()
+			got, err := (&Html{}).Parse(context.Background(), file, tt.meta)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHtml_ParseCanonical(t *testing.T) {
+	page := `<html><head><link rel="canonical" href="/news/articles/1"></head>
+<body><article><h1>Rates held</h1></article></body></html>`
+	profile := &entity.ScrapeProfile{ItemSelector: "article", TitleSelector: "h1"}
+	meta := Meta{SourceName: "bbc", Location: "https://m.bbc.com/news/articles/1?at_medium=rss", Profile: profile}
+
+	got, err := (&Html{}).Parse(context.Background(), strings.NewReader(page), meta)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if want := entity.Link("https://m.bbc.com/news/articles/1"); len(got) != 1 || got[0].Canonical != want {
+		t.Errorf("Parse() = %v, want a news with the canonical link %q", got, want)
+	}
+}
+
+func TestHtml_ParseMetadata(t *testing.T) {
+	page := `<html><body><article>
+<h2>Rates held</h2><img src="/img/rates.jpg">
+<span class="author">Jane Doe</span><span class="author">John Roe</span>
+<a class="tag">Economy</a><a class="tag"> Banks </a>
+<div class="body"><p>The bank held rates.</p></div>
+</article></body></html>`
+	profile := &entity.ScrapeProfile{
+		ItemSelector:     "article",
+		TitleSelector:    "h2",
+		AuthorSelector:   ".author",
+		CategorySelector: ".tag",
+		ImageSelector:    "img",
+		ContentSelector:  ".body",
+		BaseURL:          "https://example.com",
+	}
+
+	got, err := (&Html{}).Parse(context.Background(), strings.NewReader(page), Meta{SourceName: "example", Profile: profile})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Parse() = %d news, want 1", len(got))
+	}
+	item := got[0]
+	if want := []string{"Jane Doe", "John Roe"}; !reflect.DeepEqual(item.Authors, want) {
+		t.Errorf("Parse() authors = %q, want %q", item.Authors, want)
+	}
+	if want := []string{"Economy", "Banks"}; !reflect.DeepEqual(item.Categories, want) {
+		t.Errorf("Parse() categories = %q, want %q", item.Categories, want)
+	}
+	if want := entity.Link("https://example.com/img/rates.jpg"); item.Image != want {
+		t.Errorf("Parse() image = %q, want %q", item.Image, want)
+	}
+	if want := "<p>The bank held rates.</p>"; item.Content != want {
+		t.Errorf("Parse() content = %q, want %q", item.Content, want)
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile *entity.ScrapeProfile
+		wantErr bool
+	}{
+		{name: "valid profile", profile: &entity.ScrapeProfile{ItemSelector: "article", BaseURL: "https://www.bbc.com"}},
+		{name: "missing profile", profile: nil, wantErr: true},
+		{name: "missing item selector", profile: &entity.ScrapeProfile{TitleSelector: "h2"}, wantErr: true},
+		{name: "relative base URL", profile: &entity.ScrapeProfile{ItemSelector: "article", BaseURL: "/news"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateProfile(tt.profile); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,18 +1,17 @@
 package parser
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
+	"io"
 	"news-aggregator/internal/entity"
-	"os"
 	"time"
 )
 
 // Json represents a JSON parser for news articles.
-type Json struct {
-	FilePath entity.PathToFile
-}
+type Json struct{}
 
 // newsResponse represents the structure of the JSON response containing news articles.
 type newsResponse struct {
@@ -25,27 +24,21 @@ type newsArticle struct {
 	Description string    `json:"description"`
 	Link        string    `json:"url"`
 	Date        time.Time `json:"publishedAt"`
+	Author      string    `json:"author"`
+	Image       string    `json:"urlToImage"`
+	Content     string    `json:"content"`
 	Source      struct {
 		Name string `json:"name"`
 	} `json:"source"`
 }
 
 // Parse - implementation of a parser for files in JSON format.
-func (jsonParser *Json) Parse() ([]entity.News, error) {
-	file, err := os.Open(string(jsonParser.FilePath))
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+func (jsonParser *Json) Parse(ctx context.Context, r io.Reader, meta Meta) ([]entity.News, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	defer func(file *os.File) {
-		closeErr := file.Close()
-		if closeErr != nil && err == nil {
-			err = fmt.Errorf("error closing file: %w", closeErr)
-			return
-		}
-	}(file)
-
 	var response newsResponse
-	err = json.NewDecoder(file).Decode(&response)
+	err := json.NewDecoder(r).Decode(&response)
 	if err != nil {
 		return nil, err
 	}
@@ -58,16 +51,32 @@ func (jsonParser *Json) Parse() ([]entity.News, error) {
 			Link:        entity.Link(article.Link),
 			Date:        article.Date,
 			Source:      article.Source.Name,
+			Authors:     trimValues([]string{article.Author}),
+			Image:       entity.Link(article.Image),
+			Content:     article.Content,
 		}
 		allNews = append(allNews, news)
 	}
 	if len(allNews) == 0 {
 		return nil, errors.New("no news found")
 	}
+	setLanguage(allNews, "")
 	return allNews, nil
 }
 
-// CanParseFileType checks if the file extension is .json
-func (jsonParser *Json) CanParseFileType(ext string) bool {
-	return ext == ".json"
+func init() {
+	Register(Format{
+		Name:       "json",
+		MimeTypes:  []string{"application/json"},
+		Extensions: []string{".json"},
+		Sniff:      sniffJson,
+		Parser:     &Json{},
+	})
+}
+
+// sniffJson checks if the document is a JSON object with an articles envelope.
+func sniffJson(head []byte) bool {
+	head = trimHead(head)
+	return bytes.HasPrefix(head, []byte("{")) && bytes.Contains(head, []byte(`"articles"`)) &&
+		!sniffJsonFeed(head)
 }
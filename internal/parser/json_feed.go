@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	jsonfeed "github.com/mmcdole/gofeed/json"
+	"io"
+	"news-aggregator/internal/entity"
+	"strings"
+	"time"
+)
+
+// jsonFeedVersionPrefix identifies documents following the JSON Feed specification.
+const jsonFeedVersionPrefix = "jsonfeed.org/version/"
+
+// JsonFeed - parser for JSON Feed 1.0 and 1.1 documents (https://www.jsonfeed.org).
+type JsonFeed struct{}
+
+func init() {
+	Register(Format{
+		Name:       "jsonfeed",
+		MimeTypes:  []string{"application/feed+json"},
+		Extensions: []string{".jsonfeed"},
+		Sniff:      sniffJsonFeed,
+		Parser:     &JsonFeed{},
+	})
+}
+
+// sniffJsonFeed checks if the document declares a JSON Feed version.
+func sniffJsonFeed(head []byte) bool {
+	head = trimHead(head)
+	return bytes.HasPrefix(head, []byte("{")) && bytes.Contains(head, []byte(jsonFeedVersionPrefix))
+}
+
+// Parse - implementation of a parser for files in JSON Feed format.
+func (jsonFeedParser *JsonFeed) Parse(ctx context.Context, r io.Reader, meta Meta) ([]entity.News, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fp := jsonfeed.Parser{}
+	feed, err := fp.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(feed.Version, jsonFeedVersionPrefix) {
+		return nil, fmt.Errorf("unsupported JSON Feed version: %q", feed.Version)
+	}
+
+	var allNews []entity.News
+	for _, item := range feed.Items {
+		link := jsonFeedLink(item)
+		allNews = append(allNews, entity.News{
+			Title:       entity.Title(strings.TrimSpace(item.Title)),
+			Description: entity.Description(jsonFeedDescription(item)),
+			Link:        entity.Link(link),
+			Date:        jsonFeedDate(item),
+			Source:      feed.Title,
+			Canonical:   canonicalLink(link, item.ID),
+			GUID:        strings.TrimSpace(item.ID),
+			Authors:     jsonFeedAuthors(item),
+			Categories:  trimValues(item.Tags),
+			Image:       entity.Link(jsonFeedImage(item)),
+			Content:     jsonFeedContent(item),
+		})
+	}
+	if len(allNews) == 0 {
+		return nil, errors.New("no news found")
+	}
+	setLanguage(allNews, feed.Language)
+	return allNews, nil
+}
+
+// jsonFeedDescription prefers the item summary, then its text and HTML content.
+func jsonFeedDescription(item *jsonfeed.Item) string {
+	if summary := strings.TrimSpace(item.Summary); summary != "" {
+		return summary
+	}
+	if text := strings.TrimSpace(item.ContentText); text != "" {
+		return text
+	}
+	return stripTags(item.ContentHTML)
+}
+
+// jsonFeedLink returns the item permalink, falling back to the external URL and the ID.
+func jsonFeedLink(item *jsonfeed.Item) string {
+	switch {
+	case item.URL != "":
+		return item.URL
+	case item.ExternalURL != "":
+		return item.ExternalURL
+	case strings.HasPrefix(item.ID, "http"):
+		return item.ID
+	}
+	return ""
+}
+
+// jsonFeedAuthors returns the names of the authors of an item, of version 1.1 or 1.0.
+func jsonFeedAuthors(item *jsonfeed.Item) []string {
+	authors := item.Authors
+	if len(authors) == 0 && item.Author != nil {
+		authors = []*jsonfeed.Author{item.Author}
+	}
+	var names []string
+	for _, author := range authors {
+		names = append(names, author.Name)
+	}
+	return trimValues(names)
+}
+
+// jsonFeedImage returns the main image of an item, falling back to its banner.
+func jsonFeedImage(item *jsonfeed.Item) string {
+	if item.Image != "" {
+		return item.Image
+	}
+	return item.BannerImage
+}
+
+// jsonFeedContent returns the HTML content of an item, falling back to its text.
+func jsonFeedContent(item *jsonfeed.Item) string {
+	if content := strings.TrimSpace(item.ContentHTML); content != "" {
+		return content
+	}
+	return strings.TrimSpace(item.ContentText)
+}
+
+// jsonFeedDate returns the publication date of an item, falling back to its modification date.
+func jsonFeedDate(item *jsonfeed.Item) time.Time {
+	for _, value := range []string{item.DatePublished, item.DateModified} {
+		if value == "" {
+			continue
+		}
+		if date, err := time.Parse(time.RFC3339, value); err == nil {
+			return date.UTC()
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"news-aggregator/internal/entity"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Unit test for JSON Feed parser.
+func TestJsonFeed_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    []entity.News
+		wantErr bool
+	}{
+		{
+			name: "Test parsing valid JSON Feed file",
+			file: "../testdata/news_feed.json",
+			want: []entity.News{
+				{
+					Title:       "Switzerland's Nemo wins the Eurovision Song Contest",
+					Description: "The nonbinary singer won with the operatic pop song \"The Code\".",
+					Link:        "https://www.npr.org/2024/05/19/g-s1-155/eurovision-final",
+					Date:        time.Date(2024, 5, 19, 13, 0, 0, 0, time.UTC),
+					Source:      "NPR World",
+					Language:    "en",
+					GUID:        "https://www.npr.org/2024/05/19/g-s1-155/eurovision-final",
+					Authors:     []string{"Rachel Treisman"},
+					Categories:  []string{"Music", "Europe"},
+					Image:       "https://media.npr.org/assets/img/2024/05/19/nemo.jpg",
+				},
+				{
+					Title:       "Taiwan's lawmakers brawl over parliament reforms",
+					Description: "Scuffles broke out in Taiwan's parliament on Friday.",
+					Link:        "https://www.npr.org/2024/05/18/g-s1-120/taiwan-parliament",
+					Date:        time.Date(2024, 5, 18, 6, 30, 0, 0, time.UTC),
+					Source:      "NPR World",
+					Language:    "en",
+					GUID:        "https://www.npr.org/2024/05/18/g-s1-120/taiwan-parliament",
+					Content:     "<p>Scuffles broke out in Taiwan's parliament on Friday.</p>",
+				},
+			},
+		},
+		{
+			name:    "Test parsing NewsAPI JSON file with JSON Feed parser",
+			file:    "../testdata/news.json",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Test parsing missing JSON Feed file",
+			file:    "../testdata/nonexistent_file.json",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonFeedParser := &FileParser{
+				Path:   entity.PathToFile(tt.file),
+				Parser: &JsonFeed{},
+			}
+			got, err := jsonFeedParser.Parse()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -25,6 +25,10 @@ func TestJson_Parse(t *testing.T) {
 					Link:        "https://www.nbcnews.com/politics/politics-news/francis-scott-key-bridge-ship-removal-wes-moore-baltimore-rcna152955",
 					Date:        time.Date(2024, 5, 19, 14, 6, 47, 0, time.UTC),
 					Source:      "NBC News",
+					Language:    "en",
+					Authors:     []string{"Alexandra Marquez"},
+					Image:       "https://media-cldnry.s-nbcnews.com/image/upload/t_nbcnews-fp-1200-630,f_auto,q_auto:best/rockcms/2024-03/240329-Wes-Moore-ch-0911-bf51af.jpg",
+					Content:     "Maryland Gov. Wes Moore on Sunday said that the Dali, a massive container ship that felled the Francis Scott Key Bridge in Baltimore in March, will be removed \"within days.\"\r\n\"I remember that first m… [+1400 chars]",
 				},
 				{
 					Title:       "Harris says more Indian American representation is needed in government",
@@ -32,6 +36,10 @@ func TestJson_Parse(t *testing.T) {
 					Link:        "https://www.nbcnews.com/news/asian-america/kamala-harris-more-indian-american-representation-needed-government-rcna152761",
 					Date:        time.Date(2024, 5, 17, 19, 48, 19, 0, time.UTC),
 					Source:      "NBC News",
+					Language:    "en",
+					Authors:     []string{"Sakshi Venkatraman"},
+					Image:       "https://media-cldnry.s-nbcnews.com/image/upload/t_nbcnews-fp-1200-630,f_auto,q_auto:best/rockcms/2024-05/240517-kamala-harris-se-1157a-649838.jpg",
+					Content:     "Addressing a crowd of Indian Americans this week, Vice President Kamala Harris asserted the importance of South Asians running for office, saying theres not nearly enough compared to the groups size … [+3872 chars]",
 				},
 				{
 					Title:       "Atlanta officer accused of killing Lyft driver allegedly said victim was ‘gay fraternity’ recruiter",
@@ -39,6 +47,10 @@ func TestJson_Parse(t *testing.T) {
 					Link:        "https://www.nbcnews.com/nbc-out/out-news/atlanta-officer-accused-killing-lyft-driver-allegedly-said-victim-was-rcna152751",
 					Date:        time.Date(2024, 5, 17, 14, 29, 43, 0, time.UTC),
 					Source:      "NBC News",
+					Language:    "en",
+					Authors:     []string{"The Associated Press"},
+					Image:       "https://media-cldnry.s-nbcnews.com/image/upload/t_nbcnews-fp-1200-630,f_auto,q_auto:best/rockcms/2024-05/240517-lyft-se1023a-748202.jpg",
+					Content:     "UNION CITY, Ga. An Atlanta police officer who shot and killed a Lyft driver who was driving him home was arrested and charged with murder, authorities said.\r\nKoby Minor, 34, was being held without bo… [+1513 chars]",
 				},
 			},
 		},
@@ -58,8 +70,9 @@ func TestJson_Parse(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			jsonParser := &Json{
-				FilePath: entity.PathToFile(tt.file),
+			jsonParser := &FileParser{
+				Path:   entity.PathToFile(tt.file),
+				Parser: &Json{},
 			}
 			got, err := jsonParser.Parse()
 			if (err != nil) != tt.wantErr {
@@ -1,32 +1,167 @@
 package parser
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"news-aggregator/internal/canonical"
 	"news-aggregator/internal/entity"
+	"news-aggregator/internal/language"
+	"os"
 	"path/filepath"
 	"strings"
 )
 
-// Parser provides an API for a news parser capable of processing a specific file type.
+// sniffLength is the number of leading bytes inspected to detect the format.
+const sniffLength = 1024
+
+// Meta describes the origin of the data handed to a parser.
+type Meta struct {
+	// SourceName is the name of the source the data belongs to, if known.
+	SourceName string
+	// Location is the URL or file path the data was read from.
+	Location string
+	// ContentType is the media type the data was served with, if known.
+	ContentType string
+	// Profile describes how to scrape an HTML page, if the source has one.
+	Profile *entity.ScrapeProfile
+}
+
+// Parser provides an API for a news parser capable of processing a specific format
+// read from a stream, such as an HTTP body, an archive entry or a file.
 type Parser interface {
-	CanParseFileType(ext string) bool
-	Parse() ([]entity.News, error)
+	Parse(ctx context.Context, r io.Reader, meta Meta) ([]entity.News, error)
 }
 
-// GetFileParser returns the appropriate parser implementation based on the path to file.
-func GetFileParser(path entity.PathToFile) (Parser, error) {
-	ext := strings.ToLower(filepath.Ext(string(path)))
+// Parse detects the format of the data in r and parses it with the registered parser.
+// The content type in meta is used when the data itself is not recognised.
+// Data of sources with a scrape profile is always parsed by the Html parser.
+func Parse(ctx context.Context, r io.Reader, meta Meta) ([]entity.News, error) {
+	if meta.Profile != nil {
+		return (&Html{}).Parse(ctx, r, meta)
+	}
+	format, body, err := Detect(r)
+	if errors.Is(err, ErrUnknownFormat) && meta.ContentType != "" {
+		format, err = ForMimeType(meta.ContentType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return format.Parser.Parse(ctx, body, meta)
+}
+
+// setLanguage of the news to the language the document declares or, when it declares none,
+// to the language detected from the text of each news.
+func setLanguage(news []entity.News, declared string) {
+	declared = language.Normalize(declared)
+	for i := range news {
+		if news[i].Language != "" {
+			continue
+		}
+		if declared != "" {
+			news[i].Language = declared
+			continue
+		}
+		news[i].Language = language.Detect(string(news[i].Title) + " " + string(news[i].Description))
+	}
+}
+
+// canonicalLink declared for a news with the given link, such as a permalink GUID.
+// It is empty when the declared value is not a URL or it is the link itself.
+func canonicalLink(link, declared string) entity.Link {
+	if !canonical.IsURL(declared) || canonical.URL(declared) == canonical.URL(link) {
+		return ""
+	}
+	return entity.Link(strings.TrimSpace(declared))
+}
+
+// trimValues trims the values and drops the empty ones, nil when none is left.
+func trimValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
 
-	parsers := []Parser{
-		&Rss{FilePath: path},
-		&Json{FilePath: path},
-		&UsaToday{FilePath: path},
+// FileParser adapts a Parser to a file on disk.
+type FileParser struct {
+	Path   entity.PathToFile
+	Parser Parser
+}
+
+// Parse opens the file and parses its content.
+func (f *FileParser) Parse() ([]entity.News, error) {
+	file, err := os.Open(string(f.Path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func(file *os.File) {
+		closeErr := file.Close()
+		if closeErr != nil && err == nil {
+			err = fmt.Errorf("error closing file: %w", closeErr)
+			return
+		}
+	}(file)
+	return f.Parser.Parse(context.Background(), file, Meta{Location: string(f.Path)})
+}
+
+// GetFileParser returns the appropriate parser implementation based on the path to file.
+// The format is detected from the file content through the registry,
+// the file extension is used only when the content is not recognised.
+func GetFileParser(path entity.PathToFile) (*FileParser, error) {
+	if file, err := os.Open(string(path)); err == nil {
+		format, _, err := Detect(file)
+		_ = file.Close()
+		if err == nil {
+			return &FileParser{Path: path, Parser: format.Parser}, nil
+		}
+	}
+	format, err := ForExtension(strings.ToLower(filepath.Ext(string(path))))
+	if err != nil {
+		return nil, err
 	}
+	return &FileParser{Path: path, Parser: format.Parser}, nil
+}
+
+// trimHead drops the byte order mark and leading whitespace.
+func trimHead(head []byte) []byte {
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	return bytes.TrimLeft(head, " \t\r\n")
+}
 
-	for _, p := range parsers {
-		if p.CanParseFileType(ext) {
-			return p, nil
+// xmlRootElement returns the lower-cased name of the first element in an XML document,
+// skipping the prolog, comments and doctype declarations.
+func xmlRootElement(head []byte) string {
+	head = trimHead(head)
+	for len(head) > 0 && head[0] == '<' {
+		if bytes.HasPrefix(head, []byte("<?")) || bytes.HasPrefix(head, []byte("<!")) {
+			end := bytes.IndexByte(head, '>')
+			if end < 0 {
+				return ""
+			}
+			head = trimHead(head[end+1:])
+			continue
 		}
+		name := head[1:]
+		if end := bytes.IndexAny(name, " \t\r\n/>"); end >= 0 {
+			name = name[:end]
+		}
+		return strings.ToLower(string(name))
+	}
+	return ""
+}
+
+// stripTags returns the text content of an HTML fragment.
+func stripTags(html string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return strings.TrimSpace(html)
 	}
-	return nil, fmt.Errorf("unsupported file type: %s", ext)
+	return strings.TrimSpace(doc.Text())
 }
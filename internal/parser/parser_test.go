@@ -1,8 +1,10 @@
 package parser
 
 import (
+	"context"
 	"news-aggregator/internal/entity"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -13,22 +15,32 @@ func TestNew(t *testing.T) {
 	tests := []struct {
 		name string
 		args args
-		want Parser
+		want *FileParser
 	}{
 		{
 			name: "RSS file",
 			args: args{Path: "../testdata/news.xml"},
-			want: &Rss{FilePath: "../testdata/news.xml"},
+			want: &FileParser{Path: "../testdata/news.xml", Parser: &Rss{}},
 		},
 		{
 			name: "JSON file",
 			args: args{Path: "../testdata/news.json"},
-			want: &Json{FilePath: "../testdata/news.json"},
+			want: &FileParser{Path: "../testdata/news.json", Parser: &Json{}},
+		},
+		{
+			name: "Atom file with xml extension",
+			args: args{Path: "../testdata/news_atom.xml"},
+			want: &FileParser{Path: "../testdata/news_atom.xml", Parser: &Atom{}},
+		},
+		{
+			name: "JSON Feed file with json extension",
+			args: args{Path: "../testdata/news_feed.json"},
+			want: &FileParser{Path: "../testdata/news_feed.json", Parser: &JsonFeed{}},
 		},
 		{
 			name: "HTML file",
 			args: args{Path: "../testdata/news.html"},
-			want: &UsaToday{FilePath: "../testdata/news.html"},
+			want: &FileParser{Path: "../testdata/news.html", Parser: &UsaToday{}},
 		},
 		{
 			name: "Unsupported file type",
@@ -45,3 +57,91 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestParse(t *testing.T) {
+	const rss = `<rss version="2.0"><channel><title>Mock News</title>
+		<item><title>Mock Title</title><link>https://mock.link</link>
+		<pubDate>Mon, 01 Jan 2001 00:00:00 +0000</pubDate></item></channel></rss>`
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		data    string
+		meta    Meta
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "should detect format from content",
+			ctx:  context.Background(),
+			data: rss,
+			meta: Meta{ContentType: "application/octet-stream"},
+			want: 1,
+		},
+		{
+			name:    "should fail on unknown content and content type",
+			ctx:     context.Background(),
+			data:    "plain text",
+			meta:    Meta{ContentType: "text/plain"},
+			wantErr: true,
+		},
+		{
+			name:    "should fail on cancelled context",
+			ctx:     cancelled,
+			data:    rss,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.ctx, strings.NewReader(tt.data), tt.meta)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("Parse() got %d news, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	news := []entity.News{
+		{Title: "Уряд ухвалив рішення про бюджет"},
+		{Title: "Die Regierung und der Bundestag"},
+		{Title: "Already known", Language: "fr"},
+	}
+	setLanguage(news, "")
+	if news[0].Language != "uk" || news[1].Language != "de" || news[2].Language != "fr" {
+		t.Errorf("setLanguage() without declared language = %q, %q, %q", news[0].Language, news[1].Language, news[2].Language)
+	}
+
+	news = []entity.News{{Title: "Die Regierung und der Bundestag"}}
+	setLanguage(news, "de-AT")
+	if news[0].Language != "de" {
+		t.Errorf("setLanguage() with declared language = %q, want de", news[0].Language)
+	}
+}
+
+func TestCanonicalLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     string
+		declared string
+		want     entity.Link
+	}{
+		{name: "permalink GUID", link: "https://example.com/a?utm_source=rss", declared: "https://example.com/articles/1", want: "https://example.com/articles/1"},
+		{name: "GUID is the link", link: "http://www.example.com/a/?utm_source=rss", declared: "https://example.com/a", want: ""},
+		{name: "GUID is not a URL", link: "https://example.com/a", declared: "urn:uuid:1225c695", want: ""},
+		{name: "no GUID", link: "https://example.com/a", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canonicalLink(tt.link, tt.declared); got != tt.want {
+				t.Errorf("canonicalLink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownFormat is returned when no registered format recognises the data.
+var ErrUnknownFormat = errors.New("unknown news format")
+
+// Format describes a news format known to the parser registry.
+type Format struct {
+	// Name uniquely identifies the format, e.g. "rss" or "atom".
+	Name string
+	// MimeTypes served for the format, used when the content is not recognised.
+	MimeTypes []string
+	// Extensions of files in the format, the first one is preferred.
+	Extensions []string
+	// Sniff reports whether the leading bytes of a document belong to the format.
+	Sniff func(head []byte) bool
+	// Parser reads documents in the format.
+	Parser Parser
+}
+
+// registry of the formats in registration order, which is also the sniffing order.
+var registry struct {
+	sync.RWMutex
+	formats []Format
+}
+
+// Register adds the format to the registry.
+// It panics if the name is empty or a format with the same name is already registered.
+func Register(format Format) {
+	registry.Lock()
+	defer registry.Unlock()
+	if format.Name == "" || format.Parser == nil {
+		panic("parser: Register format without name or parser")
+	}
+	for _, f := range registry.formats {
+		if f.Name == format.Name {
+			panic("parser: Register called twice for format " + format.Name)
+		}
+	}
+	registry.formats = append(registry.formats, format)
+}
+
+// Formats returns all registered formats in sniffing order.
+func Formats() []Format {
+	registry.RLock()
+	defer registry.RUnlock()
+	return append([]Format(nil), registry.formats...)
+}
+
+// Lookup returns the registered format with the given name.
+func Lookup(name string) (Format, bool) {
+	for _, f := range Formats() {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return Format{}, false
+}
+
+// Detect picks the format from the first bytes of the reader.
+// The returned reader replays the inspected bytes followed by the rest of r,
+// so it can be passed on for parsing even when detection fails.
+func Detect(r io.Reader) (Format, io.Reader, error) {
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return Format{}, nil, fmt.Errorf("failed to read data: %w", err)
+	}
+	head = head[:n]
+	replay := io.MultiReader(bytes.NewReader(head), r)
+	for _, f := range Formats() {
+		if f.Sniff != nil && f.Sniff(head) {
+			return f, replay, nil
+		}
+	}
+	return Format{}, replay, ErrUnknownFormat
+}
+
+// ForMimeType returns the format registered for the media type of a Content-Type header.
+func ForMimeType(contentType string) (Format, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return Format{}, fmt.Errorf("%w: content type %q", ErrUnknownFormat, contentType)
+	}
+	for _, f := range Formats() {
+		for _, m := range f.MimeTypes {
+			if strings.EqualFold(m, mediaType) {
+				return f, nil
+			}
+		}
+	}
+	return Format{}, fmt.Errorf("%w: content type %q", ErrUnknownFormat, mediaType)
+}
+
+// ForExtension returns the format registered for the file extension.
+func ForExtension(ext string) (Format, error) {
+	for _, f := range Formats() {
+		for _, e := range f.Extensions {
+			if strings.EqualFold(e, ext) {
+				return f, nil
+			}
+		}
+	}
+	return Format{}, fmt.Errorf("%w: file type %s", ErrUnknownFormat, ext)
+}
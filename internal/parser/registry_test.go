@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{name: "RSS file", file: "../testdata/news.xml", want: "rss"},
+		{name: "Atom file", file: "../testdata/news_atom.xml", want: "atom"},
+		{name: "NewsAPI JSON file", file: "../testdata/news.json", want: "json"},
+		{name: "JSON Feed file", file: "../testdata/news_feed.json", want: "jsonfeed"},
+		{name: "HTML file", file: "../testdata/news.html", want: "usa_today"},
+		{name: "Unknown JSON document", file: "../testdata/invalid_news.json", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", tt.file, err)
+			}
+			got, replay, err := Detect(strings.NewReader(string(data)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Detect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrUnknownFormat) {
+				t.Errorf("Detect() error = %v, want ErrUnknownFormat", err)
+			}
+			if got.Name != tt.want {
+				t.Errorf("Detect() = %q, want %q", got.Name, tt.want)
+			}
+			replayed, err := io.ReadAll(replay)
+			if err != nil {
+				t.Fatalf("failed to read replayed data: %v", err)
+			}
+			if string(replayed) != string(data) {
+				t.Errorf("Detect() replayed %d bytes, want %d", len(replayed), len(data))
+			}
+		})
+	}
+}
+
+func TestForMimeType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+		wantErr     bool
+	}{
+		{contentType: "application/rss+xml; charset=utf-8", want: "rss"},
+		{contentType: "text/xml", want: "rss"},
+		{contentType: "application/atom+xml", want: "atom"},
+		{contentType: "application/feed+json", want: "jsonfeed"},
+		{contentType: "application/json", want: "json"},
+		{contentType: "text/html; charset=utf-8", want: "usa_today"},
+		{contentType: "text/plain", wantErr: true},
+		{contentType: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			got, err := ForMimeType(tt.contentType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ForMimeType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Name != tt.want {
+				t.Errorf("ForMimeType() = %q, want %q", got.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() expected panic for duplicate format name")
+		}
+	}()
+	rss, _ := Lookup("rss")
+	Register(rss)
+}
@@ -1,54 +1,174 @@
 package parser
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/rss"
+	"io"
 	"news-aggregator/internal/entity"
-	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Rss - parser for RSS files.
-type Rss struct {
-	FilePath entity.PathToFile
+type Rss struct{}
+
+func init() {
+	Register(Format{
+		Name:       "rss",
+		MimeTypes:  []string{"application/rss+xml", "application/rdf+xml", "application/xml", "text/xml"},
+		Extensions: []string{".xml", ".rss"},
+		Sniff:      sniffRss,
+		Parser:     &Rss{},
+	})
 }
 
-func (rssParser *Rss) CanParseFileType(ext string) bool {
-	return ext == ".xml" || ext == ".rss"
+// sniffRss checks if the document root is an RSS or RDF element.
+func sniffRss(head []byte) bool {
+	root := xmlRootElement(head)
+	return root == "rss" || root == "rdf:rdf"
 }
 
 // Parse - implementation of a parser for files in RSS format.
-func (rssParser *Rss) Parse() ([]entity.News, error) {
-	file, err := os.Open(string(rssParser.FilePath))
-	if err != nil {
+func (rssParser *Rss) Parse(ctx context.Context, r io.Reader, meta Meta) ([]entity.News, error) {
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-	defer func(file *os.File) {
-		closeErr := file.Close()
-		if closeErr != nil && err == nil {
-			err = fmt.Errorf("error closing file: %w", closeErr)
-			return
-		}
-	}(file)
-
+	translator := &rssTranslator{}
 	fp := gofeed.NewParser()
-	feed, err := fp.Parse(file)
+	fp.RSSTranslator = translator
+	feed, err := fp.Parse(r)
 	if err != nil {
 		return nil, err
 	}
 
 	var allNews []entity.News
-	for _, item := range feed.Items {
+	for i, item := range feed.Items {
+		var canonical entity.Link
+		if translator.permalink(i) {
+			canonical = canonicalLink(item.Link, item.GUID)
+		}
 		allNews = append(allNews, entity.News{
 			Title:       entity.Title(item.Title),
 			Description: entity.Description(item.Description),
 			Link:        entity.Link(item.Link),
-			Date:        *item.PublishedParsed,
+			Date:        rssDate(item),
 			Source:      feed.Title,
+			Canonical:   canonical,
+			GUID:        strings.TrimSpace(item.GUID),
+			Authors:     rssAuthors(item),
+			Categories:  trimValues(item.Categories),
+			Image:       entity.Link(rssImage(item)),
+			Content:     strings.TrimSpace(item.Content),
 		})
 	}
 	if len(allNews) == 0 {
 		return nil, errors.New("no news found")
 	}
+	setLanguage(allNews, feed.Language)
 	return allNews, nil
 }
+
+// rssTranslator keeps the RSS feed translated by gofeed for the attributes the universal items drop.
+type rssTranslator struct {
+	gofeed.DefaultRSSTranslator
+	feed *rss.Feed
+}
+
+func (t *rssTranslator) Translate(feed interface{}) (*gofeed.Feed, error) {
+	t.feed, _ = feed.(*rss.Feed)
+	return t.DefaultRSSTranslator.Translate(feed)
+}
+
+// permalink reports if the GUID of the i-th item is declared a permalink. The GUIDs without
+// the attribute are not trusted, gofeed reads it as isPermalink and misses the isPermaLink="false"
+// of opaque URL-shaped GUIDs, which would merge unrelated articles.
+func (t *rssTranslator) permalink(i int) bool {
+	if t.feed == nil || i >= len(t.feed.Items) || t.feed.Items[i].GUID == nil {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(t.feed.Items[i].GUID.IsPermalink), "true")
+}
+
+// rssDate returns the publication date of an item, falling back to its update date,
+// the zero time when it has none.
+func rssDate(item *gofeed.Item) time.Time {
+	if item.PublishedParsed != nil {
+		return *item.PublishedParsed
+	}
+	if item.UpdatedParsed != nil {
+		return *item.UpdatedParsed
+	}
+	return time.Time{}
+}
+
+// rssAuthors of an item, from <author> and <dc:creator>.
+func rssAuthors(item *gofeed.Item) []string {
+	var names []string
+	for _, author := range item.Authors {
+		if author.Name != "" {
+			names = append(names, author.Name)
+		} else {
+			names = append(names, author.Email)
+		}
+	}
+	return trimValues(names)
+}
+
+// rssImage of an item: its iTunes image, a <media:thumbnail>, an image <media:content>
+// or an image enclosure.
+func rssImage(item *gofeed.Item) string {
+	if item.Image != nil && item.Image.URL != "" {
+		return item.Image.URL
+	}
+	for _, thumbnail := range item.Extensions["media"]["thumbnail"] {
+		if url := thumbnail.Attrs["url"]; url != "" {
+			return url
+		}
+	}
+	for _, content := range item.Extensions["media"]["content"] {
+		if url := content.Attrs["url"]; url != "" && (content.Attrs["medium"] == "image" || strings.HasPrefix(content.Attrs["type"], "image/")) {
+			return url
+		}
+	}
+	for _, enclosure := range item.Enclosures {
+		if strings.HasPrefix(enclosure.Type, "image/") {
+			return enclosure.URL
+		}
+	}
+	return ""
+}
+
+// Hints a feed gives on how often it should be polled.
+type Hints struct {
+	// TTL is the number of minutes the channel may be cached, from <ttl>.
+	TTL time.Duration
+	// SkipHours are the UTC hours the channel should not be read, from <skipHours>.
+	SkipHours []int
+}
+
+// RssHints reads <ttl> and <skipHours> of a RSS channel.
+// Other documents have no hints, they are only sniffed and not parsed.
+func RssHints(r io.Reader) Hints {
+	var hints Hints
+	format, r, err := Detect(r)
+	if err != nil || format.Name != "rss" {
+		return hints
+	}
+	fp := rss.Parser{}
+	feed, err := fp.Parse(r)
+	if err != nil {
+		return hints
+	}
+	if ttl, err := strconv.Atoi(strings.TrimSpace(feed.TTL)); err == nil && ttl > 0 {
+		hints.TTL = time.Duration(ttl) * time.Minute
+	}
+	for _, h := range feed.SkipHours {
+		if hour, err := strconv.Atoi(strings.TrimSpace(h)); err == nil && hour >= 0 && hour < 24 {
+			hints.SkipHours = append(hints.SkipHours, hour)
+		}
+	}
+	return hints
+}
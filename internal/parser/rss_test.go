@@ -1,8 +1,10 @@
 package parser
 
 import (
+	"context"
 	"news-aggregator/internal/entity"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -25,6 +27,11 @@ func TestRss_Parse(t *testing.T) {
 					Link:        "https://www.bbc.com/news/articles/cnee7lp7mgdo",
 					Date:        time.Date(2024, 5, 19, 12, 20, 49, 0, time.UTC),
 					Source:      "BBC News",
+					Language:    "en",
+					GUID:        "https://www.bbc.com/news/articles/cnee7lp7mgdo#0",
+					Authors:     []string{"BBC North East"},
+					Categories:  []string{"UK", "Tyne and Wear"},
+					Image:       "https://ichef.bbci.co.uk/news/240/cpsprodpb/801d/live/33a07f10-15cd-11ef-9b12-1ba8f95c4917.jpg",
 				},
 				{
 					Title:       "Su and Steve fought for justice, but didn't live to see it",
@@ -32,6 +39,9 @@ func TestRss_Parse(t *testing.T) {
 					Link:        "https://www.bbc.co.uk/news/health-69018125",
 					Date:        time.Date(2024, 5, 18, 23, 5, 19, 0, time.UTC),
 					Source:      "BBC News",
+					Language:    "en",
+					GUID:        "https://www.bbc.co.uk/news/health-69018125#0",
+					Image:       "https://ichef.bbci.co.uk/ace/standard/240/cpsprodpb/485A/production/_133322581_susteve.jpg",
 				},
 			},
 			wantErr: false,
@@ -52,8 +62,9 @@ func TestRss_Parse(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rssParser := &Rss{
-				FilePath: entity.PathToFile(tt.file),
+			rssParser := &FileParser{
+				Path:   entity.PathToFile(tt.file),
+				Parser: &Rss{},
 			}
 			got, err := rssParser.Parse()
 			if (err != nil) != tt.wantErr {
@@ -66,3 +77,87 @@ func TestRss_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestRss_ParseWithoutDate(t *testing.T) {
+	feed := `<rss version="2.0"><channel><title>BBC News</title>
+<item><title>Undated</title><link>https://bbc.com/1</link></item>
+<item><title>Dated</title><link>https://bbc.com/2</link><pubDate>Sun, 19 May 2024 12:20:49 GMT</pubDate></item>
+</channel></rss>`
+	got, err := (&Rss{}).Parse(context.Background(), strings.NewReader(feed), Meta{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Parse() got %d news, want 2", len(got))
+	}
+	if !got[0].Date.IsZero() {
+		t.Errorf("Date of a news without pubDate = %v, want the zero time", got[0].Date)
+	}
+	if want := time.Date(2024, 5, 19, 12, 20, 49, 0, time.UTC); !got[1].Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", got[1].Date, want)
+	}
+}
+
+func TestRss_ParseCanonical(t *testing.T) {
+	tests := []struct {
+		name string
+		guid string
+		want entity.Link
+	}{
+		{name: "permalink", guid: `<guid isPermalink="true">https://bbc.com/news/1</guid>`, want: "https://bbc.com/news/1"},
+		{name: "not a permalink", guid: `<guid isPermaLink="false">https://bbc.com/?p=1</guid>`},
+		{name: "no attribute", guid: `<guid>https://bbc.com/?p=1</guid>`},
+		{name: "not a URL", guid: `<guid isPermalink="true">1234</guid>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := `<rss version="2.0"><channel><title>BBC News</title><item><title>News</title>
+<link>https://bbc.com/rss/1</link>` + tt.guid + `</item></channel></rss>`
+			got, err := (&Rss{}).Parse(context.Background(), strings.NewReader(feed), Meta{})
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got[0].Canonical != tt.want {
+				t.Errorf("Canonical = %q, want %q", got[0].Canonical, tt.want)
+			}
+		})
+	}
+}
+
+func TestRssHints(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want Hints
+	}{
+		{
+			name: "ttl and skip hours",
+			doc: `<?xml version="1.0"?><rss version="2.0"><channel><title>t</title><ttl>30</ttl>
+				<skipHours><hour>0</hour><hour>23</hour><hour>24</hour></skipHours></channel></rss>`,
+			want: Hints{TTL: 30 * time.Minute, SkipHours: []int{0, 23}},
+		},
+		{
+			name: "no hints",
+			doc:  `<?xml version="1.0"?><rss version="2.0"><channel><title>t</title></channel></rss>`,
+			want: Hints{},
+		},
+		{
+			name: "not rss",
+			doc:  `{"articles": []}`,
+			want: Hints{},
+		},
+		{
+			name: "atom",
+			doc:  `<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"><title>t</title><ttl>30</ttl></feed>`,
+			want: Hints{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RssHints(strings.NewReader(tt.doc))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RssHints() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,12 +1,13 @@
 package parser
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
+	"io"
 	"log"
 	"news-aggregator/internal/entity"
-	"os"
 	"regexp"
 	"strings"
 	"time"
@@ -23,30 +24,33 @@ var descriptionSelector = "data-c-br"
 // dateSelector to extract News date in Usa today.
 var dateSelector = "div.gnt_m_flm_sbt"
 
+// imageSelector to extract News thumbnails in Usa today, lazily loaded from data-gl-src.
+var imageSelector = "img.gnt_m_flm_i"
+
 // UsaToday - parser for HTML files from Usa Today news resource.
-type UsaToday struct {
-	FilePath entity.PathToFile
+type UsaToday struct{}
+
+func init() {
+	Register(Format{
+		Name:       "usa_today",
+		MimeTypes:  []string{"text/html", "application/xhtml+xml"},
+		Extensions: []string{".html", ".htm"},
+		Sniff:      sniffHtml,
+		Parser:     &UsaToday{},
+	})
 }
 
-func (usaTodayParser *UsaToday) CanParseFileType(ext string) bool {
-	return ext == ".html"
+// sniffHtml checks if the document root is an HTML element.
+func sniffHtml(head []byte) bool {
+	return xmlRootElement(head) == "html"
 }
 
 // Parse - implementation of a parser for files in HTML format.
-func (usaTodayParser *UsaToday) Parse() ([]entity.News, error) {
-	file, err := os.Open(string(usaTodayParser.FilePath))
-	if err != nil {
+func (usaTodayParser *UsaToday) Parse(ctx context.Context, r io.Reader, meta Meta) ([]entity.News, error) {
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-	defer func(file *os.File) {
-		closeErr := file.Close()
-		if closeErr != nil && err == nil {
-			err = fmt.Errorf("error closing file: %w", closeErr)
-			return
-		}
-	}(file)
-
-	doc, err := goquery.NewDocumentFromReader(file)
+	doc, err := goquery.NewDocumentFromReader(r)
 	if err != nil {
 		log.Fatal(err)
 		return nil, err
@@ -88,17 +92,24 @@ func (usaTodayParser *UsaToday) Parse() ([]entity.News, error) {
 			formattedDateStr = time.Now().Format(OutputLayout)
 			formattedDate, err = time.Parse(OutputLayout, formattedDateStr)
 		}
+		image := s.Find(imageSelector).AttrOr("data-gl-src", "")
+		if image != "" && !strings.HasPrefix(image, "http") {
+			image = baseURL + image
+		}
 		allNews = append(allNews, entity.News{
 			Title:       entity.Title(strings.TrimSpace(title)),
 			Description: entity.Description(strings.TrimSpace(description)),
 			Link:        entity.Link(strings.TrimSpace(link)),
 			Date:        formattedDate,
 			Source:      "usa_today",
+			Categories:  trimValues([]string{s.Find(dateSelector).AttrOr("data-c-ms", "")}),
+			Image:       entity.Link(image),
 		})
 
 	})
 	if len(allNews) == 0 {
 		return nil, errors.New("no news found")
 	}
+	setLanguage(allNews, doc.Find("html").AttrOr("lang", ""))
 	return allNews, nil
 }
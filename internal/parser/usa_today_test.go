@@ -23,6 +23,9 @@ func TestUsaToday_Parse(t *testing.T) {
 				Link:        "https://www.usatoday.com/videos/news/world/2024/05/15/astronomers-discover-an-enormous-planet-made-of-something-as-light-as-cotton-candy/73697406007/",
 				Date:        time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC),
 				Source:      "usa_today",
+				Language:    "en",
+				Categories:  []string{"WORLD"},
+				Image:       "https://www.usatoday.com/gcdn/authoring/videos/buzz60/thumbnails/73697406007.jpg?width=120&height=120&fit=crop&format=pjpg&auto=webp",
 			},
 			{
 				Title:       "Ukraine's Zelenskyy cancels all foreign trips as Russian offensive intensifies",
@@ -30,6 +33,9 @@ func TestUsaToday_Parse(t *testing.T) {
 				Link:        "https://www.usatoday.com/story/news/world/2024/05/15/ukraine-zelenskyy-cancels-foreign-trips-russian-offensive-blinken-visit/73697239007/",
 				Date:        time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC),
 				Source:      "usa_today",
+				Language:    "en",
+				Categories:  []string{"WORLD"},
+				Image:       "https://www.usatoday.com/gcdn/authoring/authoring-images/2024/05/15/USAT/73697305007-20240510-t-124803-z-1288836690-rc-2-mn-7-ajom-2-a-rtrmadp-3-ukrainecrisiskharkivassaultzelenskiy.JPG?crop=1747,1747,x423,y0&width=120&height=120&format=pjpg&auto=webp",
 			},
 			{
 				Title:       "King Charles unveils first official portrait",
@@ -37,6 +43,9 @@ func TestUsaToday_Parse(t *testing.T) {
 				Link:        "https://www.usatoday.com/videos/news/world/2024/05/14/king-charles-iii-first-portrait-since-his-coronation-unveiled/73689220007/",
 				Date:        time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC),
 				Source:      "usa_today",
+				Language:    "en",
+				Categories:  []string{"WORLD"},
+				Image:       "https://www.usatoday.com/gcdn/authoring/authoring-images/2024/05/14/USAT/73689238007-king-charles-portrait.jpg?crop=1080,1078,x422,y2&width=120&height=120&format=pjpg&auto=webp",
 			},
 		},
 		wantErr: false,
@@ -56,8 +65,9 @@ func TestUsaToday_Parse(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			usaTodayParser := &UsaToday{
-				FilePath: entity.PathToFile(tt.file),
+			usaTodayParser := &FileParser{
+				Path:   entity.PathToFile(tt.file),
+				Parser: &UsaToday{},
 			}
 			got, err := usaTodayParser.Parse()
 			if (err != nil) != tt.wantErr {
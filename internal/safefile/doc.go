@@ -0,0 +1,5 @@
+// Package safefile writes files atomically and locks them across processes, so that
+// the server and the news fetcher cronjob can share the sources file and the news folder.
+// A crash or a full disk in the middle of a write leaves the previous version of the file,
+// files that are corrupt nonetheless are moved aside by Quarantine.
+package safefile
@@ -0,0 +1,102 @@
+package safefile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// tempInfix marks the temporary files of WriteFile, they are named ".<name>.tmp-<random>".
+const tempInfix = ".tmp-"
+
+// WriteFile writes the data to a temporary file in the directory of the path, syncs it
+// and renames it over the path, so readers see either the previous or the new content.
+// Like os.WriteFile it fails for an existing file that cannot be written, whose mode is kept.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	if info, err := os.Stat(path); err == nil {
+		file, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		file.Close()
+		perm = info.Mode().Perm()
+	}
+	dir := filepath.Dir(path)
+	temp, err := os.CreateTemp(dir, "."+filepath.Base(path)+tempInfix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(temp.Name(), perm); err != nil {
+		return err
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// IsTemp reports if the name is of a temporary file of WriteFile.
+func IsTemp(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.Contains(name, tempInfix)
+}
+
+// CleanTemp removes the temporary files a crash in the middle of WriteFile left for the path.
+// The caller holds the lock of the path, so that no write in progress loses its file.
+func CleanTemp(path string) ([]string, error) {
+	prefix := "." + filepath.Base(path) + tempInfix
+	return cleanTemp(filepath.Dir(path), func(name string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+}
+
+// CleanTempDir removes the temporary files of all files of the directory,
+// the caller holds the locks of all of them.
+func CleanTempDir(dir string) ([]string, error) {
+	return cleanTemp(dir, IsTemp)
+}
+
+func cleanTemp(dir string, match func(name string) bool) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, entry := range entries {
+		if entry.IsDir() || !match(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+// Quarantine a corrupt file by renaming it to "<name>.corrupt-<time>" next to it,
+// readers no longer find it and it is kept for inspection. It returns the new path.
+func Quarantine(path string) (string, error) {
+	quarantined := fmt.Sprintf("%s.corrupt-%s", path, time.Now().UTC().Format("20060102T150405.000000000"))
+	if err := os.Rename(path, quarantined); err != nil {
+		return "", err
+	}
+	return quarantined, syncDir(filepath.Dir(path))
+}
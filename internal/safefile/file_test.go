@@ -0,0 +1,100 @@
+package safefile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sources.json")
+
+	if err := WriteFile(path, []byte(`[]`), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := WriteFile(path, []byte(`[{"Name":"bbc"}]`), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `[{"Name":"bbc"}]` {
+		t.Errorf("WriteFile() content = %s", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("WriteFile() mode = %v, want the mode of the replaced file", info.Mode().Perm())
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("WriteFile() left %d files, want only the written one", len(entries))
+	}
+}
+
+func TestWriteFile_ReadOnly(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write read-only files")
+	}
+	path := filepath.Join(t.TempDir(), "sources.json")
+	if err := os.WriteFile(path, []byte(`[]`), 0444); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, []byte(`[{}]`), 0644); err == nil {
+		t.Errorf("WriteFile() expected an error for a read-only file")
+	}
+}
+
+func TestCleanTemp(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"2024-05-01.json", ".2024-05-01.json.tmp-123", ".2024-05-02.json.tmp-456", "2024-05.json.gz"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	removed, err := CleanTemp(filepath.Join(dir, "2024-05-01.json"))
+	if err != nil {
+		t.Fatalf("CleanTemp() error = %v", err)
+	}
+	if len(removed) != 1 || filepath.Base(removed[0]) != ".2024-05-01.json.tmp-123" {
+		t.Errorf("CleanTemp() removed = %v", removed)
+	}
+	removed, err = CleanTempDir(dir)
+	if err != nil {
+		t.Fatalf("CleanTempDir() error = %v", err)
+	}
+	if len(removed) != 1 || filepath.Base(removed[0]) != ".2024-05-02.json.tmp-456" {
+		t.Errorf("CleanTempDir() removed = %v", removed)
+	}
+	if removed, err := CleanTempDir(filepath.Join(dir, "missing")); err != nil || removed != nil {
+		t.Errorf("CleanTempDir() of a missing directory = %v, %v", removed, err)
+	}
+}
+
+func TestQuarantine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "2024-05-01.json")
+	if err := os.WriteFile(path, []byte(`[{`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	quarantined, err := Quarantine(path)
+	if err != nil {
+		t.Fatalf("Quarantine() error = %v", err)
+	}
+	if !strings.HasPrefix(quarantined, path+".corrupt-") {
+		t.Errorf("Quarantine() path = %s", quarantined)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Quarantine() kept the file, error = %v", err)
+	}
+	if data, err := os.ReadFile(quarantined); err != nil || string(data) != `[{` {
+		t.Errorf("Quarantine() content = %s, %v", data, err)
+	}
+}
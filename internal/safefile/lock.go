@@ -0,0 +1,69 @@
+package safefile
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// mutexes of the locked paths by their absolute path. They exclude the goroutines of
+// this process also where the file lock does not, for instance on the other systems.
+var mutexes sync.Map
+
+// Lock is an advisory lock of a file held by this process until Unlock. It is taken on
+// a separate lock file, "<path>.lock", so that the locked file itself can be replaced.
+type Lock struct {
+	file *os.File
+	// unlock the mutex of the path.
+	unlock func()
+}
+
+// LockFile locks the path exclusively, waiting until other processes and goroutines release it.
+func LockFile(path string) (*Lock, error) {
+	return lockFile(path, true)
+}
+
+// RLockFile locks the path shared with other readers, waiting until a writer releases it.
+func RLockFile(path string) (*Lock, error) {
+	return lockFile(path, false)
+}
+
+func lockFile(path string, exclusive bool) (*Lock, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	value, _ := mutexes.LoadOrStore(abs, &sync.RWMutex{})
+	mu := value.(*sync.RWMutex)
+	l := &Lock{unlock: mu.Unlock}
+	if exclusive {
+		mu.Lock()
+	} else {
+		mu.RLock()
+		l.unlock = mu.RUnlock
+	}
+	if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
+		l.unlock()
+		return nil, err
+	}
+	if l.file, err = os.OpenFile(abs+".lock", os.O_CREATE|os.O_RDWR, 0644); err != nil {
+		l.unlock()
+		return nil, err
+	}
+	if err := lock(l.file, exclusive); err != nil {
+		l.file.Close()
+		l.unlock()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Unlock releases the lock, the lock file is kept for the next holder.
+func (l *Lock) Unlock() error {
+	defer l.unlock()
+	if err := unlock(l.file); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
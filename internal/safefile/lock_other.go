@@ -0,0 +1,19 @@
+//go:build !unix && !windows
+
+package safefile
+
+import "os"
+
+// lock does nothing where the system has no file locks, such as js/wasm or plan9:
+// the files are not shared with other processes there.
+func lock(*os.File, bool) error {
+	return nil
+}
+
+func unlock(*os.File) error {
+	return nil
+}
+
+func syncDir(string) error {
+	return nil
+}
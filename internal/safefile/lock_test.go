@@ -0,0 +1,49 @@
+package safefile
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "news", "bbc")
+	held, err := LockFile(path)
+	if err != nil {
+		t.Fatalf("LockFile() error = %v", err)
+	}
+
+	acquired := make(chan *Lock)
+	go func() {
+		lock, err := RLockFile(path)
+		if err != nil {
+			t.Errorf("RLockFile() error = %v", err)
+		}
+		acquired <- lock
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("RLockFile() acquired a lock held exclusively")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if err := held.Unlock(); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	var reader *Lock
+	select {
+	case reader = <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("RLockFile() not acquired after Unlock()")
+	}
+
+	// Readers share the lock.
+	other, err := RLockFile(path)
+	if err != nil {
+		t.Fatalf("RLockFile() error = %v", err)
+	}
+	for _, lock := range []*Lock{reader, other} {
+		if err := lock.Unlock(); err != nil {
+			t.Errorf("Unlock() error = %v", err)
+		}
+	}
+}
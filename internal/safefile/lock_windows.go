@@ -0,0 +1,29 @@
+//go:build windows
+
+package safefile
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockRange is the range of bytes locked, the whole lock file however long it gets.
+const lockRange = ^uint32(0)
+
+func lock(file *os.File, exclusive bool) error {
+	var flags uint32
+	if exclusive {
+		flags = windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	return windows.LockFileEx(windows.Handle(file.Fd()), flags, 0, lockRange, lockRange, &windows.Overlapped{})
+}
+
+func unlock(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, lockRange, lockRange, &windows.Overlapped{})
+}
+
+// syncDir does nothing, directories cannot be synced on Windows and renames are durable.
+func syncDir(string) error {
+	return nil
+}
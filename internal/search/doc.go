@@ -0,0 +1,3 @@
+// Package search provides an in-memory inverted index of news
+// with BM25 relevance scoring of keyword queries.
+package search
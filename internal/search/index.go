@@ -0,0 +1,184 @@
+package search
+
+import (
+	"math"
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/filters"
+	"news-aggregator/internal/language"
+	"strings"
+	"sync"
+)
+
+// BM25 parameters: k1 saturates the term frequency, b normalises it by the document length.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Index is an inverted index of the terms of the title and description of news.
+// Terms are stemmed in the language of the news, so the same word of different
+// languages are different terms. It is safe for concurrent use.
+type Index struct {
+	mu sync.RWMutex
+	// postings map a term to the frequency of the term in the documents containing it.
+	postings map[string]map[string]int
+	// documents map a document ID to the frequencies of its terms.
+	documents map[string]map[string]int
+	lengths   map[string]int
+	total     int
+	// languages map a language to the number of documents in it.
+	languages  map[string]int
+	languageOf map[string]string
+}
+
+// NewIndex creates an empty index.
+func NewIndex() *Index {
+	return &Index{
+		postings:   make(map[string]map[string]int),
+		documents:  make(map[string]map[string]int),
+		lengths:    make(map[string]int),
+		languages:  make(map[string]int),
+		languageOf: make(map[string]string),
+	}
+}
+
+// term of the stem in the language.
+func term(lang, stem string) string {
+	return lang + ":" + stem
+}
+
+// Add the news to the index as the document with the ID, replacing a previous one.
+// The language is detected from the text of news without one.
+func (x *Index) Add(id string, item entity.News) {
+	text := string(item.Title) + " " + string(item.Description)
+	lang := language.Normalize(item.Language)
+	if lang == "" {
+		lang = language.Detect(text)
+	}
+	if lang == "" {
+		// News of an unknown language are stemmed as English ones.
+		lang = language.English
+	}
+	stems := Terms(lang, text)
+	frequencies := make(map[string]int)
+	for _, stem := range stems {
+		frequencies[term(lang, stem)]++
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(id)
+	x.documents[id] = frequencies
+	x.lengths[id] = len(stems)
+	x.total += len(stems)
+	x.languageOf[id] = lang
+	x.languages[lang]++
+	for term, frequency := range frequencies {
+		if x.postings[term] == nil {
+			x.postings[term] = make(map[string]int)
+		}
+		x.postings[term][id] = frequency
+	}
+}
+
+// Remove the document from the index.
+func (x *Index) Remove(id string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(id)
+}
+
+func (x *Index) remove(id string) {
+	if _, ok := x.documents[id]; !ok {
+		return
+	}
+	lang := x.languageOf[id]
+	if x.languages[lang]--; x.languages[lang] == 0 {
+		delete(x.languages, lang)
+	}
+	delete(x.languageOf, id)
+	for term := range x.documents[id] {
+		delete(x.postings[term], id)
+		if len(x.postings[term]) == 0 {
+			delete(x.postings, term)
+		}
+	}
+	x.total -= x.lengths[id]
+	delete(x.documents, id)
+	delete(x.lengths, id)
+}
+
+// Len is the number of documents in the index.
+func (x *Index) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.documents)
+}
+
+// Candidates of the query, the IDs of the documents it can match.
+// Nil means the query can match documents without any of its terms.
+func (x *Index) Candidates(query *filters.Query) map[string]bool {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return query.Candidates(x.lookup)
+}
+
+// lookup the documents of any language containing all of the words, stop words left out.
+// It cannot tell when the words are stop words of one of the languages.
+func (x *Index) lookup(words []string) map[string]bool {
+	result := make(map[string]bool)
+	for lang := range x.languages {
+		stems := terms(lang, words)
+		if len(stems) == 0 {
+			return nil
+		}
+		var matching map[string]bool
+		for _, stem := range stems {
+			containing := make(map[string]bool)
+			for id := range x.postings[term(lang, stem)] {
+				if matching == nil || matching[id] {
+					containing[id] = true
+				}
+			}
+			matching = containing
+		}
+		for id := range matching {
+			result[id] = true
+		}
+	}
+	return result
+}
+
+// Scores of the documents by the BM25 relevance to the words the query searches for,
+// stemmed in the language of every document. Documents without any of the words are left out.
+func (x *Index) Scores(query *filters.Query) map[string]float64 {
+	words := language.Words(strings.Join(query.Phrases(), " "))
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	queryTerms := make(map[string]bool)
+	for lang := range x.languages {
+		for _, stem := range terms(lang, words) {
+			queryTerms[term(lang, stem)] = true
+		}
+	}
+	scores := make(map[string]float64)
+	if len(x.documents) == 0 {
+		return scores
+	}
+	n := float64(len(x.documents))
+	averageLength := float64(x.total) / n
+	for term := range queryTerms {
+		postings := x.postings[term]
+		idf := math.Log(1 + (n-float64(len(postings))+0.5)/(float64(len(postings))+0.5))
+		for id, frequency := range postings {
+			tf := float64(frequency)
+			norm := 1 - b
+			if averageLength > 0 {
+				norm += b * float64(x.lengths[id]) / averageLength
+			}
+			scores[id] += idf * tf * (k1 + 1) / (tf + k1*norm)
+		}
+	}
+	return scores
+}
@@ -0,0 +1,120 @@
+package search
+
+import (
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/filters"
+	"reflect"
+	"testing"
+)
+
+func testIndex() *Index {
+	index := NewIndex()
+	index.Add("1", entity.News{Title: "Fed cuts rates", Description: "The central bank lowered interest rates."})
+	index.Add("2", entity.News{Title: "Markets rally", Description: "Stocks rallied after the rate cut by the Fed, bonds fell and the dollar weakened."})
+	index.Add("3", entity.News{Title: "Weather today", Description: "Rain in the north."})
+	return index
+}
+
+func mustParseQuery(t *testing.T, text string) *filters.Query {
+	query, err := filters.ParseQuery(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return query
+}
+
+func TestIndex_Candidates(t *testing.T) {
+	index := testIndex()
+	tests := []struct {
+		query string
+		want  map[string]bool
+	}{
+		{"fed", map[string]bool{"1": true, "2": true}},
+		{"rate cut", map[string]bool{"1": true, "2": true}},
+		{"stocks OR rain", map[string]bool{"2": true, "3": true}},
+		{"crypto", map[string]bool{}},
+		{"-fed", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := index.Candidates(mustParseQuery(t, tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Candidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndex_Scores(t *testing.T) {
+	index := testIndex()
+
+	scores := index.Scores(mustParseQuery(t, "rates"))
+	if len(scores) != 2 {
+		t.Fatalf("Scores() = %v, want scores of documents 1 and 2", scores)
+	}
+	// Document 1 mentions rates twice in fewer words.
+	if scores["1"] <= scores["2"] {
+		t.Errorf("Scores() = %v, want document 1 ahead of document 2", scores)
+	}
+
+	// A rarer term weighs more than a common one.
+	scores = index.Scores(mustParseQuery(t, "fed OR rain"))
+	if scores["3"] <= scores["2"] {
+		t.Errorf("Scores() = %v, want document 3 ahead of document 2", scores)
+	}
+
+	// Negated words are not scored.
+	if scores := index.Scores(mustParseQuery(t, "-fed")); len(scores) != 0 {
+		t.Errorf("Scores() = %v, want no scores", scores)
+	}
+}
+
+func TestIndex_AddRemove(t *testing.T) {
+	index := testIndex()
+	if index.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", index.Len())
+	}
+
+	index.Add("3", entity.News{Title: "Fed holds rates"})
+	want := map[string]bool{"1": true, "2": true, "3": true}
+	if got := index.Candidates(mustParseQuery(t, "fed")); !reflect.DeepEqual(got, want) {
+		t.Errorf("Candidates() after replacing = %v, want %v", got, want)
+	}
+	if got := index.Candidates(mustParseQuery(t, "rain")); len(got) != 0 {
+		t.Errorf("Candidates() of replaced words = %v, want none", got)
+	}
+
+	index.Remove("1")
+	index.Remove("2")
+	index.Remove("3")
+	if index.Len() != 0 || len(index.postings) != 0 || index.total != 0 {
+		t.Errorf("index not empty after removing all documents: %d documents, %d terms", index.Len(), len(index.postings))
+	}
+}
+
+func TestIndex_Languages(t *testing.T) {
+	index := NewIndex()
+	index.Add("de", entity.News{Title: "Die Regierungen der Länder", Language: "de"})
+	index.Add("uk", entity.News{Title: "Уряд України ухвалив бюджет"})
+	index.Add("en", entity.News{Title: "Governments of the states"})
+
+	tests := []struct {
+		query string
+		want  map[string]bool
+	}{
+		{"Regierung", map[string]bool{"de": true}},
+		{"Україна", map[string]bool{"uk": true}},
+		{"government", map[string]bool{"en": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query := mustParseQuery(t, tt.query)
+			if got := index.Candidates(query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Candidates() = %v, want %v", got, tt.want)
+			}
+			if scores := index.Scores(query); len(scores) != 1 {
+				t.Errorf("Scores() = %v, want a score of one document", scores)
+			}
+		})
+	}
+}
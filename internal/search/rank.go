@@ -0,0 +1,37 @@
+package search
+
+import (
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/filters"
+	"sort"
+	"strconv"
+)
+
+// Rank the news by their BM25 relevance to the query, the most relevant first
+// or, reversed, the least relevant first. News equally relevant keep their order.
+func Rank(news []entity.News, query *filters.Query, reversed bool) []entity.News {
+	index := NewIndex()
+	for i, item := range news {
+		index.Add(strconv.Itoa(i), item)
+	}
+	scores := index.Scores(query)
+	ranked := make([]float64, len(news))
+	for i := range news {
+		ranked[i] = scores[strconv.Itoa(i)]
+	}
+	order := make([]int, len(news))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if reversed {
+			return ranked[order[i]] < ranked[order[j]]
+		}
+		return ranked[order[i]] > ranked[order[j]]
+	})
+	result := make([]entity.News, len(news))
+	for i, position := range order {
+		result[i] = news[position]
+	}
+	return result
+}
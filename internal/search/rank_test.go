@@ -0,0 +1,31 @@
+package search
+
+import (
+	"news-aggregator/internal/entity"
+	"reflect"
+	"testing"
+)
+
+func TestRank(t *testing.T) {
+	news := []entity.News{
+		{Title: "Weather today", Link: "1"},
+		{Title: "Markets rally after the rate cut", Link: "2"},
+		{Title: "Rate cut", Description: "Rates are cut.", Link: "3"},
+		{Title: "Sports", Link: "4"},
+	}
+	query := mustParseQuery(t, "rate OR cut")
+
+	links := func(news []entity.News) []entity.Link {
+		var result []entity.Link
+		for _, item := range news {
+			result = append(result, item.Link)
+		}
+		return result
+	}
+	if got, want := links(Rank(news, query, false)), []entity.Link{"3", "2", "1", "4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Rank() = %v, want %v", got, want)
+	}
+	if got, want := links(Rank(news, query, true)), []entity.Link{"1", "4", "2", "3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Rank() reversed = %v, want %v", got, want)
+	}
+}
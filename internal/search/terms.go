@@ -0,0 +1,19 @@
+package search
+
+import "news-aggregator/internal/language"
+
+// Terms of the text in the language: its lower-case words stemmed with the stemmer
+// of the language, stop words left out.
+func Terms(lang, text string) []string {
+	return terms(lang, language.Words(text))
+}
+
+func terms(lang string, words []string) []string {
+	var result []string
+	for _, word := range words {
+		if !language.IsStopWord(lang, word) {
+			result = append(result, language.Stem(lang, word))
+		}
+	}
+	return result
+}
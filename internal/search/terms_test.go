@@ -0,0 +1,31 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		text     string
+		want     []string
+	}{
+		{"Stems words", "en", "Rates rallied", []string{"rate", "ralli"}},
+		{"Drops stop words", "en", "The Fed and the markets", []string{"fed", "market"}},
+		{"Ignores punctuation", "en", "Rates, bonds (again)!", []string{"rate", "bond"}},
+		{"Only stop words", "en", "to be or not to be", nil},
+		{"Unknown language", "", "The markets", []string{"market"}},
+		{"German", "de", "Die Märkte und die Regierungen", []string{"markt", "regier"}},
+		{"Ukrainian", "uk", "Україна та війна", []string{"україн", "війн"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Terms(tt.language, tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Terms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
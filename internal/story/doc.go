@@ -0,0 +1,4 @@
+// Package story groups near-duplicate news, such as the same wire story published
+// by several sources, into stories. News are compared by the MinHash signatures
+// of the shingles of their normalised title and description.
+package story
@@ -0,0 +1,113 @@
+package story
+
+import (
+	"hash/fnv"
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/language"
+	"strings"
+)
+
+const (
+	// signatureSize is the number of hash functions of a MinHash signature.
+	signatureSize = 64
+	// bands of the signature hashed to find candidate pairs, of signatureSize/bands rows each.
+	bands = 16
+	rows  = signatureSize / bands
+	// shingleSize is the number of consecutive words of a shingle.
+	shingleSize = 2
+)
+
+// signature is the MinHash signature of the shingles of a news.
+type signature [signatureSize]uint64
+
+// seeds of the hash functions of the signature.
+var seeds = func() [signatureSize]uint64 {
+	var result [signatureSize]uint64
+	state := uint64(0x9e3779b97f4a7c15)
+	for i := range result {
+		state = mix(state + uint64(i))
+		result[i] = state
+	}
+	return result
+}()
+
+// mix the bits of x, the finalizer of SplitMix64.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// shingles of the normalised title and description: runs of consecutive stemmed words
+// without stop words, the words themselves for texts shorter than a shingle.
+func shingles(item entity.News) []string {
+	lang := item.Language
+	if lang == "" {
+		lang = language.Detect(string(item.Title) + " " + string(item.Description))
+	}
+	var words []string
+	for _, text := range []string{string(item.Title), string(item.Description)} {
+		for _, word := range language.Words(text) {
+			if !language.IsStopWord(lang, word) {
+				words = append(words, language.Stem(lang, word))
+			}
+		}
+	}
+	if len(words) < shingleSize {
+		return words
+	}
+	result := make([]string, 0, len(words)-shingleSize+1)
+	for i := 0; i+shingleSize <= len(words); i++ {
+		result = append(result, strings.Join(words[i:i+shingleSize], " "))
+	}
+	return result
+}
+
+// newSignature of the shingles, nil without shingles.
+func newSignature(shingles []string) *signature {
+	if len(shingles) == 0 {
+		return nil
+	}
+	var s signature
+	for i := range s {
+		s[i] = ^uint64(0)
+	}
+	for _, shingle := range shingles {
+		h := fnv.New64a()
+		h.Write([]byte(shingle))
+		value := h.Sum64()
+		for i, seed := range seeds {
+			if v := mix(value ^ seed); v < s[i] {
+				s[i] = v
+			}
+		}
+	}
+	return &s
+}
+
+// similarity estimates the Jaccard similarity of the shingles of two signatures.
+func (s *signature) similarity(other *signature) float64 {
+	equal := 0
+	for i := range s {
+		if s[i] == other[i] {
+			equal++
+		}
+	}
+	return float64(equal) / signatureSize
+}
+
+// band key of the rows of the band of the signature.
+func (s *signature) band(band int) uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	for _, value := range s[band*rows : (band+1)*rows] {
+		for i := range buf {
+			buf[i] = byte(value >> (8 * i))
+		}
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,114 @@
+package story
+
+import (
+	"news-aggregator/internal/entity"
+	"time"
+)
+
+// defaultThreshold of the estimated similarity from which news are the same story.
+const defaultThreshold = 0.5
+
+// Story is a group of near-duplicate news. Its title and description are those
+// of its first news, the date is the earliest one of its news.
+type Story struct {
+	Title       entity.Title       `json:"title"`
+	Description entity.Description `json:"description"`
+	Date        time.Time          `json:"date"`
+	// Sources publishing the story, in the order of its news.
+	Sources []string      `json:"sources"`
+	News    []entity.News `json:"news"`
+}
+
+// Options of grouping news into stories.
+type Options struct {
+	// Threshold of the similarity of two news, from 0 to 1, from which they are the same story.
+	// Zero uses the default of 0.5.
+	Threshold float64
+}
+
+// Group the news into stories. Stories keep the order of their first news and
+// the news of a story keep their order, so grouping sorted news keeps them sorted.
+// News without words are stories of their own.
+func (o Options) Group(news []entity.News) []Story {
+	threshold := o.Threshold
+	if threshold <= 0 {
+		threshold = defaultThreshold
+	}
+	signatures := make([]*signature, len(news))
+	for i, item := range news {
+		signatures[i] = newSignature(shingles(item))
+	}
+
+	groups := newUnionFind(len(news))
+	buckets := make(map[[2]uint64][]int)
+	for i, s := range signatures {
+		if s == nil {
+			continue
+		}
+		for band := 0; band < bands; band++ {
+			key := [2]uint64{uint64(band), s.band(band)}
+			for _, j := range buckets[key] {
+				if groups.find(i) != groups.find(j) && s.similarity(signatures[j]) >= threshold {
+					groups.union(i, j)
+				}
+			}
+			buckets[key] = append(buckets[key], i)
+		}
+	}
+
+	var stories []Story
+	index := make(map[int]int)
+	for i, item := range news {
+		root := groups.find(i)
+		position, ok := index[root]
+		if !ok {
+			position = len(stories)
+			index[root] = position
+			stories = append(stories, Story{Title: item.Title, Description: item.Description, Date: item.Date})
+		}
+		stories[position].add(item)
+	}
+	return stories
+}
+
+// add the news to the story.
+func (s *Story) add(item entity.News) {
+	s.News = append(s.News, item)
+	if item.Date.Before(s.Date) {
+		s.Date = item.Date
+	}
+	for _, source := range s.Sources {
+		if source == item.Source {
+			return
+		}
+	}
+	s.Sources = append(s.Sources, item.Source)
+}
+
+// unionFind of disjoint sets of news indexes.
+type unionFind []int
+
+func newUnionFind(n int) unionFind {
+	parent := make(unionFind, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	return parent
+}
+
+func (u unionFind) find(i int) int {
+	for u[i] != i {
+		u[i] = u[u[i]]
+		i = u[i]
+	}
+	return i
+}
+
+// union the sets of i and j, the smaller index becomes the root.
+func (u unionFind) union(i, j int) {
+	a, b := u.find(i), u.find(j)
+	if a > b {
+		a, b = b, a
+	}
+	u[b] = a
+}
@@ -0,0 +1,76 @@
+package story
+
+import (
+	"news-aggregator/internal/entity"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOptions_Group(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+	news := []entity.News{
+		{Title: "Central bank raises interest rates to fight inflation", Description: "The central bank raised its key interest rate by half a point on Tuesday.", Link: "a1", Source: "A", Date: day(3)},
+		{Title: "Local team wins the championship final", Description: "The home side won the final after extra time.", Link: "b1", Source: "B", Date: day(3)},
+		{Title: "Central bank raises interest rates to fight inflation", Description: "The central bank raised its key interest rate by half a point on Tuesday, its third rise.", Link: "b2", Source: "B", Date: day(2)},
+		{Title: "", Link: "c1", Source: "C", Date: day(1)},
+		{Title: "Central Bank raises interest rates to fight inflation!", Description: "The central bank raised its key interest rate by half a point on Tuesday.", Link: "c2", Source: "C", Date: day(4)},
+	}
+
+	stories := Options{}.Group(news)
+
+	var got [][]entity.Link
+	for _, s := range stories {
+		var links []entity.Link
+		for _, item := range s.News {
+			links = append(links, item.Link)
+		}
+		got = append(got, links)
+	}
+	want := [][]entity.Link{{"a1", "b2", "c2"}, {"b1"}, {"c1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Group() = %v, want %v", got, want)
+	}
+	first := stories[0]
+	if first.Title != news[0].Title || !first.Date.Equal(day(2)) {
+		t.Errorf("Group() story = %q of %v, want %q of %v", first.Title, first.Date, news[0].Title, day(2))
+	}
+	if want := []string{"A", "B", "C"}; !reflect.DeepEqual(first.Sources, want) {
+		t.Errorf("Group() sources = %v, want %v", first.Sources, want)
+	}
+}
+
+func TestOptions_Group_Threshold(t *testing.T) {
+	news := []entity.News{
+		{Title: "Storm floods the coast and closes the harbour", Link: "1"},
+		{Title: "Storm floods the coast and closes the airport", Link: "2"},
+	}
+	tests := []struct {
+		name      string
+		threshold float64
+		want      int
+	}{
+		{name: "Default", want: 1},
+		{name: "Strict", threshold: 1, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(Options{Threshold: tt.threshold}.Group(news)); got != tt.want {
+				t.Errorf("Group() = %d stories, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignature_similarity(t *testing.T) {
+	a := newSignature([]string{"a b", "b c", "c d", "d e"})
+	if got := a.similarity(newSignature([]string{"a b", "b c", "c d", "d e"})); got != 1 {
+		t.Errorf("similarity() of equal shingles = %v, want 1", got)
+	}
+	if got := a.similarity(newSignature([]string{"v w", "w x", "x y", "y z"})); got > 0.2 {
+		t.Errorf("similarity() of distinct shingles = %v, want about 0", got)
+	}
+	if newSignature(nil) != nil {
+		t.Errorf("newSignature() without shingles is not nil")
+	}
+}
@@ -1,12 +1,12 @@
 package template
 
 import (
-	"fmt"
 	"github.com/wk8/go-ordered-map"
 	"log"
 	"news-aggregator/internal/entity"
+	"news-aggregator/internal/filters"
 	"news-aggregator/internal/sort"
-	"regexp"
+	"news-aggregator/internal/story"
 	"strings"
 	"text/template"
 )
@@ -16,11 +16,15 @@ type Data struct {
 	Header  Header
 	News    []entity.News
 	Grouped []*groupedNews
+	// Stories of near-duplicate news, prepared when grouping by story.
+	Stories []story.Story
 }
 type Header struct {
 	Sources     string
 	Filters     string
 	SortOptions sort.Options
+	// Group is "story" to print the news grouped into stories.
+	Group string
 }
 
 type groupedNews struct {
@@ -32,25 +36,7 @@ const pathToTemplate = "internal/template/news.tmpl"
 
 // Create generates a template for rendering news.
 func (t Data) Create(keywords string) (*template.Template, error) {
-	funcMap := template.FuncMap{
-		"highlight": func(text string) string {
-			if len(keywords) == 0 {
-				return text
-			}
-			for _, keyword := range strings.Split(keywords, ",") {
-				re := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(keyword))
-				text = re.ReplaceAllStringFunc(text, func(matched string) string {
-					return "~~" + matched + "~~"
-				})
-			}
-			return text
-		},
-		"toString": func(v interface{}) string {
-			return fmt.Sprintf("%v", v)
-		},
-	}
-
-	tmpl, err := template.New("news").Funcs(funcMap).ParseFiles(pathToTemplate)
+	tmpl, err := template.New("news").Funcs(funcs(keywords, textMark)).ParseFiles(pathToTemplate)
 	if err != nil {
 		log.Fatal(err)
 		return nil, err
@@ -58,6 +44,15 @@ func (t Data) Create(keywords string) (*template.Template, error) {
 	return tmpl, err
 }
 
+// highlights of the keyword query, the comma-separated keywords when it is not valid.
+func highlights(keywords string) []string {
+	query, err := filters.ParseQuery(keywords)
+	if err != nil {
+		return strings.Split(keywords, ",")
+	}
+	return query.Phrases()
+}
+
 // Prepare the template data for rendering.
 func (t Data) Prepare() Data {
 	groupedMap := group(t.News)
@@ -68,6 +63,9 @@ func (t Data) Prepare() Data {
 		groupedList = append(groupedList, &groupedNews{Source: source, NewsList: newsList})
 	}
 	t.Grouped = groupedList
+	if t.Header.Group == "story" {
+		t.Stories = story.Options{}.Group(t.News)
+	}
 	return t
 }
 
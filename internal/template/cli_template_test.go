@@ -1,8 +1,10 @@
 package template_test
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/wk8/go-ordered-map"
@@ -97,6 +99,31 @@ func TestPrepare(t *testing.T) {
 	}
 }
 
+func TestPrepareStories(t *testing.T) {
+	restoreWD := setWorkingDirectory(t)
+	defer restoreWD()
+	news := append([]entity.News{
+		{Title: "President travels", Description: "The president is traveling", Source: "NBC", Link: "nbc"},
+	}, testNews...)
+	data := template.Data{News: news, Header: template.Header{Group: "story"}}
+
+	preparedData := data.Prepare()
+	if len(preparedData.Stories) != 3 {
+		t.Fatalf("expected 3 stories, got %d", len(preparedData.Stories))
+	}
+	tmpl, err := preparedData.Create("")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&out, "news", preparedData); err != nil {
+		t.Fatalf("ExecuteTemplate() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Story: President travels (2 news from 2 sources)") {
+		t.Errorf("expected the story of two sources in the output, got:\n%s", out.String())
+	}
+}
+
 // Mock function for grouping news items by their source.
 func group(news []entity.News) *orderedmap.OrderedMap {
 	grouped := orderedmap.New()
@@ -1,4 +1,5 @@
 // Package template provides API for working with templates.
 // It is necessary to create structured output of information about news
-// received by the user after his request.
+// received by the user after his request, as text, JSON, NDJSON, CSV, Markdown,
+// an HTML report or with a template of the user.
 package template
@@ -0,0 +1,257 @@
+package template
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"log"
+	"news-aggregator/internal/entity"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Format of the rendered news.
+type Format string
+
+const (
+	// Text renders news.tmpl, highlighting the keywords with ~~.
+	Text Format = "text"
+	// JSON renders an array of the news, or of the stories when grouping by story.
+	JSON Format = "json"
+	// NDJSON renders a JSON object of a news or story per line.
+	NDJSON Format = "ndjson"
+	// CSV renders a row per news with a header, the first column is the story when grouping by story.
+	CSV Format = "csv"
+	// Markdown renders news.md.tmpl, highlighting the keywords in bold.
+	Markdown Format = "markdown"
+	// HTML renders news.html.tmpl, a standalone report highlighting the keywords with <mark>.
+	HTML Format = "html"
+)
+
+// Formats of the news.
+var Formats = []Format{Text, JSON, NDJSON, CSV, Markdown, HTML}
+
+const (
+	pathToMarkdownTemplate = "internal/template/news.md.tmpl"
+	pathToHTMLTemplate     = "internal/template/news.html.tmpl"
+)
+
+// csvHeader of the news rendered as CSV.
+var csvHeader = []string{"source", "title", "description", "link", "date", "language", "authors", "categories"}
+
+// ParseFormat of the value, case-insensitively. An empty value is Text.
+func ParseFormat(value string) (Format, error) {
+	if value == "" {
+		return Text, nil
+	}
+	for _, format := range Formats {
+		if strings.EqualFold(value, string(format)) {
+			return format, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, format := range Formats {
+		names[i] = string(format)
+	}
+	return "", fmt.Errorf("invalid output %q, expected one of %s", value, strings.Join(names, ", "))
+}
+
+// Output of the rendered news, a format or a template file of the user.
+type Output struct {
+	Format Format
+	// TemplateFile is a Go template rendered instead of the format when given. It is executed
+	// with the prepared Data and the functions of news.tmpl, highlight and toString.
+	// The template named "news" is executed when the file defines one, otherwise the file itself.
+	TemplateFile string
+}
+
+// Render the prepared news to the writer, keywords are highlighted in the text formats.
+func (t Data) Render(w io.Writer, keywords string, output Output) error {
+	data := t.Prepare()
+	if output.TemplateFile != "" {
+		return data.renderFile(w, keywords, output.TemplateFile)
+	}
+	switch output.Format {
+	case Text, "":
+		tmpl, err := data.Create(keywords)
+		if err != nil {
+			return err
+		}
+		return tmpl.ExecuteTemplate(w, "news", data)
+	case Markdown:
+		tmpl, err := template.New("news").Funcs(funcs(keywords, markdownMark)).ParseFiles(pathToMarkdownTemplate)
+		if err != nil {
+			log.Printf("Error parsing template %s: %v", pathToMarkdownTemplate, err)
+			return err
+		}
+		return tmpl.ExecuteTemplate(w, "news", data)
+	case HTML:
+		tmpl, err := htmltemplate.New("news").Funcs(htmlFuncs(keywords)).ParseFiles(pathToHTMLTemplate)
+		if err != nil {
+			log.Printf("Error parsing template %s: %v", pathToHTMLTemplate, err)
+			return err
+		}
+		return tmpl.ExecuteTemplate(w, "news", data)
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data.items())
+	case NDJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		for _, item := range data.items() {
+			if err := encoder.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case CSV:
+		return data.writeCSV(w)
+	default:
+		return fmt.Errorf("invalid output %q", output.Format)
+	}
+}
+
+// renderFile of the user with the data.
+func (t Data) renderFile(w io.Writer, keywords, path string) error {
+	tmpl, err := template.New(filepath.Base(path)).Funcs(funcs(keywords, textMark)).ParseFiles(path)
+	if err != nil {
+		log.Printf("Error parsing template %s: %v", path, err)
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	if news := tmpl.Lookup("news"); news != nil {
+		tmpl = news
+	}
+	if err := tmpl.Execute(w, t); err != nil {
+		log.Printf("Error executing template %s: %v", path, err)
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
+
+// items of the data, the stories when grouping by story, otherwise the news.
+func (t Data) items() []any {
+	items := make([]any, 0, len(t.News))
+	if t.Header.Group == "story" {
+		for _, s := range t.Stories {
+			items = append(items, s)
+		}
+		return items
+	}
+	for _, news := range t.News {
+		items = append(items, news)
+	}
+	return items
+}
+
+// writeCSV of the news of the data.
+func (t Data) writeCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	stories := t.Header.Group == "story"
+	header := csvHeader
+	if stories {
+		header = append([]string{"story"}, header...)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	write := func(story string, news entity.News) error {
+		record := []string{
+			news.Source,
+			news.Title.String(),
+			news.Description.String(),
+			string(news.Link),
+			news.Date.Format(time.RFC3339),
+			news.Language,
+			strings.Join(news.Authors, "; "),
+			strings.Join(news.Categories, "; "),
+		}
+		if stories {
+			record = append([]string{story}, record...)
+		}
+		return writer.Write(record)
+	}
+	if stories {
+		for _, s := range t.Stories {
+			for _, news := range s.News {
+				if err := write(s.Title.String(), news); err != nil {
+					return err
+				}
+			}
+		}
+	} else {
+		for _, news := range t.News {
+			if err := write("", news); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// funcs of the templates, highlight marks the keywords in the text with mark.
+func funcs(keywords string, mark func(string) string) template.FuncMap {
+	return template.FuncMap{
+		"highlight": func(text string) string {
+			return highlight(text, keywords, mark, nil)
+		},
+		"toString": toString,
+	}
+}
+
+// htmlFuncs of the HTML template, highlight escapes the text around the marked keywords.
+func htmlFuncs(keywords string) htmltemplate.FuncMap {
+	return htmltemplate.FuncMap{
+		"highlight": func(text string) htmltemplate.HTML {
+			return htmltemplate.HTML(highlight(text, keywords, htmlMark, htmltemplate.HTMLEscapeString))
+		},
+		"toString": toString,
+	}
+}
+
+func toString(v interface{}) string {
+	return fmt.Sprintf("%v", v)
+}
+
+func textMark(matched string) string     { return "~~" + matched + "~~" }
+func markdownMark(matched string) string { return "**" + matched + "**" }
+func htmlMark(matched string) string     { return "<mark>" + matched + "</mark>" }
+
+// highlight the phrases of the keyword query in the text with mark, case-insensitively.
+// The longest phrase matching at a position is marked. The text around the phrases
+// and the phrases themselves are escaped with escape when given.
+func highlight(text, keywords string, mark func(string) string, escape func(string) string) string {
+	if escape == nil {
+		escape = func(s string) string { return s }
+	}
+	var phrases []string
+	if len(keywords) != 0 {
+		for _, phrase := range highlights(keywords) {
+			if phrase != "" {
+				phrases = append(phrases, regexp.QuoteMeta(phrase))
+			}
+		}
+	}
+	if len(phrases) == 0 {
+		return escape(text)
+	}
+	sort.SliceStable(phrases, func(i, j int) bool { return len(phrases[i]) > len(phrases[j]) })
+	re := regexp.MustCompile(`(?i)` + strings.Join(phrases, "|"))
+	var highlighted strings.Builder
+	last := 0
+	for _, match := range re.FindAllStringIndex(text, -1) {
+		highlighted.WriteString(escape(text[last:match[0]]))
+		highlighted.WriteString(mark(escape(text[match[0]:match[1]])))
+		last = match[1]
+	}
+	highlighted.WriteString(escape(text[last:]))
+	return highlighted.String()
+}
@@ -0,0 +1,121 @@
+package template_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/sort"
+	"news-aggregator/internal/template"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    template.Format
+		wantErr bool
+	}{
+		{value: "", want: template.Text},
+		{value: "json", want: template.JSON},
+		{value: "NDJSON", want: template.NDJSON},
+		{value: "Markdown", want: template.Markdown},
+		{value: "xml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := template.ParseFormat(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	restoreWD := setWorkingDirectory(t)
+	defer restoreWD()
+	date := time.Date(2024, 5, 18, 10, 0, 0, 0, time.UTC)
+	news := []entity.News{
+		{Title: "President speaks", Description: "<b>The president</b> & the press", Link: "https://bbc.com/1", Source: "BBC", Date: date, Authors: []string{"Jane Doe", "John Doe"}},
+		{Title: "New law signed", Description: "The president signed a new law", Link: "https://nbc.com/1", Source: "NBC", Date: date},
+	}
+	tests := []struct {
+		name   string
+		format template.Format
+		group  string
+		want   []string
+	}{
+		{name: "text", format: template.Text, want: []string{"Title: ~~President~~ speaks", "Source: NBC (1 items)"}},
+		{name: "json", format: template.JSON, want: []string{"[\n  {\n", `"Description": "<b>The president</b> & the press"`}},
+		{name: "ndjson", format: template.NDJSON, want: []string{`{"Title":"President speaks",`, "\n{\"Title\":\"New law signed\","}},
+		{name: "csv", format: template.CSV, want: []string{
+			"source,title,description,link,date,language,authors,categories\n",
+			"BBC,President speaks,<b>The president</b> & the press,https://bbc.com/1,2024-05-18T10:00:00Z,,Jane Doe; John Doe,\n",
+		}},
+		{name: "csv stories", format: template.CSV, group: "story", want: []string{"story,source,title,", "\nNew law signed,NBC,New law signed,"}},
+		{name: "markdown", format: template.Markdown, want: []string{"## BBC (1 items)", "### [**President** speaks](https://bbc.com/1)"}},
+		{name: "html", format: template.HTML, want: []string{
+			"<!DOCTYPE html>",
+			`<a href="https://bbc.com/1"><mark>President</mark> speaks</a>`,
+			"&lt;b&gt;The <mark>president</mark>&lt;/b&gt; &amp; the press",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := template.Data{
+				News:   news,
+				Header: template.Header{Sources: "bbc,nbc", SortOptions: sort.Options{Criterion: "source", Order: "ASC"}, Group: tt.group},
+			}
+			var out bytes.Buffer
+			if err := data.Render(&out, "president", template.Output{Format: tt.format}); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("expected %q in the output, got:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRender_TemplateFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{name: "file", template: `{{range .News}}{{.Source}}: {{highlight (toString .Title)}};{{end}}`, want: "BBC: ~~President~~ speaks;NBC: New law signed;"},
+		{name: "news template", template: `{{define "news"}}{{len .Grouped}} sources{{end}}ignored`, want: "2 sources"},
+		{name: "invalid", template: `{{range .News}`, wantErr: true},
+		{name: "unknown field", template: `{{.Unknown}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".tmpl")
+			if err := os.WriteFile(path, []byte(tt.template), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			data := template.Data{News: testNews[:2]}
+			var out bytes.Buffer
+
+			err := data.Render(&out, "president", template.Output{Format: template.JSON, TemplateFile: path})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && out.String() != tt.want {
+				t.Errorf("Render() = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
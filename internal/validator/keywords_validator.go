@@ -0,0 +1,19 @@
+package validator
+
+import "news-aggregator/internal/filters"
+
+// keywordsValidator checks the keyword query.
+type keywordsValidator struct {
+	baseValidator
+	keywords string
+}
+
+// Validate checks that the keywords are a valid query of the keyword query language.
+func (k keywordsValidator) Validate() error {
+	if k.keywords != "" {
+		if _, err := filters.ParseQuery(k.keywords); err != nil {
+			return err
+		}
+	}
+	return k.baseValidator.Validate()
+}
@@ -0,0 +1,36 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestKeywordsValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		keywords string
+		expected error
+	}{
+		{"Empty keywords", "", nil},
+		{"Comma separated keywords", "Ukraine,China", nil},
+		{"Query", `title:"interest rates" AND (fed OR ecb) -crypto`, nil},
+		{"Unterminated phrase", `"interest rates`, errors.New("invalid keywords: unterminated phrase")},
+		{"Missing parenthesis", "(fed OR ecb", errors.New("invalid keywords: missing )")},
+		{"Dangling operator", "fed OR", errors.New("invalid keywords: unexpected end of the query")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := keywordsValidator{
+				keywords: tt.keywords,
+			}
+			result := validator.Validate()
+			if tt.expected == nil && result != nil {
+				t.Errorf("Expected no error, but got %v", result)
+			}
+			if tt.expected != nil && (result == nil || result.Error() != tt.expected.Error()) {
+				t.Errorf("Expected %v, but got %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -12,13 +12,14 @@ type sortOptionsValidator struct {
 }
 
 // Validate checks if the provided sorting criterion and order are valid.
-// If a criterion is specified, it must be either "date" or "source".
+// If a criterion is specified, it must be "date", "source" or "relevance".
 // If an order is specified, it must be either "asc" or "desc".
 func (v sortOptionsValidator) Validate() error {
 	if v.criterion != "" {
-		if !strings.EqualFold(v.criterion, "date") && !strings.EqualFold(v.criterion, "source") {
-			//log.Println("Invalid sort criterion. Please use `date` or `source`")
-			return errors.New("invalid sort criterion. Please use `date` or `source`")
+		if !strings.EqualFold(v.criterion, "date") && !strings.EqualFold(v.criterion, "source") &&
+			!strings.EqualFold(v.criterion, "relevance") {
+			//log.Println("Invalid sort criterion. Please use `date`, `source` or `relevance`")
+			return errors.New("invalid sort criterion. Please use `date`, `source` or `relevance`")
 		}
 	}
 	if v.order != "" {
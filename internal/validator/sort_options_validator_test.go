@@ -32,13 +32,21 @@ func Test_sortOptionsValidator_Validate(t *testing.T) {
 			},
 			want: nil,
 		},
+		{
+			name: "Valid sort options - relevance",
+			fields: fields{
+				criterion: "Relevance",
+				order:     "",
+			},
+			want: nil,
+		},
 		{
 			name: "Invalid sort criterion",
 			fields: fields{
 				criterion: "invalid",
 				order:     "asc",
 			},
-			want: errors.New("invalid sort criterion. Please use `date` or `source`"),
+			want: errors.New("invalid sort criterion. Please use `date`, `source` or `relevance`"),
 		},
 		{
 			name: "Invalid sort order",
@@ -62,7 +70,7 @@ func Test_sortOptionsValidator_Validate(t *testing.T) {
 				criterion: "invalid",
 				order:     "invalid",
 			},
-			want: errors.New("invalid sort criterion. Please use `date` or `source`"),
+			want: errors.New("invalid sort criterion. Please use `date`, `source` or `relevance`"),
 		},
 	}
 	for _, tt := range tests {
@@ -6,6 +6,7 @@ import "news-aggregator/internal/sort"
 type validator struct {
 	Sources          string
 	AvailableSources []string
+	Keywords         string
 	DateStart        string
 	DateEnd          string
 	Criterion        string
@@ -16,6 +17,7 @@ type validator struct {
 type Config struct {
 	Sources          string
 	AvailableSources []string
+	Keywords         string
 	DateStart        string
 	DateEnd          string
 	SortOptions      sort.Options
@@ -26,6 +28,7 @@ func NewValidator(config Config) ValidatingComponent {
 	return &validator{
 		Sources:          config.Sources,
 		AvailableSources: config.AvailableSources,
+		Keywords:         config.Keywords,
 		DateStart:        config.DateStart,
 		DateEnd:          config.DateEnd,
 		Criterion:        config.SortOptions.Criterion,
@@ -41,11 +44,13 @@ type ValidatingComponent interface {
 // Validate all implementations with the chain of responsibility pattern.
 func (v validator) Validate() error {
 	sourceValidator := &sourceValidator{sources: v.Sources, availableSources: v.AvailableSources}
+	keywordsValidator := &keywordsValidator{keywords: v.Keywords}
 	dateStartValidator := &dateStartValidator{dateStart: v.DateStart}
 	dateEndValidator := &dateEndValidator{dateEnd: v.DateEnd}
 	sortOptionsValidator := &sortOptionsValidator{criterion: v.Criterion, order: v.Order}
 
-	sourceValidator.SetNext(dateStartValidator)
+	sourceValidator.SetNext(keywordsValidator)
+	keywordsValidator.SetNext(dateStartValidator)
 	dateStartValidator.SetNext(dateEndValidator)
 	dateEndValidator.SetNext(sortOptionsValidator)
 
@@ -3,7 +3,7 @@ package main
 import (
 	"crypto/tls"
 	"flag"
-	"net/http"
+	clientv1 "news-aggregator/client/v1"
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"time"
@@ -45,6 +45,7 @@ func main() {
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	serviceUrl := flag.String("service-url", "https://news-aggregator-service.news-aggregator.svc.cluster.local:443/", "The URL of the news aggregator service that the controller will interact with.")
+	serviceCACert := flag.String("service-ca-cert", "", "The PEM file of the CA certificate of the news aggregator service. Its certificate is not verified when it is not given.")
 	feedFinalizer := flag.String("feed-finalizer", "feeds.finalizers.teamdev.com", "The finalizer name used to ensure that Feed resources are properly cleaned up before they are deleted.")
 	configMapName := flag.String("config-map-name", "feed-group-source", "The name of the ConfigMap to use for feed groups")
 	hotNewsFinalizer := flag.String("news-finalizer", "news.finalizers.teamdev.com", "The finalizer name used to ensure that HotNews resources are properly cleaned up before they are deleted.")
@@ -94,18 +95,24 @@ func main() {
 		os.Exit(1)
 	}
 
-	httpClient := &http.Client{
-		Timeout: 10 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+	if *serviceCACert == "" {
+		setupLog.Info("no CA certificate of the news aggregator service given, its certificate is not verified")
+	}
+	aggregator, err := clientv1.New(clientv1.Config{
+		ServerURL:          *serviceUrl,
+		CACertFile:         *serviceCACert,
+		InsecureSkipVerify: *serviceCACert == "",
+		Timeout:            10 * time.Second,
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to create the news aggregator client")
+		os.Exit(1)
 	}
 
 	if err = (&controller.FeedReconciler{
 		Client:        mgr.GetClient(),
 		Scheme:        mgr.GetScheme(),
-		HttpClient:    httpClient,
-		ServiceURL:    *serviceUrl + "sources",
+		Aggregator:    aggregator,
 		FeedFinalizer: *feedFinalizer,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Feed")
@@ -114,8 +121,7 @@ func main() {
 	if err = (&controller.HotNewsReconciler{
 		Client:     mgr.GetClient(),
 		Scheme:     mgr.GetScheme(),
-		HttpClient: httpClient,
-		ServiceURL: *serviceUrl + "news",
+		Aggregator: aggregator,
 		ConfigMap:  *configMapName,
 		Finalizer:  *hotNewsFinalizer,
 	}).SetupWithManager(mgr); err != nil {
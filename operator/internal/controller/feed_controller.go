@@ -3,9 +3,9 @@ package controller
 import (
 	"context"
 	"fmt"
-	"io"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
 	"log"
 	"net/http"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -15,25 +15,18 @@ import (
 	"slices"
 
 	aggregatorv1 "com.teamdev/news-aggregator/api/v1"
+	clientv1 "news-aggregator/client/v1"
 )
 
-// HttpClient defines methods for executing HTTP requests.
-// It is used by FeedReconciler to communicate with a news aggregator service.
-//
-//go:generate mockgen -source=feed_controller.go -destination=mock_aggregator/mock_http_client.go -package=controller  news-aggregator/operator/internal/controller HttpClient
-type HttpClient interface {
-	Do(req *http.Request) (*http.Response, error)
-	Post(url, contentType string, body io.Reader) (*http.Response, error)
-}
-
 // FeedReconciler is a k8s controller that manages Feed resources.
 // It uses the Client to interact with the Kubernetes API
-// and HttpClient to communicate with an external news aggregator service.
+// and the Aggregator client to manage the sources of the news aggregator service.
+//
+//go:generate mockgen -destination=mock_aggregator/mock_http_client.go -package=controller news-aggregator/client/v1 HTTPClient
 type FeedReconciler struct {
 	client.Client
 	Scheme        *runtime.Scheme
-	HttpClient    HttpClient
-	ServiceURL    string
+	Aggregator    *clientv1.Client
 	FeedFinalizer string
 }
 
@@ -66,7 +59,7 @@ func (r *FeedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	if !feed.ObjectMeta.DeletionTimestamp.IsZero() {
 		if slices.Contains(feed.ObjectMeta.Finalizers, r.FeedFinalizer) {
 			log.Printf("Handling deletion of Feed %s/%s", req.Namespace, req.Name)
-			if err := r.deleteFeed(feed); err != nil {
+			if err := r.deleteFeed(ctx, feed); err != nil {
 				feed.Status.AddCondition(aggregatorv1.Condition{
 					Type:    aggregatorv1.ConditionDeleted,
 					Status:  false,
@@ -92,7 +85,7 @@ func (r *FeedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	}
 	log.Printf("Current Feed Status Conditions: %v", feed.Status.Conditions)
 	if feed.Status.Contains(aggregatorv1.ConditionAdded, true) {
-		if err := r.updateFeed(feed); err != nil {
+		if err := r.updateFeed(ctx, feed); err != nil {
 			feed.Status.AddCondition(aggregatorv1.Condition{
 				Type:    aggregatorv1.ConditionUpdated,
 				Status:  false,
@@ -110,7 +103,7 @@ func (r *FeedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 			Message: "Feed updated successfully",
 		})
 	} else {
-		if err := r.createFeed(feed); err != nil {
+		if err := r.createFeed(ctx, feed); err != nil {
 			feed.Status.AddCondition(aggregatorv1.Condition{
 				Type:    aggregatorv1.ConditionAdded,
 				Status:  false,
@@ -136,33 +129,18 @@ func (r *FeedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 }
 
 // deleteFeed handles the deletion of a Feed.
-// It sends a DELETE request to the news aggregator service to delete the source.
-func (r *FeedReconciler) deleteFeed(feed aggregatorv1.Feed) error {
+// It removes the source of the Feed from the news aggregator service.
+func (r *FeedReconciler) deleteFeed(ctx context.Context, feed aggregatorv1.Feed) error {
 	log.Printf("Handling deletion for Feed %s", feed.Name)
 
-	req, err := http.NewRequest(http.MethodDelete,
-		fmt.Sprintf("%s?name=%s", r.ServiceURL, feed.Spec.Name), nil)
-	if err != nil {
-		log.Printf("Failed to create DELETE request: %v", err)
-		return err
+	err := r.Aggregator.DeleteSource(ctx, feed.Spec.Name, "")
+	if clientv1.IsStatus(err, http.StatusNotFound) {
+		log.Printf("Source %s was already removed", feed.Spec.Name)
+		return nil
 	}
-
-	resp, err := r.HttpClient.Do(req)
 	if err != nil {
-		log.Printf("Failed to make DELETE request: %v", err)
-		return err
-	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			log.Printf("Failed to close body: %v", err)
-		}
-	}(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		log.Printf("Failed to delete source, status code: %d, response: %s", resp.StatusCode, string(body))
-		return fmt.Errorf("failed to delete source, status code: %d", resp.StatusCode)
+		log.Printf("Failed to delete source %s: %v", feed.Spec.Name, err)
+		return fmt.Errorf("failed to delete source: %w", err)
 	}
 
 	log.Printf("Source %s deleted successfully", feed.Spec.Name)
@@ -170,62 +148,43 @@ func (r *FeedReconciler) deleteFeed(feed aggregatorv1.Feed) error {
 }
 
 // createFeed handles the creation of a new Feed.
-// It sends a POST request to the news aggregator service to add the new news source.
-func (r *FeedReconciler) createFeed(feed aggregatorv1.Feed) error {
+// It creates the news source of the Feed in the news aggregator service.
+func (r *FeedReconciler) createFeed(ctx context.Context, feed aggregatorv1.Feed) error {
 	log.Printf("Create Feed %s with URLs %s", feed.Spec.Name, feed.Spec.Link)
 
-	reqURL := fmt.Sprintf("%s?name=%s&url=%s", r.ServiceURL, feed.Spec.Name, feed.Spec.Link)
-
-	resp, err := r.HttpClient.Post(reqURL, "application/json", nil)
-	if err != nil {
-		log.Printf("Failed to make POST request: %v", err)
-		return err
+	_, err := r.Aggregator.CreateSource(ctx, clientv1.Source{Name: feed.Spec.Name, URL: feed.Spec.Link})
+	if clientv1.IsStatus(err, http.StatusConflict) {
+		// The source was created before, for instance by a reconciliation whose status update failed.
+		log.Printf("Source %s already exists, updating it", feed.Spec.Name)
+		return r.updateFeed(ctx, feed)
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			log.Printf("Failed to close response body: %v", err)
-		}
-	}(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		log.Printf("Failed to create source, status code: %d, response: %s", resp.StatusCode, string(body))
-		return fmt.Errorf("failed to create source, status code: %d", resp.StatusCode)
+	if err != nil {
+		log.Printf("Failed to create source %s: %v", feed.Spec.Name, err)
+		return fmt.Errorf("failed to create source: %w", err)
 	}
 	log.Print("Successfully created feed")
 	return nil
 }
 
 // updateFeed handles the updating of an existing Feed.
-// It sends a PUT request to the news aggregator service to update the news source.
-func (r *FeedReconciler) updateFeed(feed aggregatorv1.Feed) error {
+// It changes the URL of the news source if the source is unchanged since it was read,
+// retrying when it was changed in between. The other settings of the source are kept.
+func (r *FeedReconciler) updateFeed(ctx context.Context, feed aggregatorv1.Feed) error {
 	log.Printf("Updating Feed %s with URLs %s", feed.Spec.Name, feed.Spec.Link)
 
-	reqURL := fmt.Sprintf("%s?newUrl=%s&name=%s", r.ServiceURL, feed.Spec.Link, feed.Spec.Name)
-
-	req, err := http.NewRequest(http.MethodPut, reqURL, nil)
-	if err != nil {
-		log.Printf("Failed to create PUT request: %v", err)
-		return err
-	}
-
-	resp, err := r.HttpClient.Do(req)
-	if err != nil {
-		log.Printf("Failed to make PUT request: %v", err)
-		return err
-	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
+	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return clientv1.IsStatus(err, http.StatusPreconditionFailed)
+	}, func() error {
+		source, err := r.Aggregator.Source(ctx, feed.Spec.Name)
 		if err != nil {
-			log.Printf("Failed to close response body: %v", err)
+			return err
 		}
-	}(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		log.Printf("Failed to update source, status code: %d, response: %s", resp.StatusCode, string(body))
-		return fmt.Errorf("failed to update source, status code: %d", resp.StatusCode)
+		_, err = r.Aggregator.PatchSource(ctx, feed.Spec.Name, clientv1.SourcePatch{URL: &feed.Spec.Link}, source.ETag)
+		return err
+	})
+	if err != nil {
+		log.Printf("Failed to update source %s: %v", feed.Spec.Name, err)
+		return fmt.Errorf("failed to update source: %w", err)
 	}
 	log.Print("Successfully updated feed")
 	return nil
@@ -7,6 +7,7 @@ import (
 	mockaggregator "com.teamdev/news-aggregator/internal/controller/mock_aggregator"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -21,12 +22,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"time"
+
+	clientv1 "news-aggregator/client/v1"
 )
 
 var _ = Describe("FeedReconciler", func() {
 	var (
 		fakeClient     client.Client
-		mockHTTPClient *mockaggregator.MockHttpClient
+		mockHTTPClient *mockaggregator.MockHTTPClient
 		reconciler     *controller.FeedReconciler
 		ctx            context.Context
 		feed           *aggregatorv1.Feed
@@ -39,7 +43,7 @@ var _ = Describe("FeedReconciler", func() {
 	BeforeEach(func() {
 		ctx = context.TODO()
 		ctrl = gomock.NewController(GinkgoT())
-		mockHTTPClient = mockaggregator.NewMockHttpClient(ctrl)
+		mockHTTPClient = mockaggregator.NewMockHTTPClient(ctrl)
 		fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
 		mgr, err = manager.New(config.GetConfigOrDie(), manager.Options{
 			Scheme: scheme.Scheme,
@@ -53,8 +57,7 @@ var _ = Describe("FeedReconciler", func() {
 		reconciler = &controller.FeedReconciler{
 			Client:        fakeClient,
 			Scheme:        scheme.Scheme,
-			HttpClient:    mockHTTPClient,
-			ServiceURL:    "http://test-service",
+			Aggregator:    newAggregator(mockHTTPClient, -1),
 			FeedFinalizer: "test-finalizer",
 		}
 
@@ -102,11 +105,8 @@ var _ = Describe("FeedReconciler", func() {
 			reconciler.Client = fakeClient
 			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
 			mockHTTPClient.EXPECT().
-				Post(gomock.Any(), gomock.Any(), gomock.Any()).
-				Return(&http.Response{
-					StatusCode: http.StatusOK,
-					Body:       io.NopCloser(bytes.NewBufferString("")),
-				}, nil)
+				Do(sourceRequest(http.MethodPost, "")).
+				Return(sourceResponse(http.StatusCreated, `"v1"`), nil)
 
 			req := reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -127,14 +127,38 @@ var _ = Describe("FeedReconciler", func() {
 			Expect(feed.Status.Conditions[0].Type).To(Equal(aggregatorv1.ConditionAdded))
 			Expect(feed.Status.Conditions[0].Message).To(Equal("Feed added successfully"))
 		})
+		It("should update the source when it already exists", func() {
+			fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithStatusSubresource(&aggregatorv1.Feed{}).Build()
+			reconciler.Client = fakeClient
+			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
+			gomock.InOrder(
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodPost, "")).Return(sourceResponse(http.StatusConflict, ""), nil),
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodGet, "")).Return(sourceResponse(http.StatusOK, `"v1"`), nil),
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodPatch, `"v1"`)).Return(sourceResponse(http.StatusOK, `"v2"`), nil),
+			)
+
+			req := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "test-feed",
+					Namespace: "default",
+				},
+			}
+
+			res, err := reconciler.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Requeue).To(BeFalse())
+
+			feed := &aggregatorv1.Feed{}
+			err = reconciler.Client.Get(ctx, req.NamespacedName, feed)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(feed.Status.Conditions[0].Status).To(Equal(true))
+			Expect(feed.Status.Conditions[0].Type).To(Equal(aggregatorv1.ConditionAdded))
+		})
 		It("should handle POST success but fail to update status", func() {
 			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
 			mockHTTPClient.EXPECT().
-				Post(gomock.Any(), gomock.Any(), gomock.Any()).
-				Return(&http.Response{
-					StatusCode: http.StatusOK,
-					Body:       io.NopCloser(bytes.NewBufferString("")),
-				}, nil)
+				Do(sourceRequest(http.MethodPost, "")).
+				Return(sourceResponse(http.StatusCreated, `"v1"`), nil)
 
 			req := reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -180,11 +204,8 @@ var _ = Describe("FeedReconciler", func() {
 		It("should handle POST fails", func() {
 			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
 			mockHTTPClient.EXPECT().
-				Post(gomock.Any(), gomock.Any(), gomock.Any()).
-				Return(&http.Response{
-					StatusCode: http.StatusOK,
-					Body:       io.NopCloser(bytes.NewBufferString("")),
-				}, nil)
+				Do(sourceRequest(http.MethodPost, "")).
+				Return(sourceResponse(http.StatusCreated, `"v1"`), nil)
 
 			req := reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -202,7 +223,7 @@ var _ = Describe("FeedReconciler", func() {
 			reconciler.Client = fakeClient
 			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
 			mockHTTPClient.EXPECT().
-				Post("http://test-service?name=test-feed&url=http://test-example", "application/json", nil).
+				Do(sourceRequest(http.MethodPost, "")).
 				Return(nil, errors.New("error with Post request"))
 
 			req := reconcile.Request{
@@ -213,7 +234,7 @@ var _ = Describe("FeedReconciler", func() {
 			}
 
 			res, err := reconciler.Reconcile(ctx, req)
-			Expect(err).To(MatchError("error with Post request"))
+			Expect(err).To(MatchError("failed to create source: error with Post request"))
 			Expect(res.Requeue).To(BeFalse())
 
 			feed = &aggregatorv1.Feed{}
@@ -229,11 +250,8 @@ var _ = Describe("FeedReconciler", func() {
 			var req reconcile.Request
 			BeforeEach(func() {
 				mockHTTPClient.EXPECT().
-					Post(gomock.Any(), gomock.Any(), gomock.Any()).
-					Return(&http.Response{
-						StatusCode: http.StatusInternalServerError,
-						Body:       io.NopCloser(bytes.NewBufferString("Internal Server Error")),
-					}, nil)
+					Do(sourceRequest(http.MethodPost, "")).
+					Return(sourceResponse(http.StatusInternalServerError, ""), nil)
 
 				req = reconcile.Request{
 					NamespacedName: types.NamespacedName{
@@ -248,7 +266,8 @@ var _ = Describe("FeedReconciler", func() {
 				Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
 
 				res, err := reconciler.Reconcile(ctx, req)
-				Expect(err).To(MatchError("failed to create source, status code: 500"))
+				Expect(err).To(MatchError(ContainSubstring("failed to create source: POST http://test-service/v1/sources: 500")))
+				Expect(clientv1.IsStatus(err, http.StatusInternalServerError)).To(BeTrue())
 				Expect(res.Requeue).To(BeFalse())
 
 				feed = &aggregatorv1.Feed{}
@@ -287,12 +306,10 @@ var _ = Describe("FeedReconciler", func() {
 			reconciler.Client = fakeClient
 			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
 
-			mockHTTPClient.EXPECT().
-				Do(gomock.Any()).
-				Return(&http.Response{
-					StatusCode: http.StatusOK,
-					Body:       io.NopCloser(bytes.NewBufferString("")),
-				}, nil)
+			gomock.InOrder(
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodGet, "")).Return(sourceResponse(http.StatusOK, `"v1"`), nil),
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodPatch, `"v1"`)).Return(sourceResponse(http.StatusOK, `"v2"`), nil),
+			)
 
 			req := reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -314,12 +331,10 @@ var _ = Describe("FeedReconciler", func() {
 		})
 		It("should handle a successful update but fail to update the status", func() {
 			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
-			mockHTTPClient.EXPECT().
-				Do(gomock.Any()).
-				Return(&http.Response{
-					StatusCode: http.StatusOK,
-					Body:       io.NopCloser(bytes.NewBufferString("")),
-				}, nil)
+			gomock.InOrder(
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodGet, "")).Return(sourceResponse(http.StatusOK, `"v1"`), nil),
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodPatch, `"v1"`)).Return(sourceResponse(http.StatusOK, `"v2"`), nil),
+			)
 
 			req := reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -332,13 +347,42 @@ var _ = Describe("FeedReconciler", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(res.Requeue).To(BeFalse())
 		})
-		It("should handle errors when sending the Put request", func() {
+		It("should retry the update when the source changed in between", func() {
 			fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithStatusSubresource(&aggregatorv1.Feed{}).Build()
 			reconciler.Client = fakeClient
 			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
-			mockHTTPClient.EXPECT().
-				Do(gomock.Any()).
-				Return(nil, errors.New("error with PUT request"))
+			gomock.InOrder(
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodGet, "")).Return(sourceResponse(http.StatusOK, `"v1"`), nil),
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodPatch, `"v1"`)).Return(sourceResponse(http.StatusPreconditionFailed, ""), nil),
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodGet, "")).Return(sourceResponse(http.StatusOK, `"v2"`), nil),
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodPatch, `"v2"`)).Return(sourceResponse(http.StatusOK, `"v3"`), nil),
+			)
+
+			req := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "test-feed",
+					Namespace: "default",
+				},
+			}
+
+			res, err := reconciler.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Requeue).To(BeFalse())
+
+			feed := &aggregatorv1.Feed{}
+			err = reconciler.Client.Get(ctx, req.NamespacedName, feed)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(feed.Status.Conditions[1].Status).To(Equal(true))
+			Expect(feed.Status.Conditions[1].Type).To(Equal(aggregatorv1.ConditionUpdated))
+		})
+		It("should handle errors when sending the PATCH request", func() {
+			fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithStatusSubresource(&aggregatorv1.Feed{}).Build()
+			reconciler.Client = fakeClient
+			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
+			gomock.InOrder(
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodGet, "")).Return(sourceResponse(http.StatusOK, `"v1"`), nil),
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodPatch, `"v1"`)).Return(nil, errors.New("error with PATCH request")),
+			)
 
 			req := reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -348,7 +392,7 @@ var _ = Describe("FeedReconciler", func() {
 			}
 
 			res, err := reconciler.Reconcile(ctx, req)
-			Expect(err).To(MatchError("error with PUT request"))
+			Expect(err).To(MatchError("failed to update source: error with PATCH request"))
 			Expect(res.Requeue).To(BeFalse())
 
 			feed = &aggregatorv1.Feed{}
@@ -358,11 +402,17 @@ var _ = Describe("FeedReconciler", func() {
 			Expect(feed.Status.Conditions[1].Type).To(Equal(aggregatorv1.ConditionUpdated))
 			Expect(feed.Status.Conditions[1].Message).To(Equal("Feed didn't update successfully"))
 		})
-		It("should handle issues with the URL scheme in update request", func() {
+		It("should retry requests the news aggregator service failed", func() {
 			fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithStatusSubresource(&aggregatorv1.Feed{}).Build()
 			reconciler.Client = fakeClient
+			reconciler.Aggregator = newAggregator(mockHTTPClient, 1)
 			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
-			reconciler.ServiceURL = "://bad-url"
+			gomock.InOrder(
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodGet, "")).Return(sourceResponse(http.StatusServiceUnavailable, ""), nil),
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodGet, "")).Return(sourceResponse(http.StatusOK, `"v1"`), nil),
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodPatch, `"v1"`)).Return(nil, errors.New("connection reset")),
+				mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodPatch, `"v1"`)).Return(sourceResponse(http.StatusOK, `"v2"`), nil),
+			)
 
 			req := reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -372,25 +422,22 @@ var _ = Describe("FeedReconciler", func() {
 			}
 
 			res, err := reconciler.Reconcile(ctx, req)
-			Expect(err.Error()).To(ContainSubstring("missing protocol scheme"))
+			Expect(err).ToNot(HaveOccurred())
 			Expect(res.Requeue).To(BeFalse())
 
 			feed = &aggregatorv1.Feed{}
 			err = reconciler.Client.Get(ctx, req.NamespacedName, feed)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(feed.Status.Conditions[1].Status).To(Equal(false))
+			Expect(feed.Status.Conditions[1].Status).To(Equal(true))
 			Expect(feed.Status.Conditions[1].Type).To(Equal(aggregatorv1.ConditionUpdated))
-			Expect(feed.Status.Conditions[1].Message).To(Equal("Feed didn't update successfully"))
 		})
-		Context("PUT Request Error Status Handling", func() {
+		Context("PATCH Request Error Status Handling", func() {
 			var req reconcile.Request
 			BeforeEach(func() {
-				mockHTTPClient.EXPECT().
-					Do(gomock.Any()).
-					Return(&http.Response{
-						StatusCode: http.StatusInternalServerError,
-						Body:       io.NopCloser(bytes.NewBufferString("Internal Server Error")),
-					}, nil)
+				gomock.InOrder(
+					mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodGet, "")).Return(sourceResponse(http.StatusOK, `"v1"`), nil),
+					mockHTTPClient.EXPECT().Do(sourceRequest(http.MethodPatch, `"v1"`)).Return(sourceResponse(http.StatusInternalServerError, ""), nil),
+				)
 
 				req = reconcile.Request{
 					NamespacedName: types.NamespacedName{
@@ -400,13 +447,14 @@ var _ = Describe("FeedReconciler", func() {
 				}
 			})
 
-			It("should handle PUT request failure but feed updated", func() {
+			It("should handle PATCH request failure but feed updated", func() {
 				fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithStatusSubresource(&aggregatorv1.Feed{}).Build()
 				reconciler.Client = fakeClient
 				Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
 
 				res, err := reconciler.Reconcile(ctx, req)
-				Expect(err).To(MatchError("failed to update source, status code: 500"))
+				Expect(err).To(MatchError(ContainSubstring("failed to update source: PATCH http://test-service/v1/sources/test-feed: 500")))
+				Expect(clientv1.IsStatus(err, http.StatusInternalServerError)).To(BeTrue())
 				Expect(res.Requeue).To(BeFalse())
 
 				feed = &aggregatorv1.Feed{}
@@ -417,7 +465,7 @@ var _ = Describe("FeedReconciler", func() {
 				Expect(feed.Status.Conditions[1].Message).To(Equal("Feed didn't update successfully"))
 			})
 
-			It("should handle PUT request failure but feed not updated", func() {
+			It("should handle PATCH request failure but feed not updated", func() {
 				fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
 				reconciler.Client = fakeClient
 				Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
@@ -430,6 +478,33 @@ var _ = Describe("FeedReconciler", func() {
 	})
 
 	Context("when deleting a Feed resource", func() {
+		It("should finish the deletion when the source was already removed", func() {
+			fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithStatusSubresource(&aggregatorv1.Feed{}).Build()
+			reconciler.Client = fakeClient
+			feed.Finalizers = []string{"test-finalizer"}
+
+			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
+			Expect(reconciler.Client.Delete(ctx, feed)).To(Succeed())
+
+			mockHTTPClient.EXPECT().
+				Do(sourceRequest(http.MethodDelete, "")).
+				Return(sourceResponse(http.StatusNotFound, ""), nil)
+
+			req := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "test-feed",
+					Namespace: "default",
+				},
+			}
+
+			res, err := reconciler.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Requeue).To(BeFalse())
+
+			feed = &aggregatorv1.Feed{}
+			err = reconciler.Client.Get(ctx, req.NamespacedName, feed)
+			Expect(err).To(HaveOccurred())
+		})
 		It("should successfully delete the feed", func() {
 			fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithStatusSubresource(&aggregatorv1.Feed{}).Build()
 			reconciler.Client = fakeClient
@@ -439,11 +514,8 @@ var _ = Describe("FeedReconciler", func() {
 			Expect(reconciler.Client.Delete(ctx, feed)).To(Succeed())
 
 			mockHTTPClient.EXPECT().
-				Do(gomock.Any()).
-				Return(&http.Response{
-					StatusCode: http.StatusOK,
-					Body:       io.NopCloser(bytes.NewBufferString("")),
-				}, nil)
+				Do(sourceRequest(http.MethodDelete, "")).
+				Return(sourceResponse(http.StatusNoContent, ""), nil)
 
 			req := reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -485,11 +557,8 @@ var _ = Describe("FeedReconciler", func() {
 			Expect(reconciler.Client.Delete(ctx, feed)).To(Succeed())
 
 			mockHTTPClient.EXPECT().
-				Do(gomock.Any()).
-				Return(&http.Response{
-					StatusCode: http.StatusOK,
-					Body:       io.NopCloser(bytes.NewBufferString("")),
-				}, nil)
+				Do(sourceRequest(http.MethodDelete, "")).
+				Return(sourceResponse(http.StatusNoContent, ""), nil)
 
 			req := reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -514,7 +583,7 @@ var _ = Describe("FeedReconciler", func() {
 			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
 			Expect(reconciler.Client.Delete(ctx, feed)).To(Succeed())
 			mockHTTPClient.EXPECT().
-				Do(gomock.Any()).
+				Do(sourceRequest(http.MethodDelete, "")).
 				Return(nil, errors.New("error with DELETE request"))
 
 			req := reconcile.Request{
@@ -525,7 +594,7 @@ var _ = Describe("FeedReconciler", func() {
 			}
 
 			res, err := reconciler.Reconcile(ctx, req)
-			Expect(err).To(MatchError("error with DELETE request"))
+			Expect(err).To(MatchError("failed to delete source: error with DELETE request"))
 			Expect(res.Requeue).To(BeFalse())
 
 			feed = &aggregatorv1.Feed{}
@@ -535,14 +604,18 @@ var _ = Describe("FeedReconciler", func() {
 			Expect(feed.Status.Conditions[0].Type).To(Equal(aggregatorv1.ConditionDeleted))
 			Expect(feed.Status.Conditions[0].Message).To(Equal("Failed to delete feed"))
 		})
-		It("should handle issues with an invalid URL scheme during the DELETE request", func() {
+		It("should fail the deletion when the news aggregator service keeps failing", func() {
 			fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithStatusSubresource(&aggregatorv1.Feed{}).Build()
 			reconciler.Client = fakeClient
-			reconciler.ServiceURL = "://bad-url"
+			reconciler.Aggregator = newAggregator(mockHTTPClient, 1)
 			feed.Finalizers = []string{"test-finalizer"}
 
 			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
 			Expect(reconciler.Client.Delete(ctx, feed)).To(Succeed())
+			mockHTTPClient.EXPECT().
+				Do(sourceRequest(http.MethodDelete, "")).
+				Return(sourceResponse(http.StatusBadGateway, ""), nil).
+				Times(2)
 
 			req := reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -552,7 +625,7 @@ var _ = Describe("FeedReconciler", func() {
 			}
 
 			res, err := reconciler.Reconcile(ctx, req)
-			Expect(err.Error()).To(ContainSubstring("missing protocol scheme"))
+			Expect(err).To(MatchError(clientv1.ErrServer))
 			Expect(res.Requeue).To(BeFalse())
 
 			feed = &aggregatorv1.Feed{}
@@ -570,11 +643,8 @@ var _ = Describe("FeedReconciler", func() {
 			Expect(reconciler.Client.Create(ctx, feed)).To(Succeed())
 			Expect(reconciler.Client.Delete(ctx, feed)).To(Succeed())
 			mockHTTPClient.EXPECT().
-				Do(gomock.Any()).
-				Return(&http.Response{
-					StatusCode: http.StatusInternalServerError,
-					Body:       io.NopCloser(bytes.NewBufferString("Internal Server Error")),
-				}, nil)
+				Do(sourceRequest(http.MethodDelete, "")).
+				Return(sourceResponse(http.StatusInternalServerError, ""), nil)
 
 			req := reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -600,3 +670,58 @@ var _ = Describe("FeedReconciler", func() {
 		})
 	})
 })
+
+// newAggregator client of the news aggregator service sending its requests with the HTTP client.
+func newAggregator(httpClient clientv1.HTTPClient, maxRetries int) *clientv1.Client {
+	aggregator, err := clientv1.New(clientv1.Config{
+		ServerURL:  "http://test-service",
+		HTTPClient: httpClient,
+		MaxRetries: maxRetries,
+		Backoff:    time.Millisecond,
+	})
+	Expect(err).ToNot(HaveOccurred())
+	return aggregator
+}
+
+// requestMatcher matches requests for the source of the test Feed by method and If-Match header.
+type requestMatcher struct {
+	method  string
+	ifMatch string
+}
+
+// sourceRequest matches a request for the source with the method and If-Match header.
+func sourceRequest(method, ifMatch string) gomock.Matcher {
+	return requestMatcher{method, ifMatch}
+}
+
+func (m requestMatcher) Matches(x interface{}) bool {
+	req, ok := x.(*http.Request)
+	if !ok || req.Method != m.method || req.Header.Get("If-Match") != m.ifMatch {
+		return false
+	}
+	if m.method == http.MethodPost {
+		return req.URL.String() == "http://test-service/v1/sources"
+	}
+	return req.URL.String() == "http://test-service/v1/sources/test-feed"
+}
+
+func (m requestMatcher) String() string {
+	return fmt.Sprintf("is a %s request for the source with If-Match %q", m.method, m.ifMatch)
+}
+
+// sourceResponse of the news aggregator service with the status code and ETag header.
+func sourceResponse(statusCode int, etag string) *http.Response {
+	body := ""
+	if statusCode == http.StatusOK || statusCode == http.StatusCreated {
+		body = `{"name": "test-feed", "url": "http://test-example"}`
+	}
+	resp := &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+	}
+	if etag != "" {
+		resp.Header.Set("ETag", etag)
+	}
+	return resp
+}
@@ -5,15 +5,12 @@ import (
 	"com.teamdev/news-aggregator/internal/controller/handlers"
 	"com.teamdev/news-aggregator/internal/controller/predicates"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"log"
-	"net/http"
-	"net/url"
+	clientv1 "news-aggregator/client/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -30,21 +27,11 @@ import (
 type HotNewsReconciler struct {
 	client.Client
 	Scheme     *runtime.Scheme
-	HttpClient HttpClient
-	ServiceURL string
+	Aggregator *clientv1.Client
 	ConfigMap  string
 	Finalizer  string
 }
 
-// NewsTitle represents a single news article title retrieved from
-// the external news service.
-type NewsTitle struct {
-	Title string `json:"Title"`
-}
-
-// NewsResponse represents a collection of NewsTitle elements the external news service returns.
-type NewsResponse []NewsTitle
-
 // +kubebuilder:rbac:groups=aggregator.com.teamdev,resources=hotnews,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=aggregator.com.teamdev,resources=hotnews/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=aggregator.com.teamdev,resources=hotnews/finalizers,verbs=update
@@ -133,7 +120,7 @@ func (r *HotNewsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	sources := feeds.GetNewsSources(feedNames)
 	log.Printf("Final list of news names: %v", sources)
 
-	status, err := r.fetchNewsData(sources, hotNews.Spec)
+	status, err := r.fetchNewsData(ctx, sources, hotNews.Spec)
 	if err != nil {
 		hotNews.Status = aggregatorv1.SetHotNewsErrorStatus(err.Error())
 		if err := r.Status().Update(ctx, &hotNews); err != nil {
@@ -161,107 +148,45 @@ func (r *HotNewsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	return reconcile.Result{}, nil
 }
 
-// fetchNewsData constructs a request URL using the provided sources and HotNews specifications,
-// then sends a request to the news service to retrieve news data. It returns the status of the HotNews
-// with the fetched articles or an error if the process fails.
-func (r *HotNewsReconciler) fetchNewsData(sources []string, hotNews aggregatorv1.HotNewsSpec) (aggregatorv1.HotNewsStatus, error) {
+// fetchNewsData requests the news of the sources matching the HotNews specification from the news service.
+// Only the first TitlesCount news are requested, the articles count is the total of the matching news.
+// It returns the status of the HotNews with the fetched articles or an error if the request fails.
+func (r *HotNewsReconciler) fetchNewsData(ctx context.Context, sources []string, hotNews aggregatorv1.HotNewsSpec) (aggregatorv1.HotNewsStatus, error) {
 	log.Printf("Starting fetchNewsData with sources: %v, keywords: %v, dateStart: %s, dateEnd: %s", sources, hotNews.Keywords, hotNews.DateStart, hotNews.DateEnd)
-	reqURL, err := r.buildRequestURL(sources, hotNews.Keywords, hotNews.DateStart, hotNews.DateEnd)
-	if err != nil {
-		log.Printf("Error building request: %v", err)
-		return aggregatorv1.HotNewsStatus{}, err
-	}
-
-	status, err := r.makeRequest(reqURL, hotNews.SummaryConfig.TitlesCount)
-	if err != nil {
-		log.Printf("Error making request: %v", err)
-		return aggregatorv1.HotNewsStatus{}, err
-	}
-	log.Printf("Request completed successfully, received status: %+v", status)
-
-	return status, nil
-}
-
-// buildRequestURL constructs the URL for the news service request based on the provided sources,
-// keywords, and date range. It returns the formatted URL or an error if the URL cannot be constructed
-func (r *HotNewsReconciler) buildRequestURL(sources, keywords []string, dateStart, dateEnd string) (string, error) {
-	baseURL, err := url.Parse(r.ServiceURL)
-	if err != nil {
-		return "", fmt.Errorf("invalid service URL: %s", r.ServiceURL)
-	}
-
-	params := url.Values{}
-
-	if len(sources) > 0 {
-		params.Add("sources", strings.Join(sources, ","))
-	}
-
-	if len(keywords) > 0 {
-		params.Add("keywords", strings.Join(keywords, ","))
-	} else {
-		return "", fmt.Errorf("keywords not found")
-	}
-
-	if dateStart != "" {
-		params.Add("date-start", dateStart)
+	if len(hotNews.Keywords) == 0 {
+		return aggregatorv1.HotNewsStatus{}, fmt.Errorf("keywords not found")
 	}
-	if dateEnd != "" {
-		params.Add("date-end", dateEnd)
+	query := clientv1.NewsQuery{
+		Sources:   sources,
+		Keywords:  strings.Join(hotNews.Keywords, ","),
+		DateStart: hotNews.DateStart,
+		DateEnd:   hotNews.DateEnd,
+		SortOrder: "asc",
 	}
+	newsLink := r.Aggregator.NewsURL(query)
+	query.Limit = hotNews.SummaryConfig.TitlesCount
 
-	params.Add("sort-order", "asc")
-	baseURL.RawQuery = params.Encode()
-
-	return baseURL.String(), nil
-}
-
-// makeRequest performs the HTTP request to the news service and processes the response.
-// It returns the HotNewsStatus populated with the articles and titles or an error if the request fails.
-func (r *HotNewsReconciler) makeRequest(reqURL string, titleCount int) (aggregatorv1.HotNewsStatus, error) {
-	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	page, err := r.Aggregator.News(ctx, query)
 	if err != nil {
-		log.Printf("Failed to create Get request: %v", err)
-		return aggregatorv1.HotNewsStatus{}, err
-	}
-
-	resp, err := r.HttpClient.Do(req)
-	if err != nil {
-		log.Printf("Failed to make Get request: %v", err)
-		return aggregatorv1.HotNewsStatus{}, err
-	}
-
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			log.Printf("Failed to close response body: %v", err)
-		}
-	}(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("Failed to create source, status code: %d", resp.StatusCode)
-		return aggregatorv1.HotNewsStatus{}, fmt.Errorf("failed to create source, status code: %d", resp.StatusCode)
-	}
-
-	var newsResponse NewsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&newsResponse); err != nil {
-		log.Printf("Failed to decode response: %v", err)
+		log.Printf("Error requesting news: %v", err)
 		return aggregatorv1.HotNewsStatus{}, err
 	}
 
 	var titles []string
-	for i := range newsResponse {
-		titles = append(titles, newsResponse[i].Title)
-		if i >= titleCount-1 {
+	for i := range page.Items {
+		titles = append(titles, string(page.Items[i].Title))
+		if i >= hotNews.SummaryConfig.TitlesCount-1 {
 			break
 		}
 	}
-
-	return aggregatorv1.HotNewsStatus{
-		ArticlesCount:  len(newsResponse),
-		NewsLink:       reqURL,
+	status := aggregatorv1.HotNewsStatus{
+		ArticlesCount:  page.Total,
+		NewsLink:       newsLink,
 		ArticlesTitles: titles,
 		Condition:      aggregatorv1.HotNewsCondition{Status: true},
-	}, nil
+	}
+	log.Printf("Request completed successfully, received status: %+v", status)
+	return status, nil
 }
 
 // SetupWithManager configures the HotNewsReconciler to manage resources and adds the necessary event predicates
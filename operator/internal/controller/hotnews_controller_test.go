@@ -23,12 +23,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clientv1 "news-aggregator/client/v1"
 )
 
 var _ = Describe("HotNewsReconciler", func() {
 	var (
 		fakeClient     client.Client
-		mockHTTPClient *mockaggregator.MockHttpClient
+		mockHTTPClient *mockaggregator.MockHTTPClient
 		reconciler     *controller.HotNewsReconciler
 		ctx            context.Context
 		feed           *v1.Feed
@@ -40,7 +42,7 @@ var _ = Describe("HotNewsReconciler", func() {
 	BeforeEach(func() {
 		ctx = context.TODO()
 		ctrl = gomock.NewController(GinkgoT())
-		mockHTTPClient = mockaggregator.NewMockHttpClient(ctrl)
+		mockHTTPClient = mockaggregator.NewMockHTTPClient(ctrl)
 		fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
 		mgr, err = manager.New(config.GetConfigOrDie(), manager.Options{
 			Scheme: scheme.Scheme,
@@ -49,8 +51,7 @@ var _ = Describe("HotNewsReconciler", func() {
 		reconciler = &controller.HotNewsReconciler{
 			Client:     fakeClient,
 			Scheme:     scheme.Scheme,
-			HttpClient: mockHTTPClient,
-			ServiceURL: "http://test-service",
+			Aggregator: newAggregator(mockHTTPClient, -1),
 			ConfigMap:  "test-configmap",
 			Finalizer:  "test-finalizer",
 		}
@@ -279,11 +280,14 @@ var _ = Describe("HotNewsReconciler", func() {
 						return nil, fmt.Errorf("expected GET method, got %s", req.Method)
 					}
 
-					response := `[
+					if req.URL.Query().Get("limit") != "3" {
+						return nil, fmt.Errorf("expected limit 3, got %s", req.URL.Query().Get("limit"))
+					}
+					response := `{"items": [
 					{"Title": "News 1"},
 					{"Title": "News 2"},
 					{"Title": "News 3"}
-				]`
+				], "total": 5}`
 					return &http.Response{
 						StatusCode: http.StatusOK,
 						Body:       io.NopCloser(bytes.NewBufferString(response)),
@@ -298,10 +302,10 @@ var _ = Describe("HotNewsReconciler", func() {
 			var updatedHotNews v1.HotNews
 			Expect(fakeClient.Get(ctx, req.NamespacedName, &updatedHotNews)).To(Succeed())
 
-			Expect(updatedHotNews.Status.ArticlesCount).To(Equal(3))
+			Expect(updatedHotNews.Status.ArticlesCount).To(Equal(5))
 			Expect(updatedHotNews.Status.ArticlesTitles).To(ConsistOf("News 1", "News 2", "News 3"))
 			Expect(updatedHotNews.Status.NewsLink).
-				To(Equal(fmt.Sprintf("http://test-service?date-end=%s&date-start=%s&keywords=test-keyword&sort-order=asc&sources=test-feed",
+				To(Equal(fmt.Sprintf("http://test-service/news?date-end=%s&date-start=%s&keywords=test-keyword&sort-order=asc&sources=test-feed",
 					hotNews.Spec.DateEnd, hotNews.Spec.DateStart)))
 		})
 		It("should be error Feed not found with wrong status update", func() {
@@ -369,42 +373,7 @@ var _ = Describe("HotNewsReconciler", func() {
 			Expect(updatedHotNews.Status.Condition.Status).To(Equal(false))
 		})
 	})
-	Context("when buildRequestURL fails", func() {
-		It("should return an error due to an invalid url", func() {
-			reconciler.ServiceURL = "http://example.com/%ZZ"
-			fakeClient := fake.NewClientBuilder().WithStatusSubresource(&v1.HotNews{}).Build()
-			reconciler.Client = fakeClient
-
-			hotNews := &v1.HotNews{
-				ObjectMeta: v12.ObjectMeta{
-					Name:      "test-hotnews",
-					Namespace: "default",
-				},
-				Spec: v1.HotNewsSpec{
-					Feeds:    []string{"test-feed"},
-					Keywords: []string{"test-keyword"},
-				},
-			}
-
-			Expect(fakeClient.Create(ctx, hotNews)).To(Succeed())
-			Expect(fakeClient.Create(ctx, feed)).To(Succeed())
-			req := reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Namespace: "default",
-					Name:      "test-hotnews",
-				},
-			}
-
-			result, err := reconciler.Reconcile(ctx, req)
-
-			Expect(err).To(MatchError(fmt.Sprintf("invalid service URL: %s", reconciler.ServiceURL)))
-			Expect(result.Requeue).To(BeFalse())
-
-			var updatedHotNews v1.HotNews
-			Expect(fakeClient.Get(ctx, req.NamespacedName, &updatedHotNews)).To(Succeed())
-			Expect(updatedHotNews.Status.Condition.Reason).To(Equal(fmt.Sprintf("invalid service URL: %s", reconciler.ServiceURL)))
-		})
-
+	Context("when the news query is invalid", func() {
 		It("should return an error for missing keyword", func() {
 			fakeClient := fake.NewClientBuilder().WithStatusSubresource(&v1.HotNews{}).Build()
 			reconciler.Client = fakeClient
@@ -439,7 +408,7 @@ var _ = Describe("HotNewsReconciler", func() {
 			Expect(updatedHotNews.Status.Condition.Reason).To(Equal("keywords not found"))
 		})
 	})
-	Context("when makeRequest fails", func() {
+	Context("when requesting the news fails", func() {
 		var (
 			req        reconcile.Request
 			hotNews    *v1.HotNews
@@ -499,12 +468,12 @@ var _ = Describe("HotNewsReconciler", func() {
 
 			result, err := reconciler.Reconcile(ctx, req)
 
-			Expect(err).To(MatchError("invalid character 'o' in literal null (expecting 'u')"))
+			Expect(err).To(MatchError(ContainSubstring("invalid character 'o' in literal null (expecting 'u')")))
 			Expect(result.Requeue).To(BeFalse())
 
 			var updatedHotNews v1.HotNews
 			Expect(fakeClient.Get(ctx, req.NamespacedName, &updatedHotNews)).To(Succeed())
-			Expect(updatedHotNews.Status.Condition.Reason).To(Equal("invalid character 'o' in literal null (expecting 'u')"))
+			Expect(updatedHotNews.Status.Condition.Reason).To(ContainSubstring("invalid character 'o' in literal null (expecting 'u')"))
 		})
 		It("should return an error with HTTP response status", func() {
 			mockHTTPClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
@@ -516,12 +485,35 @@ var _ = Describe("HotNewsReconciler", func() {
 
 			result, err := reconciler.Reconcile(ctx, req)
 
-			Expect(err).To(MatchError("failed to create source, status code: 500"))
+			Expect(err).To(MatchError(clientv1.ErrServer))
+			Expect(result.Requeue).To(BeFalse())
+
+			var updatedHotNews v1.HotNews
+			Expect(fakeClient.Get(ctx, req.NamespacedName, &updatedHotNews)).To(Succeed())
+			Expect(updatedHotNews.Status.Condition.Reason).To(HavePrefix("GET http://test-service/news?"))
+			Expect(updatedHotNews.Status.Condition.Reason).To(HaveSuffix(": 500 Internal Server Error"))
+		})
+		It("should retry the request when the news service is unavailable", func() {
+			reconciler.Aggregator = newAggregator(mockHTTPClient, 1)
+			gomock.InOrder(
+				mockHTTPClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Body:       io.NopCloser(bytes.NewBufferString("")),
+				}, nil),
+				mockHTTPClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"items": [{"Title": "News 1"}], "total": 1}`)),
+				}, nil),
+			)
+
+			result, err := reconciler.Reconcile(ctx, req)
+
+			Expect(err).ToNot(HaveOccurred())
 			Expect(result.Requeue).To(BeFalse())
 
 			var updatedHotNews v1.HotNews
 			Expect(fakeClient.Get(ctx, req.NamespacedName, &updatedHotNews)).To(Succeed())
-			Expect(updatedHotNews.Status.Condition.Reason).To(Equal("failed to create source, status code: 500"))
+			Expect(updatedHotNews.Status.ArticlesTitles).To(ConsistOf("News 1"))
 		})
 		It("should return an error with closing response body", func() {
 			mockHTTPClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
@@ -533,12 +525,12 @@ var _ = Describe("HotNewsReconciler", func() {
 
 			result, err := reconciler.Reconcile(ctx, req)
 
-			Expect(err).To(MatchError("failed to close response body: mock error"))
+			Expect(err).To(MatchError(ContainSubstring("failed to close response body: mock error")))
 			Expect(result.Requeue).To(BeFalse())
 
 			var updatedHotNews v1.HotNews
 			Expect(fakeClient.Get(ctx, req.NamespacedName, &updatedHotNews)).To(Succeed())
-			Expect(updatedHotNews.Status.Condition.Reason).To(Equal("failed to close response body: mock error"))
+			Expect(updatedHotNews.Status.Condition.Reason).To(ContainSubstring("failed to close response body: mock error"))
 		})
 	})
 	Context("when HotNews uses FeedGroups", func() {
@@ -589,11 +581,11 @@ var _ = Describe("HotNewsReconciler", func() {
 			}
 
 			mockHTTPClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
-				response := `[
+				response := `{"items": [
                 {"Title": "News 1"},
                 {"Title": "News 2"},
                 {"Title": "News 3"}
-            ]`
+            ], "total": 3}`
 				return &http.Response{
 					StatusCode: http.StatusOK,
 					Body:       io.NopCloser(bytes.NewBufferString(response)),
@@ -611,7 +603,7 @@ var _ = Describe("HotNewsReconciler", func() {
 			Expect(updatedHotNews.Status.ArticlesCount).To(Equal(3))
 			Expect(updatedHotNews.Status.ArticlesTitles).To(ConsistOf("News 1", "News 2", "News 3"))
 			Expect(updatedHotNews.Status.NewsLink).
-				To(Equal(fmt.Sprintf("http://test-service?date-end=%s&date-start=%s&keywords=test-keyword&sort-order=asc&sources=test-feed",
+				To(Equal(fmt.Sprintf("http://test-service/news?date-end=%s&date-start=%s&keywords=test-keyword&sort-order=asc&sources=test-feed",
 					hotNews.Spec.DateEnd, hotNews.Spec.DateStart)))
 		})
 		It("should return an error status update if ConfigMap is not found", func() {
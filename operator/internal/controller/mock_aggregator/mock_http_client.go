@@ -1,66 +1,50 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: feed_controller.go
+// Source: news-aggregator/client/v1 (interfaces: HTTPClient)
 
 // Package controller is a generated GoMock package.
 package controller
 
 import (
-	io "io"
 	http "net/http"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 )
 
-// MockHttpClient is a mock of HttpClient interface.
-type MockHttpClient struct {
+// MockHTTPClient is a mock of HTTPClient interface.
+type MockHTTPClient struct {
 	ctrl     *gomock.Controller
-	recorder *MockHttpClientMockRecorder
+	recorder *MockHTTPClientMockRecorder
 }
 
-// MockHttpClientMockRecorder is the mock recorder for MockHttpClient.
-type MockHttpClientMockRecorder struct {
-	mock *MockHttpClient
+// MockHTTPClientMockRecorder is the mock recorder for MockHTTPClient.
+type MockHTTPClientMockRecorder struct {
+	mock *MockHTTPClient
 }
 
-// NewMockHttpClient creates a new mock instance.
-func NewMockHttpClient(ctrl *gomock.Controller) *MockHttpClient {
-	mock := &MockHttpClient{ctrl: ctrl}
-	mock.recorder = &MockHttpClientMockRecorder{mock}
+// NewMockHTTPClient creates a new mock instance.
+func NewMockHTTPClient(ctrl *gomock.Controller) *MockHTTPClient {
+	mock := &MockHTTPClient{ctrl: ctrl}
+	mock.recorder = &MockHTTPClientMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockHttpClient) EXPECT() *MockHttpClientMockRecorder {
+func (m *MockHTTPClient) EXPECT() *MockHTTPClientMockRecorder {
 	return m.recorder
 }
 
 // Do mocks base method.
-func (m *MockHttpClient) Do(req *http.Request) (*http.Response, error) {
+func (m *MockHTTPClient) Do(arg0 *http.Request) (*http.Response, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Do", req)
+	ret := m.ctrl.Call(m, "Do", arg0)
 	ret0, _ := ret[0].(*http.Response)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Do indicates an expected call of Do.
-func (mr *MockHttpClientMockRecorder) Do(req interface{}) *gomock.Call {
+func (mr *MockHTTPClientMockRecorder) Do(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockHttpClient)(nil).Do), req)
-}
-
-// Post mocks base method.
-func (m *MockHttpClient) Post(url, contentType string, body io.Reader) (*http.Response, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Post", url, contentType, body)
-	ret0, _ := ret[0].(*http.Response)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// Post indicates an expected call of Post.
-func (mr *MockHttpClientMockRecorder) Post(url, contentType, body interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockHttpClient)(nil).Post), url, contentType, body)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockHTTPClient)(nil).Do), arg0)
 }
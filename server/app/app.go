@@ -0,0 +1,199 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"news-aggregator/server/handlers"
+	"news-aggregator/server/managers"
+	"news-aggregator/server/service"
+	"os"
+	"time"
+)
+
+// shutdownTimeout for finishing the requests in flight on shutdown.
+const shutdownTimeout = 10 * time.Second
+
+// Config of the server, set by the flags of RegisterFlags.
+type Config struct {
+	Port            string
+	PathToSources   string
+	PathToNews      string
+	PathToFeedCache string
+	CertFile        string
+	KeyFile         string
+	FetchInterval   time.Duration
+	Workers         int
+	Timeout         time.Duration
+	// Storage backend of sources and news, "file" or "db".
+	Storage      string
+	PathToDB     string
+	MaxAge       time.Duration
+	MaxCount     int
+	ArchiveAfter time.Duration
+	// AdminToken authorizes the admin endpoints as a bearer token, they are disabled without one.
+	AdminToken string
+}
+
+// RegisterStorageFlags of the storage of sources and news.
+func (c *Config) RegisterStorageFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Storage, "storage", "file", "Storage backend of sources and news: 'file' or 'db'. Default is 'file'.")
+	fs.StringVar(&c.PathToSources, "path-to-source", "server/sources.json", "Path to the file containing news sources. Default is 'server/sources.json'.")
+	fs.StringVar(&c.PathToNews, "news-folder", "server-news/", "Path to the folder where news files are stored. Default is 'server-news/'.")
+	fs.StringVar(&c.PathToDB, "db", "server/news.db", "Path to the embedded database used by the 'db' storage. Default is 'server/news.db'.")
+}
+
+// RegisterFetchFlags of fetching the sources, with the storage flags.
+func (c *Config) RegisterFetchFlags(fs *flag.FlagSet) {
+	c.RegisterStorageFlags(fs)
+	fs.StringVar(&c.PathToFeedCache, "feed-cache", "server/feed_cache.json", "Path to the file storing ETag, Last-Modified and content hash of fetched feeds. Default is 'server/feed_cache.json'.")
+	fs.IntVar(&c.Workers, "workers", 4, "Number of sources fetched concurrently. Default is 4.")
+	fs.DurationVar(&c.Timeout, "timeout", 30*time.Second, "Timeout for fetching a single source. Default is 30s.")
+}
+
+// RegisterFlags of the server, with the storage and fetch flags.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	c.RegisterFetchFlags(fs)
+	fs.StringVar(&c.Port, "port", ":8443", "Specify the port on which the server should listen. Default is :8443.")
+	fs.StringVar(&c.CertFile, "tls-cert", "/etc/tls/certs/tls.crt", "Path to the TLS certificate file.")
+	fs.StringVar(&c.KeyFile, "tls-key", "/etc/tls/certs/tls.key", "Path to the TLS key file.")
+	fs.DurationVar(&c.FetchInterval, "fetch-interval", time.Hour, "Default interval between fetches of a source, 0 disables fetching. Default is 1h.")
+	fs.DurationVar(&c.MaxAge, "retention-max-age", 0, "Default maximum age of the stored news of a source, 0 keeps them. Default is 0.")
+	fs.IntVar(&c.MaxCount, "retention-max-count", 0, "Default maximum number of stored news of a source, 0 keeps them all. Default is 0.")
+	fs.DurationVar(&c.ArchiveAfter, "archive-after", 0, "Age of the daily news files compacted into monthly gzip archives by the retention, 0 compacts none. Default is 0.")
+	fs.StringVar(&c.AdminToken, "admin-token", os.Getenv("ADMIN_TOKEN"), "Bearer token of the admin endpoints such as /admin/retention, which are disabled without one. Default is the ADMIN_TOKEN environment variable.")
+}
+
+// Storage of sources and news opened by OpenStorage.
+type Storage struct {
+	Sources managers.SourceManager
+	News    managers.NewsManager
+	close   func() error
+}
+
+// Close the storage.
+func (s Storage) Close() error {
+	if s.close == nil {
+		return nil
+	}
+	return s.close()
+}
+
+// OpenStorage of the config. The files of the file storage left corrupt by a crash
+// are quarantined first, the database of the db storage is locked until Close.
+func OpenStorage(c Config) (Storage, error) {
+	switch c.Storage {
+	case "file":
+		if err := recoverFiles(c.PathToSources, c.PathToNews, c.PathToFeedCache); err != nil {
+			return Storage{}, err
+		}
+		return Storage{
+			Sources: managers.CreateSourceFolder(c.PathToSources),
+			News:    managers.CreateNewsFolder(c.PathToNews),
+		}, nil
+	case "db":
+		db, err := managers.OpenDB(c.PathToDB)
+		if err != nil {
+			return Storage{}, fmt.Errorf("failed to open database: %w", err)
+		}
+		return Storage{
+			Sources: managers.CreateSourceDB(db),
+			News:    managers.CreateNewsDB(db),
+			close:   db.Close,
+		}, nil
+	default:
+		return Storage{}, fmt.Errorf("unknown storage %q, expected 'file' or 'db'", c.Storage)
+	}
+}
+
+// Fetch of the sources of the storage into its news.
+func (c Config) Fetch(storage Storage) service.Fetch {
+	return service.Fetch{
+		SourceManager: storage.Sources,
+		NewsManager:   storage.News,
+		FeedManager:   managers.UrlFeed{Cache: managers.CreateFeedCache(c.PathToFeedCache)},
+		Workers:       c.Workers,
+		Timeout:       c.Timeout,
+	}
+}
+
+// Retention of the news of the storage.
+func (c Config) Retention(storage Storage) service.Retention {
+	return service.Retention{
+		SourceManager: storage.Sources,
+		NewsManager:   storage.News,
+		MaxAge:        c.MaxAge,
+		MaxCount:      c.MaxCount,
+		ArchiveAfter:  c.ArchiveAfter,
+	}
+}
+
+// Serve the API over TLS until the context is done, fetching the sources in the
+// background unless the fetch interval is 0. The requests in flight are then finished.
+func Serve(ctx context.Context, c Config) error {
+	storage, err := OpenStorage(c)
+	if err != nil {
+		log.Printf("Error opening storage: %v", err)
+		return err
+	}
+	defer storage.Close()
+	storage.News, err = managers.IndexNews(storage.News)
+	if err != nil {
+		log.Printf("Error indexing news: %v", err)
+		return err
+	}
+
+	scheduler := service.NewScheduler(c.Fetch(storage), c.FetchInterval)
+	api := handlers.API{
+		Sources:   handlers.SourceHandler{SourceManager: storage.Sources},
+		News:      handlers.NewsHandler{NewsManager: storage.News, SourceManager: storage.Sources},
+		Schedule:  handlers.ScheduleHandler{Scheduler: scheduler},
+		Retention: handlers.RetentionHandler{Retention: c.Retention(storage), Token: c.AdminToken},
+	}
+	if c.FetchInterval > 0 {
+		scheduler.Start(ctx)
+		defer scheduler.Stop()
+	}
+
+	server := &http.Server{Addr: c.Port, Handler: api.Handler()}
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+		<-ctx.Done()
+		log.Println("Shutting down server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error shutting down server: %v", err)
+		}
+	}()
+
+	log.Println("Starting server on", c.Port)
+	err = server.ListenAndServeTLS(c.CertFile, c.KeyFile)
+	if !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Error starting server: %v", err)
+		return err
+	}
+	<-shutdown
+	return nil
+}
+
+// recoverFiles of the file storage left corrupt by a crash, they are quarantined.
+func recoverFiles(pathToSources, pathToNews, pathToFeedCache string) error {
+	quarantined, err := managers.Recovery{
+		PathToSources:   pathToSources,
+		PathToNews:      pathToNews,
+		PathToFeedCache: pathToFeedCache,
+	}.Run()
+	if err != nil {
+		log.Printf("Error recovering files: %v", err)
+		return err
+	}
+	if len(quarantined) > 0 {
+		log.Printf("Quarantined %d corrupt files: %v", len(quarantined), quarantined)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_RegisterFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var config Config
+	config.RegisterFlags(fs)
+
+	err := fs.Parse([]string{"--storage=db", "--workers=2", "--fetch-interval=0"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, Config{
+		Port:            ":8443",
+		PathToSources:   "server/sources.json",
+		PathToNews:      "server-news/",
+		PathToFeedCache: "server/feed_cache.json",
+		CertFile:        "/etc/tls/certs/tls.crt",
+		KeyFile:         "/etc/tls/certs/tls.key",
+		Workers:         2,
+		Timeout:         30 * time.Second,
+		Storage:         "db",
+		PathToDB:        "server/news.db",
+	}, config)
+}
+
+func TestOpenStorage(t *testing.T) {
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{name: "file", config: Config{Storage: "file", PathToSources: filepath.Join(dir, "sources.json"), PathToNews: filepath.Join(dir, "news")}},
+		{name: "corrupt file", config: Config{Storage: "file", PathToSources: corrupt, PathToNews: filepath.Join(dir, "news")}},
+		{name: "db", config: Config{Storage: "db", PathToDB: filepath.Join(dir, "news.db")}},
+		{name: "db folder", config: Config{Storage: "db", PathToDB: dir}, wantErr: "failed to open database"},
+		{name: "unknown", config: Config{Storage: "memory"}, wantErr: `unknown storage "memory"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, err := OpenStorage(tt.config)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			defer storage.Close()
+
+			_, err = storage.Sources.CreateSource("bbc", "https://bbc.com/rss")
+			assert.NoError(t, err)
+			sources, err := storage.Sources.GetSources()
+			assert.NoError(t, err)
+			assert.Len(t, sources, 1)
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package app wires the storage, the fetching and the HTTP handlers of the news
+// aggregator server together. It is shared by the server and the CLI, which serves,
+// fetches and manages the sources of the same storage with the same flags.
+package app
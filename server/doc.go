@@ -3,7 +3,11 @@
 // for managing news sources and fetching aggregated news based on query parameters.
 //
 // Starting the Server:
-// The server starts on port 8443 and exposes two main endpoints:
+// The server starts on port 8443 and exposes the endpoints:
 //   - /news: Endpoint for fetching aggregated news.
 //   - /sources: Endpoint for managing news sources.
+//   - /schedule: Endpoint showing when every source is fetched next.
+//
+// Sources are fetched in the background by a scheduler, each at its own interval
+// or the one given by -fetch-interval. The server stops gracefully on SIGINT and SIGTERM.
 package main
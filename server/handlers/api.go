@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+)
+
+// API serves all routes of the server, validating requests against the OpenAPI document.
+type API struct {
+	Sources   SourceHandler
+	News      NewsHandler
+	Schedule  ScheduleHandler
+	Retention RetentionHandler
+}
+
+// route of the API, a handler of all methods of the path when the method is empty.
+type route struct {
+	method  string
+	path    string
+	handler http.HandlerFunc
+}
+
+func (r route) pattern() string {
+	return strings.TrimSpace(r.method + " " + r.path)
+}
+
+// routes of the API, every operation has to be described in the OpenAPI document.
+// The v1 sources API has a route per operation:
+//
+//	GET    /v1/sources         lists the sources
+//	POST   /v1/sources         creates a source, 201 or 409 when the name is taken
+//	GET    /v1/sources/{name}  gets a source
+//	PUT    /v1/sources/{name}  replaces the settings of a source
+//	PATCH  /v1/sources/{name}  changes the given settings of a source
+//	DELETE /v1/sources/{name}  removes a source, 204
+//
+// Unknown names are 404. PUT, PATCH and DELETE take an optional If-Match header, 412 when it does not match.
+func (a API) routes() []route {
+	return []route{
+		{"", "/news", a.News.News},
+		{"", "/sources", a.Sources.Sources},
+		{http.MethodGet, "/v1/sources", a.Sources.List},
+		{http.MethodPost, "/v1/sources", a.Sources.Create},
+		{http.MethodGet, "/v1/sources/{name}", a.Sources.Get},
+		{http.MethodPut, "/v1/sources/{name}", a.Sources.Replace},
+		{http.MethodPatch, "/v1/sources/{name}", a.Sources.Patch},
+		{http.MethodDelete, "/v1/sources/{name}", a.Sources.Delete},
+		{"", "/schedule", a.Schedule.Schedule},
+		{"", "/admin/retention", a.Retention.Prune},
+		{http.MethodGet, "/openapi.json", OpenAPI},
+	}
+}
+
+// Handler of the routes of the API.
+func (a API) Handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, r := range a.routes() {
+		mux.HandleFunc(r.pattern(), r.handler)
+	}
+	return ValidateRequests(mux)
+}
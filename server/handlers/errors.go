@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// apiError is the JSON body of error responses.
+type apiError struct {
+	// Status code of the response and its text, like 404 and "Not Found".
+	Status int    `json:"status"`
+	Error  string `json:"error"`
+	// Message describing the error.
+	Message string `json:"message"`
+}
+
+// writeError responds with the status code and the message as a JSON error object.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	body := apiError{Status: status, Error: http.StatusText(status), Message: message}
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Error encoding error response: %v", err)
+	}
+}
+
+// writeJSON responds with the status code and the value encoded as JSON.
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
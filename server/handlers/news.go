@@ -2,22 +2,52 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
-	"news-aggregator/internal"
-	"news-aggregator/internal/initializers"
+	"net/url"
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/filters"
 	"news-aggregator/internal/sort"
+	"news-aggregator/internal/story"
 	"news-aggregator/internal/validator"
 	"news-aggregator/server/managers"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// maxLimit of news in a single page.
+const maxLimit = 1000
+
 type NewsHandler struct {
 	NewsManager   managers.NewsManager
 	SourceManager managers.SourceManager
 }
 
+// NewsPage is the envelope of a page of news in GET /news responses.
+type NewsPage struct {
+	Items      []entity.News `json:"items"`
+	Total      int           `json:"total"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+// StoryPage is the envelope of a page of news grouped into stories with group=story.
+// The total and cursor count news, not stories.
+type StoryPage struct {
+	Items      []story.Story `json:"items"`
+	Total      int           `json:"total"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
 // News handler for GET requests to retrieve aggregated news based
-// on specified query parameters.
+// on specified query parameters. The limit and cursor parameters page the news,
+// the response is a NewsPage with RFC 8288 Link headers to the next and first pages.
+// With format=array the news are returned as a plain JSON array as before,
+// the total is then given by the X-Total-Count header. With group=story the news
+// of the page are grouped into stories of near-duplicate news from several sources.
+// The category and author parameters keep news of any of the comma-separated values.
 func (newsHandler NewsHandler) News(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		log.Printf("Invalid request method: %s", r.Method)
@@ -31,6 +61,11 @@ func (newsHandler NewsHandler) News(w http.ResponseWriter, r *http.Request) {
 	dateEnd := r.URL.Query().Get("date-end")
 	sortOrder := r.URL.Query().Get("sort-order")
 	sortBy := r.URL.Query().Get("sort-by")
+	cursor := r.URL.Query().Get("cursor")
+	format := r.URL.Query().Get("format")
+	group := r.URL.Query().Get("group")
+	category := r.URL.Query().Get("category")
+	author := r.URL.Query().Get("author")
 
 	log.Printf("Received GET request with parameters - Sources: %s, Keywords: %s, DateStart: %s, DateEnd: %s, SortOrder: %s, SortBy: %s",
 		sources, keywords, dateStart, dateEnd, sortOrder, sortBy)
@@ -46,12 +81,6 @@ func (newsHandler NewsHandler) News(w http.ResponseWriter, r *http.Request) {
 	for _, source := range s {
 		availableSources = append(availableSources, string(source.Name))
 	}
-	resources, err := newsHandler.NewsManager.GetNewsSourceFilePath(availableSources)
-	if err != nil {
-		log.Printf("Error getting news source file paths: %v", err)
-		http.Error(w, "Error retrieving news source file paths", http.StatusBadRequest)
-		return
-	}
 
 	sortOptions := sort.Options{
 		Criterion: sortBy,
@@ -60,6 +89,7 @@ func (newsHandler NewsHandler) News(w http.ResponseWriter, r *http.Request) {
 	config := validator.Config{
 		Sources:          sources,
 		AvailableSources: availableSources,
+		Keywords:         keywords,
 		DateStart:        dateStart,
 		DateEnd:          dateEnd,
 		SortOptions:      sortOptions,
@@ -71,24 +101,130 @@ func (newsHandler NewsHandler) News(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	limit, err := parseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format != "" && format != "array" {
+		http.Error(w, "invalid format. Please use `array` or leave it empty", http.StatusBadRequest)
+		return
+	}
+	if group != "" && group != "story" {
+		http.Error(w, "invalid group. Please use `story` or leave it empty", http.StatusBadRequest)
+		return
+	}
+	query := newsQuery(sources, keywords, dateStart, dateEnd, sortOptions, availableSources)
+	query.Limit = limit
+	query.Cursor = cursor
+	if categories := splitList(category); len(categories) > 0 {
+		query.Category = &filters.Category{Categories: categories}
+	}
+	if authors := splitList(author); len(authors) > 0 {
+		query.Author = &filters.Author{Authors: authors}
+	}
 
-	a := internal.NewAggregator(
-		resources,
-		sources,
-		initializers.InitializeFilters(&keywords, &dateStart, &dateEnd),
-		sortOptions)
-	news, err := a.Aggregate()
+	page, err := newsHandler.NewsManager.Query(r.Context(), query)
+	if errors.Is(err, managers.ErrInvalidCursor) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
-		log.Printf("Error aggregating news: %v", err)
+		log.Printf("Error querying news: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	setLinks(w, r, cursor, page.NextCursor)
 	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(news)
+	var body interface{} = NewsPage{Items: page.News, Total: page.Total, NextCursor: page.NextCursor}
+	if group == "story" {
+		stories := story.Options{}.Group(page.News)
+		if stories == nil {
+			stories = make([]story.Story, 0)
+		}
+		body = StoryPage{Items: stories, Total: page.Total, NextCursor: page.NextCursor}
+		if format == "array" {
+			body = stories
+		}
+	} else if format == "array" {
+		body = page.News
+	}
+	if format == "array" {
+		w.Header().Set("X-Total-Count", strconv.Itoa(page.Total))
+	}
+	err = json.NewEncoder(w).Encode(body)
 	if err != nil {
 		log.Printf("Error encoding response: %v", err)
 		http.Error(w, "Error encoding response", http.StatusInternalServerError)
 		return
 	}
 }
+
+// newsQuery for the validated request parameters.
+// Requested sources are matched to the available ones case-insensitively.
+func newsQuery(sources, keywords, dateStart, dateEnd string, sortOptions sort.Options, availableSources []string) managers.NewsQuery {
+	query := managers.NewsQuery{Sort: sortOptions}
+	for _, name := range strings.Split(sources, ",") {
+		name = strings.TrimSpace(name)
+		for _, available := range availableSources {
+			if strings.EqualFold(name, available) {
+				query.Sources = append(query.Sources, available)
+			}
+		}
+	}
+	if keywords != "" {
+		query.Keywords, _ = filters.ParseQuery(keywords)
+	}
+	if dateStart != "" {
+		query.DateStart, _ = time.Parse(validator.DateFormat, dateStart)
+	}
+	if dateEnd != "" {
+		query.DateEnd, _ = time.Parse(validator.DateFormat, dateEnd)
+	}
+	return query
+}
+
+// splitList of comma-separated values, without empty ones.
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseLimit of news in a page, zero when the parameter is missing.
+func parseLimit(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxLimit {
+		return 0, fmt.Errorf("invalid limit. Please use a number from 1 to %d", maxLimit)
+	}
+	return limit, nil
+}
+
+// setLinks to the next page and, within the pages, to the first one as RFC 8288 Link headers.
+func setLinks(w http.ResponseWriter, r *http.Request, cursor, nextCursor string) {
+	if nextCursor != "" {
+		w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="next"`, pageURL(r, nextCursor)))
+	}
+	if cursor != "" {
+		w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="first"`, pageURL(r, "")))
+	}
+}
+
+// pageURL of the request with the given cursor.
+func pageURL(r *http.Request, cursor string) string {
+	params := r.URL.Query()
+	params.Del("cursor")
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+	u := url.URL{Path: r.URL.Path, RawQuery: params.Encode()}
+	return u.String()
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/golang/mock/gomock"
@@ -8,7 +9,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"news-aggregator/internal/entity"
+	"news-aggregator/internal/filters"
+	"news-aggregator/server/managers"
 	"news-aggregator/server/managers/mock_managers"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -34,12 +39,21 @@ func TestNewsHandler(t *testing.T) {
 	}
 	mockSourceManager.EXPECT().GetSources().Return(mockSources, nil)
 
-	mockNewsManager.EXPECT().GetNewsSourceFilePath([]string{"bbc_news"}).
-		Return(map[string][]string{
-			"bbc_news": {"../../internal/testdata/bbc_news/ready_news.json"},
-		}, nil)
-
-	req, err := http.NewRequest("GET", "/news?sources=bbc_news&keywords=England", nil)
+	keywords, err := filters.ParseQuery("England")
+	assert.NoError(t, err)
+	// The test news are copied, so the lock file of the source is left in the temporary folder.
+	newsFolder := t.TempDir()
+	jsonData, err := os.ReadFile("../../internal/testdata/bbc_news/ready_news.json")
+	assert.NoError(t, err)
+	assert.NoError(t, os.MkdirAll(filepath.Join(newsFolder, "bbc_news"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(newsFolder, "bbc_news", "ready_news.json"), jsonData, 0644))
+	query := managers.NewsQuery{Sources: []string{"bbc_news"}, Keywords: keywords}
+	mockNewsManager.EXPECT().Query(gomock.Any(), query).
+		DoAndReturn(func(ctx context.Context, query managers.NewsQuery) (managers.NewsPage, error) {
+			return managers.CreateNewsFolder(newsFolder).Query(ctx, query)
+		})
+
+	req, err := http.NewRequest("GET", "/news?sources=BBC_news&keywords=England", nil)
 	assert.NoError(t, err, "Expected no error creating request")
 
 	rr := httptest.NewRecorder()
@@ -58,10 +72,155 @@ func TestNewsHandler(t *testing.T) {
 			Date:        time.Date(2024, 6, 30, 19, 31, 26, 0, time.UTC),
 		},
 	}
-	var actual []entity.News
+	var actual NewsPage
 	err = json.NewDecoder(rr.Body).Decode(&actual)
 	assert.NoError(t, err, "Expected no error decoding response body")
-	assert.ElementsMatch(t, expected, actual, "Expected response body to match")
+	assert.ElementsMatch(t, expected, actual.Items, "Expected response body to match")
+	assert.Equal(t, 1, actual.Total, "Expected total to match")
+	assert.Empty(t, rr.Header().Get("Link"), "Expected no Link header for a single page")
+}
+
+func TestNewsHandlerPages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, mockNewsManager, mockSourceManager := setupNewsHandlerTest(ctrl)
+
+	mockSourceManager.EXPECT().GetSources().Return([]entity.Source{{Name: "bbc_news"}}, nil)
+	query := managers.NewsQuery{Sources: []string{"bbc_news"}, Limit: 1, Cursor: "abc"}
+	mockNewsManager.EXPECT().Query(gomock.Any(), query).
+		Return(managers.NewsPage{News: []entity.News{{Title: "Title"}}, Total: 3, NextCursor: "def"}, nil)
+
+	req, err := http.NewRequest("GET", "/news?sources=bbc_news&limit=1&cursor=abc", nil)
+	assert.NoError(t, err, "Expected no error creating request")
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(handler.News).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "Expected status OK")
+	assert.JSONEq(t, `{"items":[{"Title":"Title","Description":"","Link":"","Date":"0001-01-01T00:00:00Z","Source":""}],"total":3,"nextCursor":"def"}`,
+		rr.Body.String())
+	assert.Equal(t, []string{
+		`</news?cursor=def&limit=1&sources=bbc_news>; rel="next"`,
+		`</news?limit=1&sources=bbc_news>; rel="first"`,
+	}, rr.Header().Values("Link"))
+}
+
+func TestNewsHandlerArrayFormat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, mockNewsManager, mockSourceManager := setupNewsHandlerTest(ctrl)
+
+	mockSourceManager.EXPECT().GetSources().Return([]entity.Source{{Name: "bbc_news"}}, nil)
+	mockNewsManager.EXPECT().Query(gomock.Any(), managers.NewsQuery{Sources: []string{"bbc_news"}, Limit: 1}).
+		Return(managers.NewsPage{News: []entity.News{{Title: "Title"}}, Total: 3, NextCursor: "def"}, nil)
+
+	req, err := http.NewRequest("GET", "/news?sources=bbc_news&limit=1&format=array", nil)
+	assert.NoError(t, err, "Expected no error creating request")
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(handler.News).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "Expected status OK")
+	var actual []entity.News
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&actual), "Expected a JSON array")
+	assert.Equal(t, []entity.News{{Title: "Title"}}, actual)
+	assert.Equal(t, "3", rr.Header().Get("X-Total-Count"))
+	assert.Equal(t, `</news?cursor=def&format=array&limit=1&sources=bbc_news>; rel="next"`, rr.Header().Get("Link"))
+}
+
+func TestNewsHandlerCategoryAndAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, mockNewsManager, mockSourceManager := setupNewsHandlerTest(ctrl)
+
+	mockSourceManager.EXPECT().GetSources().Return([]entity.Source{{Name: "bbc_news"}}, nil)
+	query := managers.NewsQuery{
+		Sources:  []string{"bbc_news"},
+		Category: &filters.Category{Categories: []string{"Sport", "Europe"}},
+		Author:   &filters.Author{Authors: []string{"Jane Doe"}},
+	}
+	news := []entity.News{{Title: "Title", Categories: []string{"Sport"}, Authors: []string{"Jane Doe"}, Image: "https://bbc.com/1.jpg"}}
+	mockNewsManager.EXPECT().Query(gomock.Any(), query).Return(managers.NewsPage{News: news, Total: 1}, nil)
+
+	req, err := http.NewRequest("GET", "/news?sources=bbc_news&category=Sport,+Europe&author=Jane+Doe", nil)
+	assert.NoError(t, err, "Expected no error creating request")
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(handler.News).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "Expected status OK")
+	var actual NewsPage
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&actual), "Expected no error decoding response body")
+	assert.Equal(t, news, actual.Items)
+}
+
+func TestNewsHandlerGroupStory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, mockNewsManager, mockSourceManager := setupNewsHandlerTest(ctrl)
+
+	mockSourceManager.EXPECT().GetSources().Return([]entity.Source{{Name: "bbc_news"}, {Name: "nbc_news"}}, nil)
+	news := []entity.News{
+		{Title: "England beat Slovakia after extra time", Description: "Harry Kane scores the winner.", Link: "bbc", Source: "bbc_news"},
+		{Title: "Markets rally", Link: "other", Source: "bbc_news"},
+		{Title: "England beat Slovakia after extra time", Description: "Harry Kane scores the winner!", Link: "nbc", Source: "nbc_news"},
+	}
+	mockNewsManager.EXPECT().Query(gomock.Any(), managers.NewsQuery{Sources: []string{"bbc_news", "nbc_news"}}).
+		Return(managers.NewsPage{News: news, Total: 3}, nil)
+
+	req, err := http.NewRequest("GET", "/news?sources=bbc_news,nbc_news&group=story", nil)
+	assert.NoError(t, err, "Expected no error creating request")
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(handler.News).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "Expected status OK")
+	var actual StoryPage
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&actual), "Expected no error decoding response body")
+	assert.Equal(t, 3, actual.Total, "Expected total to count news")
+	if assert.Len(t, actual.Items, 2, "Expected two stories") {
+		assert.Equal(t, []string{"bbc_news", "nbc_news"}, actual.Items[0].Sources)
+		assert.Equal(t, []entity.News{news[0], news[2]}, actual.Items[0].News)
+		assert.Equal(t, []entity.News{news[1]}, actual.Items[1].News)
+	}
+}
+
+func TestNewsHandlerInvalidPageParameters(t *testing.T) {
+	tests := []struct {
+		name  string
+		url   string
+		query bool
+	}{
+		{name: "limit not a number", url: "/news?sources=bbc_news&limit=ten"},
+		{name: "limit too large", url: "/news?sources=bbc_news&limit=1001"},
+		{name: "unknown format", url: "/news?sources=bbc_news&format=xml"},
+		{name: "unknown group", url: "/news?sources=bbc_news&group=topic"},
+		{name: "invalid cursor", url: "/news?sources=bbc_news&cursor=abc", query: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler, mockNewsManager, mockSourceManager := setupNewsHandlerTest(ctrl)
+			mockSourceManager.EXPECT().GetSources().Return([]entity.Source{{Name: "bbc_news"}}, nil)
+			if tt.query {
+				mockNewsManager.EXPECT().Query(gomock.Any(), gomock.Any()).Return(managers.NewsPage{}, managers.ErrInvalidCursor)
+			}
+
+			req, err := http.NewRequest("GET", tt.url, nil)
+			assert.NoError(t, err, "Expected no error creating request")
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(handler.News).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, "Expected status Bad Request")
+		})
+	}
 }
 
 func TestNewsHandlerInvalidSource(t *testing.T) {
@@ -73,8 +232,7 @@ func TestNewsHandlerInvalidSource(t *testing.T) {
 	var mockSources []entity.Source
 	mockSourceManager.EXPECT().GetSources().Return(mockSources, nil)
 
-	mockNewsManager.EXPECT().GetNewsSourceFilePath(gomock.Any()).
-		Return(map[string][]string{}, errors.New("error getting news source file paths"))
+	mockNewsManager.EXPECT().Query(gomock.Any(), gomock.Any()).Times(0)
 
 	req, err := http.NewRequest("GET", "/news?sources=invalid_source", nil)
 	assert.NoError(t, err, "Expected no error creating request")
@@ -95,7 +253,7 @@ func TestNewsHandlerErrorGettingSources(t *testing.T) {
 
 	mockSourceManager.EXPECT().GetSources().Return(nil, errors.New("error getting sources"))
 
-	mockNewsManager.EXPECT().GetNewsSourceFilePath(gomock.Any()).Return(nil, nil).Times(0)
+	mockNewsManager.EXPECT().Query(gomock.Any(), gomock.Any()).Times(0)
 
 	req, err := http.NewRequest("GET", "/news?sources=invalid_source", nil)
 	assert.NoError(t, err, "Expected no error creating request")
@@ -108,7 +266,7 @@ func TestNewsHandlerErrorGettingSources(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code, "Expected status Internal Server Error")
 }
 
-func TestNewsHandlerErrorGettingNewsSourceFilePath(t *testing.T) {
+func TestNewsHandlerErrorGettingNews(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -118,8 +276,8 @@ func TestNewsHandlerErrorGettingNewsSourceFilePath(t *testing.T) {
 		{Name: "bbc_news"},
 	}
 	mockSourceManager.EXPECT().GetSources().Return(mockSources, nil)
-	mockNewsManager.EXPECT().GetNewsSourceFilePath([]string{"bbc_news"}).
-		Return(map[string][]string{}, errors.New("error getting news source file paths"))
+	mockNewsManager.EXPECT().Query(gomock.Any(), gomock.Any()).
+		Return(managers.NewsPage{}, errors.New("error getting news"))
 
 	req, err := http.NewRequest("GET", "/news?sources=bbc_news", nil)
 	assert.NoError(t, err, "Expected no error creating request")
@@ -129,7 +287,7 @@ func TestNewsHandlerErrorGettingNewsSourceFilePath(t *testing.T) {
 
 	httpHandler.ServeHTTP(rr, req)
 
-	assert.Equal(t, http.StatusBadRequest, rr.Code, "Expected status Bad Request")
+	assert.Equal(t, http.StatusInternalServerError, rr.Code, "Expected status Internal Server Error")
 }
 
 func TestNewsHandlerInvalidMethod(t *testing.T) {
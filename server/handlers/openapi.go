@@ -0,0 +1,475 @@
+package handlers
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// openAPIDocument describes the API of the server, it is served at /openapi.json.
+//
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// apiSpec is the part of the OpenAPI document requests are validated against.
+var apiSpec = mustLoadSpec(openAPIDocument)
+
+// maxValidatedBody is the size limit of the request bodies, larger bodies are rejected.
+const maxValidatedBody = 1 << 20
+
+// OpenAPI handles GET requests for the OpenAPI document of the server.
+func OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(openAPIDocument); err != nil {
+		log.Printf("Error writing OpenAPI document: %v", err)
+	}
+}
+
+// spec holds the paths of the OpenAPI document with their operations by lower case method,
+// and the components their parameters and schemas refer to.
+type spec struct {
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components struct {
+		Parameters map[string]*parameter `json:"parameters"`
+		Schemas    map[string]*schema    `json:"schemas"`
+	} `json:"components"`
+}
+
+type operation struct {
+	OperationID string       `json:"operationId"`
+	Parameters  []*parameter `json:"parameters"`
+	RequestBody *struct {
+		Required bool `json:"required"`
+		Content  map[string]struct {
+			Schema *schema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+type parameter struct {
+	Ref      string  `json:"$ref"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *schema `json:"schema"`
+}
+
+// schema is the subset of the OpenAPI schema objects used by the document.
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Enum                 []any              `json:"enum"`
+	Pattern              string             `json:"pattern"`
+	MinLength            *int               `json:"minLength"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	Nullable             bool               `json:"nullable"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	AllOf                []*schema          `json:"allOf"`
+	OneOf                []*schema          `json:"oneOf"`
+
+	pattern *regexp.Regexp
+}
+
+// mustLoadSpec decodes the document, resolving the references of the parameters
+// and compiling the patterns of the schemas.
+func mustLoadSpec(document []byte) *spec {
+	var s spec
+	if err := json.Unmarshal(document, &s); err != nil {
+		panic(fmt.Sprintf("invalid OpenAPI document: %v", err))
+	}
+	var schemas []*schema
+	for _, item := range s.Components.Schemas {
+		schemas = append(schemas, item)
+	}
+	for _, item := range s.Components.Parameters {
+		schemas = append(schemas, item.Schema)
+	}
+	for path, item := range s.Paths {
+		for method, op := range item {
+			for i, p := range op.Parameters {
+				if p.Ref != "" {
+					resolved, ok := s.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+					if !ok {
+						panic(fmt.Sprintf("unknown parameter %s of %s %s", p.Ref, method, path))
+					}
+					op.Parameters[i] = resolved
+					continue
+				}
+				schemas = append(schemas, p.Schema)
+			}
+			if op.RequestBody != nil {
+				for _, content := range op.RequestBody.Content {
+					schemas = append(schemas, content.Schema)
+				}
+			}
+		}
+	}
+	for _, item := range schemas {
+		if err := s.compile(item); err != nil {
+			panic(fmt.Sprintf("invalid OpenAPI document: %v", err))
+		}
+	}
+	return &s
+}
+
+// compile the patterns of the schema and the schemas within it, checking their references.
+func (s *spec) compile(sc *schema) error {
+	if sc == nil {
+		return nil
+	}
+	if sc.Ref != "" {
+		if s.resolve(sc) == nil {
+			return fmt.Errorf("unknown schema %s", sc.Ref)
+		}
+		return nil
+	}
+	if sc.Pattern != "" {
+		pattern, err := regexp.Compile(sc.Pattern)
+		if err != nil {
+			return err
+		}
+		sc.pattern = pattern
+	}
+	nested := []*schema{sc.Items}
+	nested = append(nested, sc.AllOf...)
+	nested = append(nested, sc.OneOf...)
+	for _, property := range sc.Properties {
+		nested = append(nested, property)
+	}
+	for _, item := range nested {
+		if err := s.compile(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve the reference of the schema to a component, nil for an unknown one.
+func (s *spec) resolve(sc *schema) *schema {
+	if sc.Ref == "" {
+		return sc
+	}
+	return s.Components.Schemas[strings.TrimPrefix(sc.Ref, "#/components/schemas/")]
+}
+
+// find the operation of the request with the values of its path parameters,
+// nil when the document does not describe it.
+func (s *spec) find(method, path string) (*operation, map[string]string) {
+	segments := strings.Split(path, "/")
+	var found *operation
+	var foundValues map[string]string
+	for template, item := range s.Paths {
+		op, ok := item[strings.ToLower(method)]
+		if !ok {
+			continue
+		}
+		values, ok := matchPath(strings.Split(template, "/"), segments)
+		if ok && (found == nil || len(values) < len(foundValues)) {
+			found, foundValues = op, values
+		}
+	}
+	return found, foundValues
+}
+
+// matchPath segments to the segments of a path template with {parameters}.
+func matchPath(template, segments []string) (map[string]string, bool) {
+	if len(template) != len(segments) {
+		return nil, false
+	}
+	values := make(map[string]string)
+	for i, part := range template {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			value, err := url.PathUnescape(segments[i])
+			if err != nil || value == "" {
+				return nil, false
+			}
+			values[part[1:len(part)-1]] = value
+		} else if part != segments[i] {
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+// ValidateRequests against the OpenAPI document before passing them to the next handler:
+// the parameters and the JSON body of the operation have to match their schemas,
+// otherwise the request is rejected with a JSON error object. Requests of paths and
+// methods the document does not describe are left to the next handler.
+func ValidateRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op, pathValues := apiSpec.find(r.Method, r.URL.EscapedPath())
+		if op == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err := apiSpec.validateParameters(op, r, pathValues); err != nil {
+			log.Printf("Invalid request %s %s: %v", r.Method, r.URL.Path, err)
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if status, err := apiSpec.validateBody(op, w, r); err != nil {
+			log.Printf("Invalid request body of %s %s: %v", r.Method, r.URL.Path, err)
+			writeError(w, status, err.Error())
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validateParameters of the operation given in the path, query and headers of the request.
+func (s *spec) validateParameters(op *operation, r *http.Request, pathValues map[string]string) error {
+	query := r.URL.Query()
+	for _, p := range op.Parameters {
+		var value string
+		var given bool
+		switch p.In {
+		case "path":
+			value, given = pathValues[p.Name]
+		case "query":
+			given = query.Has(p.Name)
+			value = query.Get(p.Name)
+		case "header":
+			value = r.Header.Get(p.Name)
+			given = value != ""
+		default:
+			continue
+		}
+		if !given {
+			if p.Required {
+				return fmt.Errorf("missing %s parameter %q", p.In, p.Name)
+			}
+			continue
+		}
+		parsed, err := parseParameter(value, s.resolve(p.Schema))
+		if err == nil {
+			err = s.validate(parsed, p.Schema, "")
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s parameter %q: %w", p.In, p.Name, err)
+		}
+	}
+	return nil
+}
+
+// numberTypes of the schemas named with their article in error messages.
+var numberTypes = map[string]string{"integer": "an integer", "number": "a number"}
+
+// parseParameter value into the type of its schema.
+func parseParameter(value string, sc *schema) (any, error) {
+	switch sc.Type {
+	case "integer", "number":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.New("must be " + numberTypes[sc.Type])
+		}
+		return number, nil
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("must be true or false")
+		}
+		return b, nil
+	default:
+		return value, nil
+	}
+}
+
+// validateBody of the request against the schema of its media type. The body is
+// read and replaced for the next handler. It returns the status code of an invalid body.
+func (s *spec) validateBody(op *operation, w http.ResponseWriter, r *http.Request) (int, error) {
+	if op.RequestBody == nil || r.Body == nil {
+		return 0, nil
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxValidatedBody))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return http.StatusRequestEntityTooLarge, fmt.Errorf("body larger than %d bytes", tooLarge.Limit)
+		}
+		return http.StatusBadRequest, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if len(bytes.TrimSpace(data)) == 0 {
+		if op.RequestBody.Required {
+			return http.StatusBadRequest, errors.New("missing JSON body")
+		}
+		return 0, nil
+	}
+	mediaType := "application/json"
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		if mediaType, _, err = mime.ParseMediaType(contentType); err != nil {
+			return http.StatusUnsupportedMediaType, fmt.Errorf("invalid Content-Type %q", contentType)
+		}
+	}
+	content, ok := op.RequestBody.Content[mediaType]
+	if !ok {
+		return http.StatusUnsupportedMediaType, fmt.Errorf("unsupported Content-Type %q", mediaType)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if err := s.validate(value, content.Schema, ""); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid body: %w", err)
+	}
+	return 0, nil
+}
+
+// validate the decoded JSON value against the schema, the path names the value in errors.
+func (s *spec) validate(value any, sc *schema, path string) error {
+	if sc == nil {
+		return nil
+	}
+	if value == nil && sc.Nullable {
+		return nil
+	}
+	sc = s.resolve(sc)
+	for _, item := range sc.AllOf {
+		if err := s.validate(value, item, path); err != nil {
+			return err
+		}
+	}
+	if len(sc.OneOf) > 0 {
+		matched := 0
+		for _, item := range sc.OneOf {
+			if s.validate(value, item, path) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return fieldError(path, "must match exactly one schema")
+		}
+	}
+	if len(sc.Enum) > 0 && (!isScalar(value) || !slices.Contains(sc.Enum, value)) {
+		return fieldError(path, fmt.Sprintf("must be one of %v", sc.Enum))
+	}
+	switch sc.Type {
+	case "":
+		return nil
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fieldError(path, "must be a string")
+		}
+		return validateString(str, sc, path)
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok || (sc.Type == "integer" && number != math.Trunc(number)) {
+			return fieldError(path, "must be "+numberTypes[sc.Type])
+		}
+		if sc.Minimum != nil && number < *sc.Minimum {
+			return fieldError(path, fmt.Sprintf("must be at least %v", *sc.Minimum))
+		}
+		if sc.Maximum != nil && number > *sc.Maximum {
+			return fieldError(path, fmt.Sprintf("must be at most %v", *sc.Maximum))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fieldError(path, "must be true or false")
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fieldError(path, "must be an array")
+		}
+		for i, item := range items {
+			if err := s.validate(item, sc.Items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return fieldError(path, "must be an object")
+		}
+		for _, name := range sc.Required {
+			if _, ok := object[name]; !ok {
+				return fieldError(join(path, name), "is required")
+			}
+		}
+		for name, property := range object {
+			schema, ok := sc.Properties[name]
+			if !ok {
+				if sc.AdditionalProperties != nil && !*sc.AdditionalProperties {
+					return fieldError(join(path, name), "is not a known field")
+				}
+				continue
+			}
+			if err := s.validate(property, schema, join(path, name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateString against the length, pattern and format of the schema.
+func validateString(value string, sc *schema, path string) error {
+	if sc.MinLength != nil && len([]rune(value)) < *sc.MinLength {
+		return fieldError(path, fmt.Sprintf("must have at least %d characters", *sc.MinLength))
+	}
+	if sc.pattern != nil && !sc.pattern.MatchString(value) {
+		return fieldError(path, fmt.Sprintf("must match %s", sc.Pattern))
+	}
+	switch sc.Format {
+	case "date":
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return fieldError(path, "must be a date like 2024-05-01")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fieldError(path, "must be an RFC 3339 date and time")
+		}
+	case "duration":
+		if _, err := time.ParseDuration(value); err != nil {
+			return fieldError(path, "must be a duration like 15m or 720h")
+		}
+	case "uri":
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+			return fieldError(path, "must be an absolute URL")
+		}
+	}
+	return nil
+}
+
+// isScalar reports if the JSON value is comparable to the values of an enum.
+func isScalar(value any) bool {
+	switch value.(type) {
+	case string, float64, bool, nil:
+		return true
+	default:
+		return false
+	}
+}
+
+func fieldError(path, message string) error {
+	if path == "" {
+		return errors.New(message)
+	}
+	return fmt.Errorf("%s %s", path, message)
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPI(t *testing.T) {
+	rr := httptest.NewRecorder()
+	API{}.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/openapi.json", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	var document map[string]any
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &document))
+	assert.Equal(t, "3.0.3", document["openapi"])
+}
+
+func TestAPI_RoutesMatchDocument(t *testing.T) {
+	routes := API{}.routes()
+	for _, r := range routes {
+		item, ok := apiSpec.Paths[r.path]
+		if !assert.True(t, ok, "Expected the document to describe %s", r.path) || r.method == "" {
+			continue
+		}
+		assert.Contains(t, item, strings.ToLower(r.method), "Expected the document to describe %s", r.pattern())
+	}
+
+	operationIDs := make(map[string]bool)
+	for path, item := range apiSpec.Paths {
+		for method, op := range item {
+			assert.False(t, operationIDs[op.OperationID], "Expected a unique operation ID %s", op.OperationID)
+			operationIDs[op.OperationID] = true
+			routed := false
+			for _, r := range routes {
+				routed = routed || (r.path == path && (r.method == "" || strings.ToLower(r.method) == method))
+			}
+			assert.True(t, routed, "Expected a route of %s %s", method, path)
+		}
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		code        int
+		message     string
+	}{
+		{name: "valid query", method: "GET", target: "/news?limit=10&date-start=2024-05-01&sort-by=Date&sort-order=desc&format=array", code: http.StatusOK},
+		{name: "limit below minimum", method: "GET", target: "/news?limit=0", code: http.StatusBadRequest, message: `invalid query parameter "limit": must be at least 1`},
+		{name: "limit not an integer", method: "GET", target: "/news?limit=ten", code: http.StatusBadRequest, message: `invalid query parameter "limit": must be an integer`},
+		{name: "invalid date", method: "GET", target: "/news?date-start=2024-13-01", code: http.StatusBadRequest, message: `invalid query parameter "date-start": must be a date like 2024-05-01`},
+		{name: "invalid enum", method: "GET", target: "/news?format=csv", code: http.StatusBadRequest, message: `invalid query parameter "format": must be one of [array]`},
+		{name: "invalid boolean", method: "POST", target: "/admin/retention?dry-run=maybe", code: http.StatusBadRequest, message: `invalid query parameter "dry-run": must be true or false`},
+		{name: "missing required parameter", method: "POST", target: "/sources?url=https://bbc.com", code: http.StatusBadRequest, message: `missing query parameter "name"`},
+		{name: "invalid duration", method: "PUT", target: "/sources?name=bbc&interval=often", code: http.StatusBadRequest, message: `invalid query parameter "interval": must be a duration like 15m or 720h`},
+		{name: "valid body", method: "POST", target: "/v1/sources", body: `{"name":"bbc","url":"https://bbc.com/rss","retention":{"MaxCount":10}}`, code: http.StatusOK},
+		{name: "missing body", method: "POST", target: "/v1/sources", code: http.StatusBadRequest, message: "missing JSON body"},
+		{name: "missing field", method: "POST", target: "/v1/sources", body: `{"name":"bbc"}`, code: http.StatusBadRequest, message: "invalid body: url is required"},
+		{name: "unknown field", method: "POST", target: "/v1/sources", body: `{"name":"bbc","url":"https://bbc.com","link":"x"}`, code: http.StatusBadRequest, message: "invalid body: link is not a known field"},
+		{name: "nested field", method: "POST", target: "/v1/sources", body: `{"name":"bbc","url":"https://bbc.com","retention":{"MaxCount":-1}}`, code: http.StatusBadRequest, message: "invalid body: retention.MaxCount must be at least 0"},
+		{name: "invalid JSON", method: "POST", target: "/v1/sources", body: `{"name":`, code: http.StatusBadRequest},
+		{name: "unsupported media type", method: "POST", target: "/v1/sources", contentType: "text/plain", body: `name=bbc`, code: http.StatusUnsupportedMediaType},
+		{name: "null resets", method: "PATCH", target: "/v1/sources/bbc", contentType: "application/merge-patch+json", body: `{"language":null,"scrape":null}`, code: http.StatusOK},
+		{name: "null url", method: "PATCH", target: "/v1/sources/bbc", body: `{"url":null}`, code: http.StatusBadRequest, message: "invalid body: url must be a string"},
+		{name: "invalid path parameter", method: "GET", target: "/v1/sources/bbc%20news", code: http.StatusBadRequest},
+		{name: "optional body", method: "POST", target: "/sources?name=bbc&url=https://bbc.com", code: http.StatusOK},
+		{name: "undescribed path", method: "GET", target: "/unknown?limit=0", code: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			handler := ValidateRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				received = string(body)
+			}))
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.code, rr.Code, rr.Body.String())
+			if tt.code == http.StatusOK {
+				assert.Equal(t, tt.body, received, "Expected the body to be passed on")
+				return
+			}
+			var apiErr apiError
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &apiErr))
+			assert.Equal(t, tt.code, apiErr.Status)
+			if tt.message != "" {
+				assert.Equal(t, tt.message, apiErr.Message)
+			}
+		})
+	}
+}
+
+// TestAPI_HandlersMatchDocument checks the operations of the document against the source of their handlers:
+// the parameters the handler reads have to be the documented ones and the statuses it responds with have
+// to be documented responses. Documented responses have to be given by the handler or by ValidateRequests.
+func TestAPI_HandlersMatchDocument(t *testing.T) {
+	var document struct {
+		Paths map[string]map[string]struct {
+			Responses map[string]any `json:"responses"`
+		} `json:"paths"`
+	}
+	assert.NoError(t, json.Unmarshal(openAPIDocument, &document))
+	source := loadHandlerSource(t)
+	validator := source.facts("ValidateRequests", nil)
+
+	for _, r := range (API{}).routes() {
+		name := handlerName(r.handler)
+		for method, op := range apiSpec.Paths[r.path] {
+			method = strings.ToUpper(method)
+			if r.method != "" && r.method != method {
+				continue
+			}
+			t.Run(method+" "+r.path, func(t *testing.T) {
+				facts := source.facts(name, &method)
+				var documented []string
+				for _, p := range op.Parameters {
+					documented = append(documented, p.In+" "+p.Name)
+				}
+				assert.ElementsMatch(t, documented, facts.params, "Expected the handler %s to read the documented parameters", name)
+
+				var responses []int
+				for code := range document.Paths[r.path][strings.ToLower(method)].Responses {
+					status, err := strconv.Atoi(code)
+					assert.NoError(t, err)
+					responses = append(responses, status)
+				}
+				var given []int
+				for status := range facts.statuses {
+					// Methods the document does not describe are not allowed.
+					if status != http.StatusMethodNotAllowed {
+						given = append(given, status)
+					}
+				}
+				assert.Subset(t, responses, given, "Expected documented responses of the statuses the handler %s responds with", name)
+				possible := append([]int{http.StatusOK}, given...)
+				possible = append(possible, facts.mapped...)
+				for status := range validator.statuses {
+					possible = append(possible, status)
+				}
+				assert.Subset(t, possible, responses, "Expected the handler %s to respond with the documented statuses", name)
+			})
+		}
+	}
+}
+
+// handlerSource holds the functions of the package by name, methods by their type and name like "API.Handler".
+type handlerSource map[string]*ast.FuncDecl
+
+// handlerFacts are the parameters a function reads, like "query limit", and the statuses it responds with,
+// including those of the functions and methods of the package it calls. The statuses of the errors of
+// the managers are mapped, depending on the error the handler may respond with them.
+type handlerFacts struct {
+	params   []string
+	statuses map[int]bool
+	mapped   []int
+}
+
+// errorStatuses of the functions mapping errors of the managers to statuses, only their fallback
+// status is certain for every handler calling them.
+var errorStatuses = map[string]int{"sourceErrorStatus": http.StatusInternalServerError}
+
+// loadHandlerSource of the non-test files of the package.
+func loadHandlerSource(t *testing.T) handlerSource {
+	files, err := filepath.Glob("*.go")
+	assert.NoError(t, err)
+	source := make(handlerSource)
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok {
+				source[funcKey(fn)] = fn
+			}
+		}
+	}
+	return source
+}
+
+// handlerName of a route handler, a function or a method value of the package.
+func handlerName(handler http.HandlerFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	name = strings.TrimSuffix(name[strings.LastIndex(name, "/")+1:], "-fm")
+	return strings.TrimPrefix(name, "handlers.")
+}
+
+// facts of the function with the name. When a method is given, only the case of the method
+// is followed in a switch on the request method.
+func (s handlerSource) facts(name string, method *string) handlerFacts {
+	facts := handlerFacts{statuses: make(map[int]bool)}
+	params := make(map[string]bool)
+	visited := make(map[string]bool)
+	s.walk(name, method, visited, params, facts.statuses)
+	for p := range params {
+		facts.params = append(facts.params, p)
+	}
+	for mapping := range errorStatuses {
+		if !visited[mapping] {
+			continue
+		}
+		mapped := make(map[int]bool)
+		ast.Inspect(s[mapping].Body, func(n ast.Node) bool {
+			return s.visit(n, "", "", nil, make(map[string]bool), make(map[string]bool), mapped)
+		})
+		for status := range mapped {
+			facts.mapped = append(facts.mapped, status)
+		}
+	}
+	return facts
+}
+
+func (s handlerSource) walk(name string, method *string, visited, params map[string]bool, statuses map[int]bool) {
+	fn, ok := s[name]
+	if !ok || visited[name] {
+		return
+	}
+	visited[name] = true
+	if status, ok := errorStatuses[name]; ok && method == nil {
+		statuses[status] = true
+		return
+	}
+	recv, recvType := "", ""
+	if fn.Recv != nil && len(fn.Recv.List[0].Names) > 0 {
+		recv = fn.Recv.List[0].Names[0].Name
+		recvType = strings.Split(funcKey(fn), ".")[0]
+	}
+	queries := make(map[string]bool)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SwitchStmt:
+			if method != nil && isSelector(n.Tag, "Method") {
+				for _, stmt := range n.Body.List {
+					clause := stmt.(*ast.CaseClause)
+					if len(clause.List) == 0 || slices.ContainsFunc(clause.List, func(e ast.Expr) bool { return isSelector(e, "Method"+methodName(*method)) }) {
+						for _, body := range clause.Body {
+							ast.Inspect(body, func(n ast.Node) bool { return s.visit(n, recv, recvType, queries, visited, params, statuses) })
+						}
+					}
+				}
+				return false
+			}
+		case *ast.AssignStmt:
+			if call, ok := n.Rhs[0].(*ast.CallExpr); ok && len(n.Lhs) == 1 && isSelector(call.Fun, "Query") {
+				queries[n.Lhs[0].(*ast.Ident).Name] = true
+			}
+		}
+		return s.visit(n, recv, recvType, queries, visited, params, statuses)
+	})
+}
+
+// visit a node of a function, recording the parameters and statuses and following the calls.
+func (s handlerSource) visit(n ast.Node, recv, recvType string, queries, visited, params map[string]bool, statuses map[int]bool) bool {
+	switch n := n.(type) {
+	case *ast.SelectorExpr:
+		if x, ok := n.X.(*ast.Ident); ok && x.Name == "http" {
+			if status, ok := statusCodes[strings.TrimPrefix(n.Sel.Name, "Status")]; ok {
+				statuses[status] = true
+			}
+		}
+	case *ast.CallExpr:
+		switch fun := n.Fun.(type) {
+		case *ast.Ident:
+			s.walk(fun.Name, nil, visited, params, statuses)
+		case *ast.SelectorExpr:
+			if x, ok := fun.X.(*ast.Ident); ok && x.Name == recv {
+				s.walk(recvType+"."+fun.Sel.Name, nil, visited, params, statuses)
+			}
+			if len(n.Args) == 0 {
+				break
+			}
+			lit, ok := n.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				break
+			}
+			name, _ := strconv.Unquote(lit.Value)
+			switch {
+			case fun.Sel.Name == "PathValue":
+				params["path "+name] = true
+			case fun.Sel.Name != "Get" && fun.Sel.Name != "Has":
+			case isSelector(fun.X, "Query") || isIdent(fun.X, queries):
+				params["query "+name] = true
+			case isSelector(fun.X, "Header") && name != "Authorization" && name != "Content-Type":
+				params["header "+name] = true
+			}
+		}
+	}
+	return true
+}
+
+// isSelector reports if the expression selects the name, or calls a selection of it.
+func isSelector(e ast.Expr, name string) bool {
+	if call, ok := e.(*ast.CallExpr); ok {
+		e = call.Fun
+	}
+	sel, ok := e.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == name
+}
+
+func isIdent(e ast.Expr, names map[string]bool) bool {
+	ident, ok := e.(*ast.Ident)
+	return ok && names[ident.Name]
+}
+
+// funcKey of a function, its name or the name of its receiver type and its name.
+func funcKey(fn *ast.FuncDecl) string {
+	if fn.Recv == nil {
+		return fn.Name.Name
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if generic, ok := typ.(*ast.IndexExpr); ok {
+		typ = generic.X
+	}
+	return typ.(*ast.Ident).Name + "." + fn.Name.Name
+}
+
+// methodName of the http.MethodX constant of a method like PATCH.
+func methodName(method string) string {
+	return method[:1] + strings.ToLower(method[1:])
+}
+
+// statusCodes by the names of their http.StatusX constants, like NotFound.
+var statusCodes = func() map[string]int {
+	codes := make(map[string]int)
+	names := strings.NewReplacer(" ", "", "-", "", "'", "")
+	for code := 100; code < 600; code++ {
+		if text := http.StatusText(code); text != "" {
+			codes[names.Replace(text)] = code
+		}
+	}
+	return codes
+}()
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"news-aggregator/server/service"
+	"strconv"
+	"strings"
+)
+
+type RetentionHandler struct {
+	Retention service.Retention
+	// Token authorizing the requests as a bearer token, the retention cannot be run
+	// through the API without one.
+	Token string
+}
+
+// Prune handles POST requests running the retention of the stored news.
+// With dry-run=true the report lists what would be deleted and archived without changing anything.
+// Sources that could not be pruned are reported with an error and an Internal Server Error status.
+// Requests without the bearer token of the handler are Unauthorized, all are Forbidden when it has none.
+func (h RetentionHandler) Prune(w http.ResponseWriter, r *http.Request) {
+	if h.Token == "" {
+		log.Printf("Retention requested without an admin token configured")
+		writeError(w, http.StatusForbidden, "the retention is disabled, start the server with --admin-token to enable it")
+		return
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.Token)) != 1 {
+		log.Printf("Retention requested without a valid token")
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, "a valid bearer token is required")
+		return
+	}
+	if r.Method != http.MethodPost {
+		log.Printf("Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	dryRun := false
+	if value := r.URL.Query().Get("dry-run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			log.Printf("Invalid dry-run: %s", value)
+			http.Error(w, "invalid dry-run. Please use `true` or `false`", http.StatusBadRequest)
+			return
+		}
+	}
+	report, err := h.Retention.Run(r.Context(), dryRun)
+	if err != nil && len(report.Sources) == 0 {
+		log.Printf("Error running retention: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
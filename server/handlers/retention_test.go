@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"news-aggregator/internal/entity"
+	"news-aggregator/server/managers"
+	"news-aggregator/server/managers/mock_managers"
+	"news-aggregator/server/service"
+)
+
+func TestRetention(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		// token of the handler and authorization of the request.
+		token         string
+		authorization string
+		dryRun        bool
+		pruneErr      error
+		wantStatus    int
+	}{
+		{name: "run", method: "POST", target: "/admin/retention", token: "secret", authorization: "Bearer secret", wantStatus: http.StatusOK},
+		{name: "dry run", method: "POST", target: "/admin/retention?dry-run=true", token: "secret", authorization: "Bearer secret", dryRun: true, wantStatus: http.StatusOK},
+		{name: "failed source", method: "POST", target: "/admin/retention", token: "secret", authorization: "Bearer secret", pruneErr: errors.New("disk full"), wantStatus: http.StatusInternalServerError},
+		{name: "invalid dry run", method: "POST", target: "/admin/retention?dry-run=maybe", token: "secret", authorization: "Bearer secret", wantStatus: http.StatusBadRequest},
+		{name: "method not allowed", method: "GET", target: "/admin/retention", token: "secret", authorization: "Bearer secret", wantStatus: http.StatusMethodNotAllowed},
+		{name: "missing token", method: "POST", target: "/admin/retention", token: "secret", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", method: "POST", target: "/admin/retention", token: "secret", authorization: "Bearer guess", wantStatus: http.StatusUnauthorized},
+		{name: "disabled", method: "POST", target: "/admin/retention", authorization: "Bearer secret", wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+			mockNewsManager := mock_managers.NewMockNewsManager(ctrl)
+			handler := RetentionHandler{Retention: service.Retention{
+				SourceManager: mockSourceManager,
+				NewsManager:   mockNewsManager,
+				MaxCount:      1,
+			}, Token: tt.token}
+			if tt.wantStatus == http.StatusOK || tt.pruneErr != nil {
+				mockSourceManager.EXPECT().GetSources().Return([]entity.Source{{Name: "bbc_news"}}, nil)
+				mockNewsManager.EXPECT().ListSources().Return([]string{"bbc_news"}, nil)
+				mockNewsManager.EXPECT().Prune(gomock.Any(), "bbc_news", gomock.Any()).
+					DoAndReturn(func(_ any, _ string, options managers.PruneOptions) (managers.PruneResult, error) {
+						assert.Equal(t, tt.dryRun, options.DryRun)
+						return managers.PruneResult{Kept: 1, Deleted: []entity.News{{Link: "https://bbc.com/1"}}}, tt.pruneErr
+					})
+			}
+
+			req, err := http.NewRequest(tt.method, tt.target, nil)
+			assert.NoError(t, err)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(handler.Prune).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus == http.StatusOK {
+				var report service.RetentionReport
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&report))
+				assert.Equal(t, tt.dryRun, report.DryRun)
+				assert.Equal(t, []service.SourceRetention{{
+					Source:    "bbc_news",
+					Retention: entity.Retention{MaxCount: 1},
+					Kept:      1,
+					Deleted:   []entity.Link{"https://bbc.com/1"},
+				}}, report.Sources)
+			}
+		})
+	}
+}
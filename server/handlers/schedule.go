@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"news-aggregator/server/service"
+)
+
+type ScheduleHandler struct {
+	Scheduler *service.Scheduler
+}
+
+// Schedule handles GET requests for the fetch schedule of all sources.
+func (s ScheduleHandler) Schedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		log.Printf("Method not allowed: %s", r.Method)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.Scheduler.Status()); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"news-aggregator/server/service"
+)
+
+func TestSchedule(t *testing.T) {
+	scheduleHandler := ScheduleHandler{Scheduler: service.NewScheduler(service.Fetch{}, 0)}
+
+	req, err := http.NewRequest("GET", "/schedule", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	httpHandler := http.HandlerFunc(scheduleHandler.Schedule)
+	httpHandler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[]`, rr.Body.String())
+}
+
+func TestScheduleMethodNotAllowed(t *testing.T) {
+	scheduleHandler := ScheduleHandler{Scheduler: service.NewScheduler(service.Fetch{}, 0)}
+
+	req, err := http.NewRequest("POST", "/schedule", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	httpHandler := http.HandlerFunc(scheduleHandler.Schedule)
+	httpHandler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
@@ -2,12 +2,23 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/language"
+	"news-aggregator/internal/parser"
 	"news-aggregator/server/managers"
 	"regexp"
+	"strconv"
+	"time"
 )
 
+// languageCode is an ISO 639 language code.
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}$`)
+
 type SourceHandler struct {
 	SourceManager managers.SourceManager
 }
@@ -25,107 +36,287 @@ func (s SourceHandler) Sources(w http.ResponseWriter, r *http.Request) {
 		s.removeSource(w, r)
 	default:
 		log.Printf("Method not allowed: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
 	}
 }
 
 // getSources handles GET requests to retrieve news sources.
+// The ETag header holds the tag of the source, or of all sources without a name.
 func (s SourceHandler) getSources(w http.ResponseWriter, r *http.Request) {
 	sourceName := r.URL.Query().Get("name")
 	log.Printf("GET request received for source: %s", sourceName)
 
 	var feeds interface{}
-	var err error
-
+	var etag string
 	if sourceName == "" {
-		feeds, err = s.SourceManager.GetSources()
+		sources, err := s.SourceManager.GetSources()
+		if err != nil {
+			log.Printf("Error retrieving sources: %v", err)
+			writeError(w, sourceErrorStatus(err), err.Error())
+			return
+		}
+		feeds, etag = sources, managers.SourcesETag(sources)
 	} else {
-		feeds, err = s.SourceManager.GetSource(sourceName)
-	}
-
-	if err != nil {
-		log.Printf("Error retrieving sources: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		source, err := s.SourceManager.GetSource(sourceName)
+		if err != nil {
+			log.Printf("Error retrieving sources: %v", err)
+			writeError(w, sourceErrorStatus(err), err.Error())
+			return
+		}
+		feeds, etag = source, managers.SourceETag(source)
 	}
 
+	w.Header().Set("ETag", etag)
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(feeds); err != nil {
 		log.Printf("Error encoding response: %v", err)
-		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Error encoding response")
 	}
 }
 
 // downloadSource handles POST requests to add new news feed URL.
+// An optional JSON body holds the scrape profile for HTML pages,
+// an optional interval parameter the time between fetches and the optional
+// max-age and max-count parameters the retention of its news.
 func (s SourceHandler) downloadSource(w http.ResponseWriter, r *http.Request) {
 	urlStr := r.URL.Query().Get("url")
 	name := r.URL.Query().Get("name")
 	log.Printf("POST request received to add source with Name%s ; URL: %s", name, urlStr)
 	if urlStr == "" {
 		log.Print("URL parameter is missing")
-		http.Error(w, "URL parameter is missing", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "URL parameter is missing")
 		return
 	}
 	if name == "" {
 		log.Print("Name parameter is missing")
-		http.Error(w, "Name parameter is missing", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Name parameter is missing")
+		return
+	}
+	profile, err := decodeScrapeProfile(r)
+	if err != nil {
+		log.Printf("Invalid scrape profile: %v", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	interval, err := parseInterval(r)
+	if err != nil {
+		log.Printf("Invalid interval: %v", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	lang, err := parseLanguage(r)
+	if err != nil {
+		log.Printf("Invalid language: %v", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	retention, err := parseRetention(r)
+	if err != nil {
+		log.Printf("Invalid retention: %v", err)
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	reg := regexp.MustCompile(`[^\p{L}\p{N}_]+`)
 	cleaned := reg.ReplaceAllString(name, "_")
 
-	source, err := s.SourceManager.CreateSource(cleaned, urlStr)
+	source := entity.Source{Name: entity.SourceName(cleaned), PathToFile: entity.PathToFile(urlStr), Scrape: profile, Retention: retention}
+	if interval != nil {
+		source.Interval = *interval
+	}
+	if lang != nil {
+		source.Language = *lang
+	}
+	// The source is created with its settings at once, so it starts at its first version.
+	source, err = s.SourceManager.AddSource(source)
 	if err != nil {
 		log.Printf("Error creating source for URL %s: %v", urlStr, err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, sourceErrorStatus(err), err.Error())
 		return
 	}
+	w.Header().Set("ETag", managers.SourceETag(source))
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(source); err != nil {
 		log.Printf("Error encoding response: %v", err)
-		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Error encoding response")
 	}
 	log.Printf("Successfully created source with Name: %s and URL: %s", name, urlStr)
 }
 
-// updateSource handles PUT requests to update an existing news source URL.
+// updateSource handles PUT requests to update an existing news source URL,
+// its fetch interval, language, retention and, when a JSON body is given, its scrape profile.
+// All of them are changed at once, only when the source matches the optional If-Match header.
 func (s SourceHandler) updateSource(w http.ResponseWriter, r *http.Request) {
 	newUrl := r.URL.Query().Get("newUrl")
 	name := r.URL.Query().Get("name")
 	log.Printf("PUT request received to update source with Name %s ; New url  %s", name, newUrl)
 
-	if newUrl == "" {
-		log.Print("URL parameters are missing")
-		http.Error(w, "URL parameters are missing", http.StatusBadRequest)
-		return
-	}
 	if name == "" {
 		log.Print("Name parameter is missing")
-		http.Error(w, "Name parameter is missing", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Name parameter is missing")
+		return
+	}
+	profile, err := decodeScrapeProfile(r)
+	if err != nil {
+		log.Printf("Invalid scrape profile: %v", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	interval, err := parseInterval(r)
+	if err != nil {
+		log.Printf("Invalid interval: %v", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	lang, err := parseLanguage(r)
+	if err != nil {
+		log.Printf("Invalid language: %v", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	retention, err := parseRetention(r)
+	if err != nil {
+		log.Printf("Invalid retention: %v", err)
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	err := s.SourceManager.UpdateSource(name, newUrl)
+	if newUrl == "" && profile == nil && interval == nil && lang == nil && retention == nil {
+		log.Print("URL parameters are missing")
+		writeError(w, http.StatusBadRequest, "URL parameters are missing")
+		return
+	}
+	source, err := s.SourceManager.UpdateSourceIfMatch(name, r.Header.Get("If-Match"), func(source *entity.Source) {
+		if newUrl != "" {
+			source.PathToFile = entity.PathToFile(newUrl)
+		}
+		if profile != nil {
+			source.Scrape = profile
+		}
+		if interval != nil {
+			source.Interval = *interval
+		}
+		if lang != nil {
+			source.Language = *lang
+		}
+		if retention != nil {
+			source.Retention = retention
+		}
+	})
 	if err != nil {
-		log.Printf("Error updating source from URL %s to %s: %v", name, newUrl, err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Printf("Error updating source %s: %v", name, err)
+		writeError(w, sourceErrorStatus(err), err.Error())
 		return
 	}
+	w.Header().Set("ETag", managers.SourceETag(source))
+}
+
+// sourceErrorStatus is the status code of an error of the source manager.
+func sourceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, managers.ErrSourceNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, managers.ErrSourceExists):
+		return http.StatusConflict
+	case errors.Is(err, managers.ErrSourceChanged):
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseInterval reads the optional interval parameter given as a duration like "15m".
+// It returns nil if the parameter is missing; "0" resets the source to the default interval.
+func parseInterval(r *http.Request) (*entity.Interval, error) {
+	value := r.URL.Query().Get("interval")
+	if value == "" {
+		return nil, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return nil, fmt.Errorf("invalid interval %q", value)
+	}
+	interval := entity.Interval(d)
+	return &interval, nil
+}
+
+// parseLanguage reads the optional language parameter given as a language tag like "uk" or "de-AT".
+// It returns nil if the parameter is missing; an empty value resets the source to the language of its feed.
+func parseLanguage(r *http.Request) (*string, error) {
+	if !r.URL.Query().Has("language") {
+		return nil, nil
+	}
+	value := r.URL.Query().Get("language")
+	lang := language.Normalize(value)
+	if lang != "" && !languageCode.MatchString(lang) {
+		return nil, fmt.Errorf("invalid language %q", value)
+	}
+	return &lang, nil
+}
+
+// parseRetention reads the optional max-age and max-count parameters, a duration like "720h"
+// and a number of news. It returns nil if both are missing; when either is given both are replaced,
+// zero values reset the source to the default retention.
+func parseRetention(r *http.Request) (*entity.Retention, error) {
+	query := r.URL.Query()
+	if !query.Has("max-age") && !query.Has("max-count") {
+		return nil, nil
+	}
+	var retention entity.Retention
+	if value := query.Get("max-age"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid max-age %q", value)
+		}
+		retention.MaxAge = entity.Interval(d)
+	}
+	if value := query.Get("max-count"); value != "" {
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid max-count %q", value)
+		}
+		retention.MaxCount = count
+	}
+	return &retention, nil
 }
 
-// removeSource handles DELETE requests to remove a news source.
+// decodeScrapeProfile reads an optional scrape profile from the JSON request body.
+// It returns nil if the body is empty.
+func decodeScrapeProfile(r *http.Request) (*entity.ScrapeProfile, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	var profile entity.ScrapeProfile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("invalid scrape profile: %w", err)
+	}
+	if err := parser.ValidateProfile(&profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// removeSource handles DELETE requests to remove a news source,
+// only when it matches the optional If-Match header.
 func (s SourceHandler) removeSource(w http.ResponseWriter, r *http.Request) {
 	sourceName := r.URL.Query().Get("name")
 	log.Printf("DELETE request received to remove source with name: %s", sourceName)
 
 	if sourceName == "" {
 		log.Print("Source name is missing")
-		http.Error(w, "Source name is missing", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Source name is missing")
 		return
 	}
-	err := s.SourceManager.RemoveSourceByName(sourceName)
+	var err error
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		err = s.SourceManager.RemoveSourceIfMatch(sourceName, ifMatch)
+	} else {
+		err = s.SourceManager.RemoveSourceByName(sourceName)
+	}
 	if err != nil {
 		log.Printf("Error removing source with name %s: %v", sourceName, err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, sourceErrorStatus(err), err.Error())
 		return
 	}
 }
@@ -2,13 +2,17 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"news-aggregator/internal/entity"
+	"news-aggregator/server/managers"
 	"news-aggregator/server/managers/mock_managers"
 )
 
@@ -31,6 +35,7 @@ func TestSourcesGet(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.JSONEq(t, `[{"Name":"bbc_news","PathToFile":"test-path-to-file"}]`, rr.Body.String())
+	assert.Equal(t, managers.SourcesETag(expectedSources), rr.Header().Get("ETag"))
 }
 
 func TestGetSourceByName(t *testing.T) {
@@ -52,6 +57,7 @@ func TestGetSourceByName(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.JSONEq(t, `{"Name":"bbc_news","PathToFile":"test-path-to-file"}`, rr.Body.String())
+	assert.Equal(t, managers.SourceETag(expectedSource), rr.Header().Get("ETag"))
 }
 
 func TestGetSourcesEmpty(t *testing.T) {
@@ -82,7 +88,7 @@ func TestDownloadSource(t *testing.T) {
 	sourceHandler := SourceHandler{SourceManager: mockSourceManager}
 
 	expectedSource := entity.Source{Name: "test_feed", PathToFile: "http://example.com/feed"}
-	mockSourceManager.EXPECT().CreateSource("test_feed", "http://example.com/feed").Return(expectedSource, nil)
+	mockSourceManager.EXPECT().AddSource(expectedSource).Return(expectedSource, nil)
 
 	req, err := http.NewRequest("POST", "/sources?name=test_feed&url=http://example.com/feed", nil)
 	assert.NoError(t, err)
@@ -119,7 +125,7 @@ func TestDownloadSourceCreationError(t *testing.T) {
 	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
 	sourceHandler := SourceHandler{SourceManager: mockSourceManager}
 
-	mockSourceManager.EXPECT().CreateSource("test_feed", "http://example.com/feed").Return(entity.Source{}, errors.New("creation error"))
+	mockSourceManager.EXPECT().AddSource(entity.Source{Name: "test_feed", PathToFile: "http://example.com/feed"}).Return(entity.Source{}, errors.New("creation error"))
 
 	req, err := http.NewRequest("POST", "/sources?name=test_feed&url=http://example.com/feed", nil)
 	assert.NoError(t, err)
@@ -131,6 +137,184 @@ func TestDownloadSourceCreationError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 }
 
+func TestDownloadSourceWithScrapeProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	sourceHandler := SourceHandler{SourceManager: mockSourceManager}
+
+	profile := &entity.ScrapeProfile{ItemSelector: "article", TitleSelector: "h2"}
+	source := entity.Source{Name: "bbc_html", PathToFile: "https://www.bbc.com/news", Scrape: profile}
+	mockSourceManager.EXPECT().AddSource(source).Return(source, nil)
+
+	body := strings.NewReader(`{"ItemSelector":"article","TitleSelector":"h2"}`)
+	req, err := http.NewRequest("POST", "/sources?name=bbc_html&url=https://www.bbc.com/news", body)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	httpHandler := http.HandlerFunc(sourceHandler.Sources)
+	httpHandler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"Name":"bbc_html","PathToFile":"https://www.bbc.com/news","Scrape":{"ItemSelector":"article","TitleSelector":"h2"}}`, rr.Body.String())
+}
+
+func TestDownloadSourceInvalidScrapeProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	sourceHandler := SourceHandler{SourceManager: mockSourceManager}
+
+	body := strings.NewReader(`{"TitleSelector":"h2"}`)
+	req, err := http.NewRequest("POST", "/sources?name=bbc_html&url=https://www.bbc.com/news", body)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	httpHandler := http.HandlerFunc(sourceHandler.Sources)
+	httpHandler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUpdateSourceScrapeProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	sourceHandler := SourceHandler{SourceManager: mockSourceManager}
+
+	expectUpdateSource(t, mockSourceManager, "bbc_html", "",
+		entity.Source{Name: "bbc_html"},
+		entity.Source{Name: "bbc_html", Scrape: &entity.ScrapeProfile{ItemSelector: "article"}})
+
+	req, err := http.NewRequest("PUT", "/sources?name=bbc_html", strings.NewReader(`{"ItemSelector":"article"}`))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	httpHandler := http.HandlerFunc(sourceHandler.Sources)
+	httpHandler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestUpdateSourceInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	sourceHandler := SourceHandler{SourceManager: mockSourceManager}
+
+	expectUpdateSource(t, mockSourceManager, "test_feed", "",
+		entity.Source{Name: "test_feed", Language: "en"},
+		entity.Source{Name: "test_feed", Language: "en", Interval: entity.Interval(15 * time.Minute)})
+
+	req, err := http.NewRequest("PUT", "/sources?name=test_feed&interval=15m", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	httpHandler := http.HandlerFunc(sourceHandler.Sources)
+	httpHandler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestDownloadSourceInvalidInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	sourceHandler := SourceHandler{SourceManager: mockSourceManager}
+
+	req, err := http.NewRequest("POST", "/sources?name=test_feed&url=http://example.com&interval=often", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	httpHandler := http.HandlerFunc(sourceHandler.Sources)
+	httpHandler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUpdateSourceLanguage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	sourceHandler := SourceHandler{SourceManager: mockSourceManager}
+
+	expectUpdateSource(t, mockSourceManager, "test_feed", "",
+		entity.Source{Name: "test_feed"},
+		entity.Source{Name: "test_feed", Language: "uk"})
+
+	req, err := http.NewRequest("PUT", "/sources?name=test_feed&language=uk-UA", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	httpHandler := http.HandlerFunc(sourceHandler.Sources)
+	httpHandler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestUpdateSourceRetention(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	sourceHandler := SourceHandler{SourceManager: mockSourceManager}
+
+	expectUpdateSource(t, mockSourceManager, "test_feed", "",
+		entity.Source{Name: "test_feed"},
+		entity.Source{Name: "test_feed", Retention: &entity.Retention{MaxAge: entity.Interval(720 * time.Hour), MaxCount: 500}})
+
+	req, err := http.NewRequest("PUT", "/sources?name=test_feed&max-age=720h&max-count=500", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	httpHandler := http.HandlerFunc(sourceHandler.Sources)
+	httpHandler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestDownloadSourceInvalidRetention(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	sourceHandler := SourceHandler{SourceManager: mockSourceManager}
+
+	for _, params := range []string{"max-age=month", "max-count=-1", "max-count=many"} {
+		req, err := http.NewRequest("POST", "/sources?name=test_feed&url=http://example.com&"+params, nil)
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		httpHandler := http.HandlerFunc(sourceHandler.Sources)
+		httpHandler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, params)
+	}
+}
+
+func TestDownloadSourceInvalidLanguage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	sourceHandler := SourceHandler{SourceManager: mockSourceManager}
+
+	req, err := http.NewRequest("POST", "/sources?name=test_feed&url=http://example.com&language=ukrainian", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	httpHandler := http.HandlerFunc(sourceHandler.Sources)
+	httpHandler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
 func TestUpdateSource(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -138,16 +322,32 @@ func TestUpdateSource(t *testing.T) {
 	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
 	sourceHandler := SourceHandler{SourceManager: mockSourceManager}
 
-	mockSourceManager.EXPECT().UpdateSource("test_feed", "http://newurl.com").Return(nil)
+	source := entity.Source{Name: "test_feed", PathToFile: "http://newurl.com", Version: 2}
+	expectUpdateSource(t, mockSourceManager, "test_feed", `"1"`,
+		entity.Source{Name: "test_feed", PathToFile: "http://example.com", Version: 1}, source)
 
 	req, err := http.NewRequest("PUT", "/sources?name=test_feed&newUrl=http://newurl.com", nil)
 	assert.NoError(t, err)
+	req.Header.Set("If-Match", `"1"`)
 
 	rr := httptest.NewRecorder()
 	httpHandler := http.HandlerFunc(sourceHandler.Sources)
 	httpHandler.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, managers.SourceETag(source), rr.Header().Get("ETag"))
+}
+
+// expectUpdateSource of the name with the If-Match value, its change has to turn the source into the wanted one.
+func expectUpdateSource(t *testing.T, m *mock_managers.MockSourceManager, name, ifMatch string, source, want entity.Source) {
+	m.EXPECT().UpdateSourceIfMatch(name, ifMatch, gomock.Any()).
+		DoAndReturn(func(_, _ string, change func(source *entity.Source)) (entity.Source, error) {
+			change(&source)
+			// The managers increment the version.
+			source.Version = want.Version
+			assert.Equal(t, want, source)
+			return source, nil
+		})
 }
 
 func TestUpdateSourceError(t *testing.T) {
@@ -157,7 +357,7 @@ func TestUpdateSourceError(t *testing.T) {
 	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
 	sourceHandler := SourceHandler{SourceManager: mockSourceManager}
 
-	mockSourceManager.EXPECT().UpdateSource("test_feed", "http://newurl.com").Return(errors.New("update error"))
+	mockSourceManager.EXPECT().UpdateSourceIfMatch("test_feed", "", gomock.Any()).Return(entity.Source{}, errors.New("update error"))
 
 	req, err := http.NewRequest("PUT", "/sources?name=test_feed&newUrl=http://newurl.com", nil)
 	assert.NoError(t, err)
@@ -207,6 +407,95 @@ func TestRemoveSourceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 }
 
+func TestSourcesConflicts(t *testing.T) {
+	notFound := fmt.Errorf("test_feed: %w", managers.ErrSourceNotFound)
+	changed := fmt.Errorf("test_feed: %w", managers.ErrSourceChanged)
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		ifMatch string
+		expect  func(m *mock_managers.MockSourceManager)
+		code    int
+	}{
+		{
+			name:   "get missing source",
+			method: "GET",
+			target: "/sources?name=test_feed",
+			expect: func(m *mock_managers.MockSourceManager) {
+				m.EXPECT().GetSource("test_feed").Return(entity.Source{}, notFound)
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name:   "create existing source",
+			method: "POST",
+			target: "/sources?name=test_feed&url=http://example.com",
+			expect: func(m *mock_managers.MockSourceManager) {
+				m.EXPECT().AddSource(entity.Source{Name: "test_feed", PathToFile: "http://example.com"}).
+					Return(entity.Source{}, fmt.Errorf("test_feed: %w", managers.ErrSourceExists))
+			},
+			code: http.StatusConflict,
+		},
+		{
+			name:    "update changed source",
+			method:  "PUT",
+			target:  "/sources?name=test_feed&interval=1h",
+			ifMatch: `"old"`,
+			expect: func(m *mock_managers.MockSourceManager) {
+				m.EXPECT().UpdateSourceIfMatch("test_feed", `"old"`, gomock.Any()).Return(entity.Source{}, changed)
+			},
+			code: http.StatusPreconditionFailed,
+		},
+		{
+			name:   "update missing source",
+			method: "PUT",
+			target: "/sources?name=test_feed&interval=1h",
+			expect: func(m *mock_managers.MockSourceManager) {
+				m.EXPECT().UpdateSourceIfMatch("test_feed", "", gomock.Any()).Return(entity.Source{}, notFound)
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name:    "remove changed source",
+			method:  "DELETE",
+			target:  "/sources?name=test_feed",
+			ifMatch: `"old"`,
+			expect: func(m *mock_managers.MockSourceManager) {
+				m.EXPECT().RemoveSourceIfMatch("test_feed", `"old"`).Return(changed)
+			},
+			code: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "remove matching source",
+			method:  "DELETE",
+			target:  "/sources?name=test_feed",
+			ifMatch: `"new"`,
+			expect: func(m *mock_managers.MockSourceManager) {
+				m.EXPECT().RemoveSourceIfMatch("test_feed", `"new"`).Return(nil)
+			},
+			code: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+			sourceHandler := SourceHandler{SourceManager: mockSourceManager}
+			tt.expect(mockSourceManager)
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(sourceHandler.Sources).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
 func TestSourcesMethodNotAllowed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/language"
+	"news-aggregator/internal/parser"
+	"news-aggregator/server/managers"
+	"regexp"
+	"time"
+)
+
+// sourceNamePattern of the names of sources created through the v1 API.
+var sourceNamePattern = regexp.MustCompile(`^[\p{L}\p{N}_]+$`)
+
+// maxSourceBody is the size limit of the JSON body of a source.
+const maxSourceBody = 1 << 20
+
+// sourceResource is the JSON representation of a source in the v1 API,
+// the body of its requests and responses.
+type sourceResource struct {
+	Name      string                `json:"name"`
+	URL       string                `json:"url"`
+	Scrape    *entity.ScrapeProfile `json:"scrape,omitempty"`
+	Interval  *entity.Interval      `json:"interval,omitempty"`
+	Language  *string               `json:"language,omitempty"`
+	Retention *entity.Retention     `json:"retention,omitempty"`
+	// Version of the source, ignored in requests.
+	Version int64 `json:"version,omitempty"`
+}
+
+// sourcePatch is the body of a PATCH request, a JSON merge patch of a sourceResource:
+// only the given fields are changed and null resets a field to its default.
+type sourcePatch struct {
+	URL       optional[string]               `json:"url"`
+	Scrape    optional[entity.ScrapeProfile] `json:"scrape"`
+	Interval  optional[entity.Interval]      `json:"interval"`
+	Language  optional[string]               `json:"language"`
+	Retention optional[entity.Retention]     `json:"retention"`
+}
+
+// optional field of a patch, Set when it is given, with a nil Value when it is null.
+type optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// UnmarshalJSON records that the field is given.
+func (o *optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	o.Value = &value
+	return nil
+}
+
+// List handles GET requests for all sources.
+func (s SourceHandler) List(w http.ResponseWriter, r *http.Request) {
+	sources, err := s.SourceManager.GetSources()
+	if err != nil {
+		log.Printf("Error retrieving sources: %v", err)
+		writeError(w, sourceErrorStatus(err), err.Error())
+		return
+	}
+	resources := make([]sourceResource, 0, len(sources))
+	for _, source := range sources {
+		resources = append(resources, toResource(source))
+	}
+	w.Header().Set("ETag", managers.SourcesETag(sources))
+	writeJSON(w, http.StatusOK, resources)
+}
+
+// Create handles POST requests with a new source, responding 201 with the created source.
+func (s SourceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var resource sourceResource
+	if err := decodeSourceBody(w, r, &resource); err != nil {
+		log.Printf("Invalid source: %v", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !sourceNamePattern.MatchString(resource.Name) {
+		log.Printf("Invalid source name %q", resource.Name)
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid name %q, use letters, digits and underscores", resource.Name))
+		return
+	}
+	if err := resource.validate(); err != nil {
+		log.Printf("Invalid source %s: %v", resource.Name, err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	log.Printf("POST request received to create source %s with URL %s", resource.Name, resource.URL)
+
+	source := entity.Source{Name: entity.SourceName(resource.Name), PathToFile: entity.PathToFile(resource.URL)}
+	resource.apply(&source)
+	source, err := s.SourceManager.AddSource(source)
+	if err != nil {
+		log.Printf("Error creating source %s: %v", resource.Name, err)
+		writeError(w, sourceErrorStatus(err), err.Error())
+		return
+	}
+	w.Header().Set("Location", "/v1/sources/"+url.PathEscape(resource.Name))
+	w.Header().Set("ETag", managers.SourceETag(source))
+	writeJSON(w, http.StatusCreated, toResource(source))
+}
+
+// Get handles GET requests for a single source.
+func (s SourceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	source, err := s.SourceManager.GetSource(name)
+	if err != nil {
+		log.Printf("Error retrieving source %s: %v", name, err)
+		writeError(w, sourceErrorStatus(err), err.Error())
+		return
+	}
+	w.Header().Set("ETag", managers.SourceETag(source))
+	writeJSON(w, http.StatusOK, toResource(source))
+}
+
+// Replace handles PUT requests replacing the URL and all settings of a source,
+// settings missing from the body are reset to their defaults.
+func (s SourceHandler) Replace(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	var resource sourceResource
+	if err := decodeSourceBody(w, r, &resource); err != nil {
+		log.Printf("Invalid source: %v", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if resource.Name != "" && resource.Name != name {
+		log.Printf("Source name %s does not match %s", resource.Name, name)
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("name %q does not match the source %q", resource.Name, name))
+		return
+	}
+	if err := resource.validate(); err != nil {
+		log.Printf("Invalid source %s: %v", name, err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.change(w, r, name, func(source *entity.Source) {
+		*source = entity.Source{Name: source.Name, PathToFile: entity.PathToFile(resource.URL)}
+		resource.apply(source)
+	})
+}
+
+// Patch handles PATCH requests changing the settings given in a JSON merge patch of the source.
+func (s SourceHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	var patch sourcePatch
+	if err := decodeSourceBody(w, r, &patch); err != nil {
+		log.Printf("Invalid patch: %v", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := patch.validate(); err != nil {
+		log.Printf("Invalid patch of source %s: %v", name, err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.change(w, r, name, patch.apply)
+}
+
+// Delete handles DELETE requests removing a source, its stored news are kept.
+func (s SourceHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	log.Printf("DELETE request received to remove source with name: %s", name)
+	if err := s.SourceManager.RemoveSourceIfMatch(name, r.Header.Get("If-Match")); err != nil {
+		log.Printf("Error removing source with name %s: %v", name, err)
+		writeError(w, sourceErrorStatus(err), err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// change the source in a single update when it matches the If-Match header and respond with it.
+func (s SourceHandler) change(w http.ResponseWriter, r *http.Request, name string, change func(source *entity.Source)) {
+	log.Printf("%s request received to update source with name: %s", r.Method, name)
+	source, err := s.SourceManager.UpdateSourceIfMatch(name, r.Header.Get("If-Match"), change)
+	if err != nil {
+		log.Printf("Error updating source %s: %v", name, err)
+		writeError(w, sourceErrorStatus(err), err.Error())
+		return
+	}
+	w.Header().Set("ETag", managers.SourceETag(source))
+	writeJSON(w, http.StatusOK, toResource(source))
+}
+
+// decodeSourceBody decodes the JSON body into the value, rejecting unknown fields.
+func decodeSourceBody(w http.ResponseWriter, r *http.Request, value any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSourceBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(value); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("missing JSON body")
+		}
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if decoder.More() {
+		return errors.New("invalid JSON body: more than one value")
+	}
+	return nil
+}
+
+// toResource represents the source in the v1 API.
+func toResource(source entity.Source) sourceResource {
+	resource := sourceResource{
+		Name:      string(source.Name),
+		URL:       string(source.PathToFile),
+		Scrape:    source.Scrape,
+		Retention: source.Retention,
+		Version:   source.Version,
+	}
+	if source.Interval != 0 {
+		resource.Interval = &source.Interval
+	}
+	if source.Language != "" {
+		resource.Language = &source.Language
+	}
+	return resource
+}
+
+// validate the URL and settings of the resource, normalizing its language.
+func (resource *sourceResource) validate() error {
+	if err := validateURL(resource.URL); err != nil {
+		return err
+	}
+	return validateSettings(resource.Scrape, resource.Interval, resource.Language, resource.Retention)
+}
+
+// apply the settings of the resource to the source.
+func (resource sourceResource) apply(source *entity.Source) {
+	source.Scrape = resource.Scrape
+	if resource.Interval != nil {
+		source.Interval = *resource.Interval
+	}
+	if resource.Language != nil {
+		source.Language = *resource.Language
+	}
+	source.Retention = resource.Retention
+}
+
+// validate the given fields of the patch, normalizing its language.
+func (patch *sourcePatch) validate() error {
+	if patch.URL.Set {
+		if patch.URL.Value == nil {
+			return errors.New("url cannot be null")
+		}
+		if err := validateURL(*patch.URL.Value); err != nil {
+			return err
+		}
+	}
+	return validateSettings(patch.Scrape.Value, patch.Interval.Value, patch.Language.Value, patch.Retention.Value)
+}
+
+// apply the given fields of the patch to the source.
+func (patch sourcePatch) apply(source *entity.Source) {
+	if patch.URL.Set {
+		source.PathToFile = entity.PathToFile(*patch.URL.Value)
+	}
+	if patch.Scrape.Set {
+		source.Scrape = patch.Scrape.Value
+	}
+	if patch.Interval.Set {
+		source.Interval = valueOrZero(patch.Interval.Value)
+	}
+	if patch.Language.Set {
+		source.Language = valueOrZero(patch.Language.Value)
+	}
+	if patch.Retention.Set {
+		source.Retention = patch.Retention.Value
+	}
+}
+
+func valueOrZero[T any](value *T) T {
+	var zero T
+	if value == nil {
+		return zero
+	}
+	return *value
+}
+
+// validateURL of a feed or page, it has to be an absolute http or https URL.
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url %q, expected an absolute http or https URL", value)
+	}
+	return nil
+}
+
+// validateSettings of a source, the ones that are nil are not given.
+// The language is normalized to its language code.
+func validateSettings(profile *entity.ScrapeProfile, interval *entity.Interval, lang *string, retention *entity.Retention) error {
+	if profile != nil {
+		if err := parser.ValidateProfile(profile); err != nil {
+			return err
+		}
+	}
+	if interval != nil && *interval < 0 {
+		return fmt.Errorf("invalid interval %q", time.Duration(*interval))
+	}
+	if lang != nil {
+		normalized := language.Normalize(*lang)
+		if normalized != "" && !languageCode.MatchString(normalized) {
+			return fmt.Errorf("invalid language %q", *lang)
+		}
+		*lang = normalized
+	}
+	if retention != nil && (retention.MaxAge < 0 || retention.MaxCount < 0) {
+		return fmt.Errorf("invalid retention, max age %s and max count %d cannot be negative", retention.MaxAge, retention.MaxCount)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"news-aggregator/server/managers"
+)
+
+func newSourcesV1Server(t *testing.T) http.Handler {
+	sources := SourceHandler{SourceManager: managers.CreateSourceFolder(filepath.Join(t.TempDir(), "sources.json"))}
+	return API{Sources: sources}.Handler()
+}
+
+func serveV1(handler http.Handler, method, target, body string, header ...string) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, target, nil)
+	} else {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSourcesV1(t *testing.T) {
+	server := newSourcesV1Server(t)
+
+	rr := serveV1(server, "GET", "/v1/sources", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[]`, rr.Body.String())
+
+	rr = serveV1(server, "POST", "/v1/sources", `{"name":"bbc","url":"https://bbc.com/rss","interval":"15m","language":"en-GB"}`)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "/v1/sources/bbc", rr.Header().Get("Location"))
+	assert.JSONEq(t, `{"name":"bbc","url":"https://bbc.com/rss","interval":"15m0s","language":"en","version":1}`, rr.Body.String())
+	created := rr.Header().Get("ETag")
+	assert.NotEmpty(t, created)
+
+	rr = serveV1(server, "POST", "/v1/sources", `{"name":"bbc","url":"https://bbc.com/rss"}`)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.JSONEq(t, `{"status":409,"error":"Conflict","message":"Source with name bbc already exists"}`, rr.Body.String())
+
+	rr = serveV1(server, "GET", "/v1/sources/bbc", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, created, rr.Header().Get("ETag"))
+
+	rr = serveV1(server, "PATCH", "/v1/sources/bbc", `{"retention":{"MaxCount":100},"language":null}`, "If-Match", created)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"name":"bbc","url":"https://bbc.com/rss","interval":"15m0s","retention":{"MaxCount":100},"version":2}`, rr.Body.String())
+	patched := rr.Header().Get("ETag")
+
+	rr = serveV1(server, "PUT", "/v1/sources/bbc", `{"url":"https://bbc.co.uk/rss"}`, "If-Match", created)
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+
+	rr = serveV1(server, "PUT", "/v1/sources/bbc", `{"url":"https://bbc.co.uk/rss"}`, "If-Match", patched)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"name":"bbc","url":"https://bbc.co.uk/rss","version":3}`, rr.Body.String())
+
+	rr = serveV1(server, "GET", "/v1/sources", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var list []sourceResource
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	assert.Len(t, list, 1)
+
+	rr = serveV1(server, "DELETE", "/v1/sources/bbc", "")
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	rr = serveV1(server, "DELETE", "/v1/sources/bbc", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	rr = serveV1(server, "GET", "/v1/sources/bbc", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+}
+
+func TestSourcesV1_Invalid(t *testing.T) {
+	server := newSourcesV1Server(t)
+	rr := serveV1(server, "POST", "/v1/sources", `{"name":"cnn","url":"https://cnn.com/rss"}`)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		code   int
+	}{
+		{name: "missing body", method: "POST", target: "/v1/sources", code: http.StatusBadRequest},
+		{name: "unknown field", method: "POST", target: "/v1/sources", body: `{"name":"bbc","url":"https://bbc.com","newUrl":"x"}`, code: http.StatusBadRequest},
+		{name: "invalid name", method: "POST", target: "/v1/sources", body: `{"name":"bbc news","url":"https://bbc.com"}`, code: http.StatusBadRequest},
+		{name: "missing url", method: "POST", target: "/v1/sources", body: `{"name":"bbc"}`, code: http.StatusBadRequest},
+		{name: "relative url", method: "POST", target: "/v1/sources", body: `{"name":"bbc","url":"bbc.com/rss"}`, code: http.StatusBadRequest},
+		{name: "invalid interval", method: "POST", target: "/v1/sources", body: `{"name":"bbc","url":"https://bbc.com","interval":"often"}`, code: http.StatusBadRequest},
+		{name: "invalid language", method: "POST", target: "/v1/sources", body: `{"name":"bbc","url":"https://bbc.com","language":"english"}`, code: http.StatusBadRequest},
+		{name: "invalid profile", method: "POST", target: "/v1/sources", body: `{"name":"bbc","url":"https://bbc.com","scrape":{"TitleSelector":"h2"}}`, code: http.StatusBadRequest},
+		{name: "negative retention", method: "PATCH", target: "/v1/sources/cnn", body: `{"retention":{"MaxCount":-1}}`, code: http.StatusBadRequest},
+		{name: "null url", method: "PATCH", target: "/v1/sources/cnn", body: `{"url":null}`, code: http.StatusBadRequest},
+		{name: "other name", method: "PUT", target: "/v1/sources/cnn", body: `{"name":"bbc","url":"https://bbc.com"}`, code: http.StatusBadRequest},
+		{name: "patch unknown source", method: "PATCH", target: "/v1/sources/bbc", body: `{"interval":"1h"}`, code: http.StatusNotFound},
+		{name: "replace unknown source", method: "PUT", target: "/v1/sources/bbc", body: `{"url":"https://bbc.com"}`, code: http.StatusNotFound},
+		{name: "remove unknown source", method: "DELETE", target: "/v1/sources/bbc", code: http.StatusNotFound},
+		{name: "method not allowed", method: "POST", target: "/v1/sources/cnn", code: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serveV1(server, tt.method, tt.target, tt.body)
+			assert.Equal(t, tt.code, rr.Code, rr.Body.String())
+		})
+	}
+}
@@ -1,21 +1,22 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
-	"net/http"
-	"news-aggregator/server/handlers"
-	"news-aggregator/server/managers"
+	"news-aggregator/server/app"
+	"news-aggregator/server/service"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 // main initializes and starts the news aggregator server.
 func main() {
 	help := flag.Bool("help", false, "Show all available arguments and their descriptions.")
-	port := flag.String("port", ":8443", "Specify the port on which the server should listen. Default is :8443.")
-	pathToSourcesFile := flag.String("path-to-source", "server/sources.json", "Path to the file containing news sources. Default is 'server/sources.json'.")
-	pathToNews := flag.String("news-folder", "server-news/", "Path to the folder where news files are stored. Default is 'server-news/'.")
-	certFile := flag.String("tls-cert", "/etc/tls/certs/tls.crt", "Path to the TLS certificate file.")
-	keyFile := flag.String("tls-key", "/etc/tls/certs/tls.key", "Path to the TLS key file.")
+	migrate := flag.Bool("migrate", false, "Import the sources file and news folder into the database and exit.")
+	var config app.Config
+	config.RegisterFlags(flag.CommandLine)
 
 	flag.Parse()
 
@@ -23,18 +24,36 @@ func main() {
 		flag.Usage()
 		return
 	}
-	sourceFolder := managers.CreateSourceFolder(*pathToSourcesFile)
-	newsFolder := managers.CreateNewsFolder(*pathToNews)
-	sourceHandler := handlers.SourceHandler{SourceManager: sourceFolder}
-	newsHandler := handlers.NewsHandler{NewsManager: newsFolder, SourceManager: sourceFolder}
+	if *migrate {
+		migrateToDB(config)
+		return
+	}
 
-	http.HandleFunc("/news", newsHandler.News)
-	http.HandleFunc("/sources", sourceHandler.Sources)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := app.Serve(ctx, config); err != nil {
+		log.Fatal("ListenAndServe: ", err)
+	}
+}
 
-	log.Println("Starting server on", *port)
-	err := http.ListenAndServeTLS(*port, *certFile, *keyFile, nil)
+// migrateToDB the sources file and news folder of the config.
+func migrateToDB(config app.Config) {
+	if config.Storage != "db" {
+		log.Fatal("Migration requires -storage=db")
+	}
+	storage, err := app.OpenStorage(config)
 	if err != nil {
-		log.Print("Error starting server: ", err)
-		log.Fatal("ListenAndServe: ", err)
+		log.Fatal("Error opening database: ", err)
+	}
+	defer storage.Close()
+	report, err := service.Migration{
+		PathToSources: config.PathToSources,
+		PathToNews:    config.PathToNews,
+		SourceManager: storage.Sources,
+		NewsManager:   storage.News,
+	}.Run()
+	if err != nil {
+		log.Fatal("Error migrating: ", err)
 	}
+	log.Printf("Migrated %d sources and %d news into %s", report.Sources, report.News, config.PathToDB)
 }
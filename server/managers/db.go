@@ -0,0 +1,79 @@
+package managers
+
+import (
+	"encoding/json"
+	"log"
+	"news-aggregator/internal/canonical"
+	"news-aggregator/internal/entity"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Buckets of the embedded database.
+var (
+	// sourcesBucket maps source names to sources.
+	sourcesBucket = []byte("sources")
+	// newsBucket maps "<source>\x00<link>" to news, its prefix scans index news by source.
+	newsBucket = []byte("news")
+	// linksBucket indexes news by "<link>\x00<source>".
+	linksBucket = []byte("links")
+	// datesBucket indexes news by "<date>\x00<source>\x00<link>" in chronological order.
+	datesBucket = []byte("dates")
+	// canonicalBucket maps canonical links to the key of the first news stored with them.
+	canonicalBucket = []byte("canonical")
+	// revisionsBucket maps source names to the ID of the last transaction changing their news.
+	revisionsBucket = []byte("revisions")
+)
+
+// keySeparator of the parts of composite keys, it cannot occur in source names or links.
+const keySeparator = "\x00"
+
+// dateKeyLayout sorts lexically in chronological order.
+const dateKeyLayout = "20060102T150405.000000000Z"
+
+// OpenDB opens or creates the embedded database used by the DB managers.
+func OpenDB(path string) (*bolt.DB, error) {
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		log.Printf("Error opening database %s: %v", path, err)
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{sourcesBucket, newsBucket, linksBucket, datesBucket, revisionsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		if tx.Bucket(canonicalBucket) != nil {
+			return nil
+		}
+		return indexCanonicalLinks(tx)
+	})
+	if err != nil {
+		log.Printf("Error creating database buckets: %v", err)
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// indexCanonicalLinks of the stored news in a new canonical bucket,
+// for databases created before news were deduplicated by their canonical link.
+func indexCanonicalLinks(tx *bolt.Tx) error {
+	links, err := tx.CreateBucket(canonicalBucket)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(newsBucket).ForEach(func(k, v []byte) error {
+		var item entity.News
+		if err := json.Unmarshal(v, &item); err != nil {
+			return err
+		}
+		link := []byte(canonical.Link(item))
+		if len(link) == 0 || links.Get(link) != nil {
+			return nil
+		}
+		return links.Put(link, k)
+	})
+}
@@ -0,0 +1,44 @@
+package managers
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	bolt "go.etcd.io/bbolt"
+	"news-aggregator/internal/entity"
+)
+
+// openTestDB in a temporary folder, closed at the end of the test.
+func openTestDB(t *testing.T) *bolt.DB {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "news.db"))
+	assert.NoError(t, err, "Expected no error opening database")
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	return db
+}
+
+func TestOpenDB_IndexesCanonicalLinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "news.db")
+	db, err := OpenDB(path)
+	assert.NoError(t, err)
+	news := []entity.News{{Title: "Title", Link: "https://www.example.com/1?utm_source=rss", Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}}
+	_, err = CreateNewsDB(db).AddNews(news, "source1")
+	assert.NoError(t, err)
+	// A database created before the canonical links were indexed.
+	assert.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket(canonicalBucket)
+	}))
+	assert.NoError(t, db.Close())
+
+	db, err = OpenDB(path)
+	assert.NoError(t, err)
+	defer db.Close()
+	_, err = CreateNewsDB(db).AddNews([]entity.News{{Title: "Copy", Link: "https://example.com/1"}}, "source2")
+	assert.NoError(t, err)
+	got, err := CreateNewsDB(db).GetNewsFromFolder("source2")
+	assert.NoError(t, err)
+	assert.Empty(t, got, "Expected the stored news to be indexed by canonical link")
+}
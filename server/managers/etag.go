@@ -0,0 +1,53 @@
+package managers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"news-aggregator/internal/entity"
+	"strings"
+)
+
+// SourceETag is the strong entity tag of the source, it changes with every change of the source.
+func SourceETag(source entity.Source) string {
+	return etag(source)
+}
+
+// SourcesETag is the strong entity tag of the collection of sources,
+// it changes when a source is added, changed or removed.
+func SourcesETag(sources []entity.Source) string {
+	return etag(sources)
+}
+
+func etag(value any) string {
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		// Sources always encode, an unmatchable tag keeps the precondition safe.
+		return `"invalid"`
+	}
+	sum := sha256.Sum256(jsonData)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// MatchETag reports if the If-Match value matches the entity tag of a resource,
+// an empty etag is a resource that does not exist. An empty If-Match value matches
+// anything, "*" any existing resource, otherwise one of its comma separated tags
+// has to be strongly equal to the etag.
+func MatchETag(ifMatch, etag string) bool {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "" {
+		return true
+	}
+	if etag == "" {
+		return false
+	}
+	if ifMatch == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(tag) == etag {
+			return true
+		}
+	}
+	return false
+}
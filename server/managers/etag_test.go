@@ -0,0 +1,114 @@
+package managers
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"news-aggregator/internal/entity"
+)
+
+func TestMatchETag(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		etag    string
+		want    bool
+	}{
+		{name: "no precondition", etag: `"a"`, want: true},
+		{name: "no precondition without resource", want: true},
+		{name: "any", ifMatch: "*", etag: `"a"`, want: true},
+		{name: "any without resource", ifMatch: "*", want: false},
+		{name: "equal", ifMatch: `"a"`, etag: `"a"`, want: true},
+		{name: "different", ifMatch: `"b"`, etag: `"a"`, want: false},
+		{name: "list", ifMatch: `"b", "a"`, etag: `"a"`, want: true},
+		{name: "weak", ifMatch: `W/"a"`, etag: `"a"`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchETag(tt.ifMatch, tt.etag))
+		})
+	}
+}
+
+func sourceTestManagers(t *testing.T) map[string]SourceManager {
+	return map[string]SourceManager{
+		"folder": CreateSourceFolder(filepath.Join(t.TempDir(), "sources.json")),
+		"db":     CreateSourceDB(openTestDB(t)),
+	}
+}
+
+func TestSourceManager_IfMatch(t *testing.T) {
+	for name, s := range sourceTestManagers(t) {
+		t.Run(name, func(t *testing.T) {
+			created, err := s.CreateSource("bbc", "https://bbc.com/rss")
+			assert.NoError(t, err)
+			etag := SourceETag(created)
+			_, err = s.CreateSource("bbc", "https://bbc.com/rss")
+			assert.ErrorIs(t, err, ErrSourceExists)
+
+			updated, err := s.UpdateSourceIfMatch("bbc", etag, func(source *entity.Source) {
+				source.Language = "en"
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, entity.Source{Name: "bbc", PathToFile: "https://bbc.com/rss", Language: "en", Version: 2}, updated)
+			assert.NotEqual(t, etag, SourceETag(updated))
+
+			_, err = s.UpdateSourceIfMatch("bbc", etag, func(source *entity.Source) {
+				source.Language = "de"
+			})
+			assert.ErrorIs(t, err, ErrSourceChanged)
+			_, err = s.UpdateSourceIfMatch("cnn", "", func(source *entity.Source) {})
+			assert.ErrorIs(t, err, ErrSourceNotFound)
+			_, err = s.GetSource("cnn")
+			assert.ErrorIs(t, err, ErrSourceNotFound)
+
+			assert.ErrorIs(t, s.RemoveSourceIfMatch("bbc", etag), ErrSourceChanged)
+			assert.ErrorIs(t, s.RemoveSourceIfMatch("cnn", "*"), ErrSourceChanged)
+			assert.NoError(t, s.RemoveSourceIfMatch("bbc", SourceETag(updated)))
+			sources, err := s.GetSources()
+			assert.NoError(t, err)
+			assert.Empty(t, sources)
+		})
+	}
+}
+
+func TestSourceManager_UpdateConcurrently(t *testing.T) {
+	for name, s := range sourceTestManagers(t) {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.CreateSource("bbc", "https://bbc.com/rss")
+			assert.NoError(t, err)
+
+			// Every writer retries its change until it is based on the latest version.
+			const writers = 10
+			var wg sync.WaitGroup
+			for i := 0; i < writers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for {
+						source, err := s.GetSource("bbc")
+						if !assert.NoError(t, err) {
+							return
+						}
+						_, err = s.UpdateSourceIfMatch("bbc", SourceETag(source), func(changed *entity.Source) {
+							changed.Interval = source.Interval + 1
+						})
+						if !errors.Is(err, ErrSourceChanged) {
+							assert.NoError(t, err)
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			source, err := s.GetSource("bbc")
+			assert.NoError(t, err)
+			assert.Equal(t, entity.Interval(writers), source.Interval, "Expected no lost update")
+			assert.Equal(t, int64(writers+1), source.Version)
+		})
+	}
+}
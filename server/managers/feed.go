@@ -1,34 +1,85 @@
 package managers
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"news-aggregator/internal/entity"
 	"news-aggregator/internal/parser"
-	"os"
-	"strings"
+	"strconv"
+	"time"
 )
 
-const fileName = "file"
-
 // FeedManager for fetching news feeds.
 //
 //go:generate mockgen -source=feed.go -destination=mock_managers/mock_feed.go
 type FeedManager interface {
-	FetchFeed(path string) ([]entity.News, error)
+	FetchFeed(ctx context.Context, source entity.Source) (Feed, error)
+	// SaveValidators of a downloaded feed once its news are stored,
+	// so a feed whose news were lost is downloaded in full again.
+	SaveValidators(source entity.Source, validators Validators)
+}
+
+// Feed downloaded from a source with the hints on when to poll it next.
+type Feed struct {
+	News  []entity.News
+	Hints Hints
+	// Validators of the download, saved with SaveValidators once the news are stored.
+	Validators Validators
 }
 
+// Hints of a source on when it should be polled next.
+type Hints struct {
+	// TTL of the feed, it should not be polled more often.
+	TTL time.Duration
+	// SkipHours are the UTC hours the feed should not be polled.
+	SkipHours []int
+	// RetryAfter is the delay requested by the server with a Retry-After header.
+	RetryAfter time.Duration
+}
+
+// ErrNotModified is returned by FetchFeed when the feed did not change since the last download.
+var ErrNotModified = errors.New("feed not modified")
+
 // UrlFeed implements the FeedManager for fetching feeds from URLs.
+// With a Cache, requests are conditional on the validators of the previous
+// download and unchanged feeds are not parsed again.
 type UrlFeed struct {
+	Cache FeedCache
 }
 
-// FetchFeed downloads and parses the news feed from the given URL.
-func (f UrlFeed) FetchFeed(path string) ([]entity.News, error) {
-	resp, err := http.Get(path)
+// FetchFeed downloads and parses the news feed of the given source.
+// Pages of sources with a scrape profile are parsed according to the profile.
+// The download is aborted when ctx is cancelled.
+// ErrNotModified is returned if the server answers 304 Not Modified
+// or the content is the same as in the previous download.
+// Hints are returned along with ErrNotModified and download errors too.
+// The validators of a parsed feed are not saved until SaveValidators is called.
+func (f UrlFeed) FetchFeed(ctx context.Context, source entity.Source) (Feed, error) {
+	path := string(source.PathToFile)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		log.Printf("Failed to create request for %s: %v", path, err)
+		return Feed{}, err
+	}
+	previous := f.validators(source)
+	if previous.ETag != "" {
+		req.Header.Set("If-None-Match", previous.ETag)
+	}
+	if previous.LastModified != "" {
+		req.Header.Set("If-Modified-Since", previous.LastModified)
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		log.Println("Failed to download feed", http.StatusInternalServerError)
-		return nil, err
+		return Feed{}, err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -36,61 +87,94 @@ func (f UrlFeed) FetchFeed(path string) ([]entity.News, error) {
 			return
 		}
 	}(resp.Body)
-	contentType := resp.Header.Get("Content-Type")
-	ext := getContentExt(contentType)
-	tempFileName := fileName + ext
-	tempFile, err := os.Create(tempFileName)
+	if resp.StatusCode == http.StatusNotModified {
+		log.Printf("Feed %s not modified", path)
+		return Feed{Hints: previous.hints()}, ErrNotModified
+	}
+	if resp.StatusCode != http.StatusOK {
+		hints := Hints{RetryAfter: retryAfter(resp.Header.Get("Retry-After"), time.Now())}
+		return Feed{Hints: hints}, fmt.Errorf("failed to download feed %s, status code: %d", path, resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("Failed to create temporary file: %v", err)
-		return nil, err
+		log.Printf("Failed to read feed from %s: %v", path, err)
+		return Feed{}, err
 	}
-	if _, err := io.Copy(tempFile, resp.Body); err != nil {
-		log.Printf("Failed to write response to file: %v", err)
-		return nil, err
+	rssHints := parser.RssHints(bytes.NewReader(body))
+	current := Validators{
+		URL:          path,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentHash:  contentHash(body, source.Scrape),
+		CheckedAt:    time.Now().UTC(),
+		TTL:          rssHints.TTL,
+		SkipHours:    rssHints.SkipHours,
 	}
-	err = tempFile.Close()
+	if previous.ContentHash != "" && previous.ContentHash == current.ContentHash {
+		log.Printf("Feed %s content unchanged", path)
+		f.SaveValidators(source, current)
+		return Feed{Hints: current.hints()}, ErrNotModified
+	}
+	feed, err := parser.Parse(ctx, bytes.NewReader(body), parser.Meta{
+		SourceName:  string(source.Name),
+		Location:    path,
+		ContentType: resp.Header.Get("Content-Type"),
+		Profile:     source.Scrape,
+	})
 	if err != nil {
-		log.Printf("Failed to close temporary file: %v", err)
-		return nil, err
+		log.Printf("Failed to parse feed from %s: %v", path, err)
+		return Feed{}, err
 	}
+	return Feed{News: feed, Hints: current.hints(), Validators: current}, nil
+}
 
-	feed, err := getFeedFromFile(tempFileName)
-	if err != nil {
-		log.Printf("Failed to parse feed from file: %v", err)
-		return nil, err
+// validators of the previous download of the source,
+// they are ignored once the source URL changes.
+func (f UrlFeed) validators(source entity.Source) Validators {
+	if f.Cache == nil {
+		return Validators{}
 	}
-	err = os.Remove(tempFileName)
+	v, err := f.Cache.GetValidators(string(source.Name))
 	if err != nil {
-		log.Printf("Error removing temporary file %s: %v", tempFileName, err)
-		return nil, err
+		log.Printf("Failed to read feed cache for %s: %v", source.Name, err)
+		return Validators{}
 	}
-	return feed, nil
+	if v.URL != string(source.PathToFile) {
+		return Validators{}
+	}
+	return v
 }
 
-// getFeedFromFile using parsers.
-func getFeedFromFile(filePath string) ([]entity.News, error) {
-	p, err := parser.GetFileParser(entity.PathToFile(filePath))
-	if err != nil {
-		log.Printf("Error getting file parser for %s: %v", filePath, err)
-		return nil, err
+// SaveValidators of the source, failures only cost a full download next time.
+func (f UrlFeed) SaveValidators(source entity.Source, v Validators) {
+	if f.Cache == nil {
+		return
 	}
-	f, err := p.Parse()
-	if err != nil {
-		log.Printf("Error parsing file %s: %v", filePath, err)
-		return nil, err
+	if err := f.Cache.SetValidators(string(source.Name), v); err != nil {
+		log.Printf("Failed to update feed cache for %s: %v", source.Name, err)
 	}
-	return f, err
 }
 
-// getContentExt returns the corresponding file extension based on the content type
-func getContentExt(contentType string) string {
-	if strings.Contains(contentType, "application/json") {
-		return ".json"
-	} else if strings.Contains(contentType, "application/rss+xml") ||
-		strings.Contains(contentType, "text/xml") {
-		return ".xml"
-	} else if strings.Contains(contentType, "text/html") {
-		return ".html"
-	}
-	return ""
+// contentHash of the feed body together with the scrape profile it is parsed with.
+func contentHash(body []byte, profile *entity.ScrapeProfile) string {
+	h := sha256.New()
+	h.Write(body)
+	if profile != nil {
+		_ = json.NewEncoder(h).Encode(profile)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
 }
@@ -0,0 +1,104 @@
+package managers
+
+import (
+	"encoding/json"
+	"log"
+	"news-aggregator/internal/safefile"
+	"os"
+	"sync"
+	"time"
+)
+
+// Validators of the last successful download of a source feed,
+// used to make conditional requests and to skip unchanged content.
+type Validators struct {
+	URL          string
+	ETag         string    `json:",omitempty"`
+	LastModified string    `json:",omitempty"`
+	ContentHash  string    `json:",omitempty"`
+	CheckedAt    time.Time `json:",omitempty"`
+	// TTL and SkipHours hints of the feed, kept for unchanged downloads.
+	TTL       time.Duration `json:",omitempty"`
+	SkipHours []int         `json:",omitempty"`
+}
+
+// hints of the feed the validators were stored for.
+func (v Validators) hints() Hints {
+	return Hints{TTL: v.TTL, SkipHours: v.SkipHours}
+}
+
+// FeedCache provides API for storing feed validators per source.
+//
+//go:generate mockgen -source=feed_cache.go -destination=mock_managers/mock_feed_cache.go
+type FeedCache interface {
+	GetValidators(sourceName string) (Validators, error)
+	SetValidators(sourceName string, validators Validators) error
+}
+
+// feedCacheFile implements FeedCache storing validators of all sources in a JSON file.
+type feedCacheFile struct {
+	mu   sync.Mutex
+	path string
+}
+
+// CreateFeedCache stored in the specified file.
+func CreateFeedCache(pathToCache string) FeedCache {
+	return &feedCacheFile{path: pathToCache}
+}
+
+// GetValidators of the source, empty validators are returned for unknown sources.
+func (cache *feedCacheFile) GetValidators(sourceName string) (Validators, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	entries, err := cache.read()
+	if err != nil {
+		return Validators{}, err
+	}
+	return entries[sourceName], nil
+}
+
+// SetValidators of the source.
+func (cache *feedCacheFile) SetValidators(sourceName string, validators Validators) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	lock, err := safefile.LockFile(cache.path)
+	if err != nil {
+		log.Printf("Error locking feed cache: %v", err)
+		return err
+	}
+	defer lock.Unlock()
+	entries, err := cache.read()
+	if err != nil {
+		return err
+	}
+	entries[sourceName] = validators
+	jsonData, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		log.Printf("Error marshalling feed cache: %v", err)
+		return err
+	}
+	err = safefile.WriteFile(cache.path, jsonData, 0644)
+	if err != nil {
+		log.Printf("Error writing feed cache: %v", err)
+		return err
+	}
+	return nil
+}
+
+// read the cache file, a missing file is an empty cache.
+func (cache *feedCacheFile) read() (map[string]Validators, error) {
+	entries := make(map[string]Validators)
+	jsonData, err := os.ReadFile(cache.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		log.Printf("Error reading feed cache: %v", err)
+		return nil, err
+	}
+	if err := json.Unmarshal(jsonData, &entries); err != nil {
+		log.Printf("Error decoding feed cache %s: %v", cache.path, err)
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,40 @@
+package managers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFeedCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed_cache.json")
+	cache := CreateFeedCache(path)
+
+	result, err := cache.GetValidators("source1")
+	assert.Nil(t, err, "Expected no error for a missing cache file")
+	assert.Equal(t, Validators{}, result, "Expected empty validators")
+
+	validators := Validators{
+		URL:          "https://example.com/rss",
+		ETag:         `"v1"`,
+		LastModified: "Mon, 01 Jan 2001 00:00:00 GMT",
+		ContentHash:  "abc",
+		CheckedAt:    time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	err = cache.SetValidators("source1", validators)
+	assert.Nil(t, err, "Expected no error")
+
+	result, err = CreateFeedCache(path).GetValidators("source1")
+	assert.Nil(t, err, "Expected no error")
+	assert.Equal(t, validators, result, "Expected validators to match")
+}
+
+func TestFeedCacheCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed_cache.json")
+	assert.Nil(t, os.WriteFile(path, []byte("not json"), 0644))
+
+	_, err := CreateFeedCache(path).GetValidators("source1")
+	assert.Error(t, err, "Expected error for a corrupted cache file")
+}
@@ -1,9 +1,12 @@
 package managers
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"news-aggregator/internal/entity"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
@@ -29,16 +32,60 @@ func TestUrlFeed_Fetch(t *testing.T) {
 	}))
 	defer mockServer.Close()
 
+	plainServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+		<feed xmlns="http://www.w3.org/2005/Atom">
+			<title>Mock Atom</title>
+			<entry>
+				<title>Mock Title</title>
+				<link href="https://mock.link"/>
+				<updated>2001-01-01T00:00:00Z</updated>
+			</entry>
+		</feed>`))
+	}))
+	defer plainServer.Close()
+
+	unknownServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("plain text"))
+	}))
+	defer unknownServer.Close()
+
 	tests := []struct {
 		name    string
 		url     string
+		want    []entity.News
 		wantErr bool
 	}{
 		{
-			name:    "Valid URL",
-			url:     mockServer.URL,
+			name: "Valid URL",
+			url:  mockServer.URL,
+			want: []entity.News{{
+				Title:       "Mock Title",
+				Description: "Mock Description",
+				Link:        "https://mock.link",
+				Date:        time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC),
+				Source:      "Mock News",
+			}},
+			wantErr: false,
+		},
+		{
+			name: "Feed served with generic content type",
+			url:  plainServer.URL,
+			want: []entity.News{{
+				Title:  "Mock Title",
+				Link:   "https://mock.link",
+				Date:   time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC),
+				Source: "Mock Atom",
+			}},
 			wantErr: false,
 		},
+		{
+			name:    "Unknown format",
+			url:     unknownServer.URL,
+			wantErr: true,
+		},
 		{
 			name:    "Invalid URL",
 			url:     "http://invalid.url",
@@ -49,70 +96,131 @@ func TestUrlFeed_Fetch(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			f := UrlFeed{}
-			_, err := f.FetchFeed(tt.url)
+			got, err := f.FetchFeed(context.Background(), entity.Source{Name: "mock", PathToFile: entity.PathToFile(tt.url)})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UrlFeed.FeedManager() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if !tt.wantErr && !reflect.DeepEqual(got.News, tt.want) {
+				t.Errorf("UrlFeed.FeedManager() got = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
-func TestGetFeedFromFile(t *testing.T) {
-	existingFilePath := "../../internal/testdata/news.json"
-	invalidFilePath := "../../internal/testdata/invalid.json"
+
+func TestUrlFeed_FetchConditional(t *testing.T) {
+	const feed = `<?xml version="1.0" encoding="UTF-8"?>
+		<rss version="2.0"><channel><title>Mock News</title>
+		<item><title>Mock Title</title><link>https://mock.link</link>
+		<pubDate>Mon, 01 Jan 2001 00:00:00 +0000</pubDate></item>
+		</channel></rss>`
+
+	etagServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(feed))
+	}))
+	defer etagServer.Close()
+
+	hashServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(feed))
+	}))
+	defer hashServer.Close()
 
 	tests := []struct {
-		name     string
-		filePath string
-		want     []entity.News
-		wantErr  bool
+		name string
+		url  string
 	}{
-		{
-			name:     "Valid File",
-			filePath: existingFilePath,
-			want: []entity.News{
-				{
-					Title:       "Container ship that struck Baltimore bridge will be removed from the site 'within days,' Maryland governor says",
-					Description: "Ship that struck Francis Scott Key Bridge in Baltimore will be removed \"within days,\" Maryland Gov. Wes Moore says",
-					Link:        "https://www.nbcnews.com/politics/politics-news/francis-scott-key-bridge-ship-removal-wes-moore-baltimore-rcna152955",
-					Date:        time.Date(2024, 5, 19, 14, 6, 47, 0, time.UTC),
-					Source:      "NBC News",
-				},
-				{
-					Title:       "Harris says more Indian American representation is needed in government",
-					Description: "Addressing a crowd of Indian Americans this week, Vice President Kamala Harris asserted the importance of voting and running. But Biden and Harris approval among the group has fallen.",
-					Link:        "https://www.nbcnews.com/news/asian-america/kamala-harris-more-indian-american-representation-needed-government-rcna152761",
-					Date:        time.Date(2024, 5, 17, 19, 48, 19, 0, time.UTC),
-					Source:      "NBC News",
-				},
-				{
-					Title:       "Atlanta officer accused of killing Lyft driver allegedly said victim was ‘gay fraternity’ recruiter",
-					Description: "An Atlanta police officer accused of murdering a Lyft driver allegedly said the victim was in a gay fraternity trying to recruit him.",
-					Link:        "https://www.nbcnews.com/nbc-out/out-news/atlanta-officer-accused-killing-lyft-driver-allegedly-said-victim-was-rcna152751",
-					Date:        time.Date(2024, 5, 17, 14, 29, 43, 0, time.UTC),
-					Source:      "NBC News",
-				},
-			},
-			wantErr: false,
-		},
-		{
-			name:     "Invalid File",
-			filePath: invalidFilePath,
-			want:     []entity.News{},
-			wantErr:  true,
-		},
+		{name: "ETag", url: etagServer.URL},
+		{name: "Content hash", url: hashServer.URL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := UrlFeed{Cache: CreateFeedCache(filepath.Join(t.TempDir(), "feed_cache.json"))}
+			source := entity.Source{Name: "mock", PathToFile: entity.PathToFile(tt.url)}
+
+			got, err := f.FetchFeed(context.Background(), source)
+			if err != nil || len(got.News) != 1 {
+				t.Fatalf("first FetchFeed() got = %v, err = %v", got, err)
+			}
+			got, err = f.FetchFeed(context.Background(), source)
+			if err != nil || len(got.News) != 1 {
+				t.Fatalf("FetchFeed() before SaveValidators got = %v, err = %v", got, err)
+			}
+			f.SaveValidators(source, got.Validators)
+			_, err = f.FetchFeed(context.Background(), source)
+			if !errors.Is(err, ErrNotModified) {
+				t.Errorf("second FetchFeed() error = %v, want ErrNotModified", err)
+			}
+
+			source.PathToFile = entity.PathToFile(tt.url + "/moved")
+			got, err = f.FetchFeed(context.Background(), source)
+			if err != nil || len(got.News) != 1 {
+				t.Errorf("FetchFeed() after URL change got = %v, err = %v", got, err)
+			}
+		})
+	}
+}
+
+func TestUrlFeed_FetchHints(t *testing.T) {
+	ttlServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+		<rss version="2.0"><channel><title>Mock News</title><ttl>60</ttl>
+		<skipHours><hour>3</hour></skipHours>
+		<item><title>Mock Title</title><link>https://mock.link</link>
+		<pubDate>Mon, 01 Jan 2001 00:00:00 +0000</pubDate></item>
+		</channel></rss>`))
+	}))
+	defer ttlServer.Close()
+
+	busyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer busyServer.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		want    Hints
+		wantErr bool
+	}{
+		{name: "RSS ttl and skip hours", url: ttlServer.URL, want: Hints{TTL: time.Hour, SkipHours: []int{3}}},
+		{name: "Retry-After", url: busyServer.URL, want: Hints{RetryAfter: 2 * time.Minute}, wantErr: true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getFeedFromFile(tt.filePath)
+			got, err := UrlFeed{}.FetchFeed(context.Background(), entity.Source{Name: "mock", PathToFile: entity.PathToFile(tt.url)})
 			if (err != nil) != tt.wantErr {
-				t.Errorf("getFeedFromFile() error = %v, wantErr %v", err, tt.wantErr)
-				return
+				t.Fatalf("FetchFeed() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("getFeedFromFile() got = %v, want %v", got, tt.want)
+			if !reflect.DeepEqual(got.Hints, tt.want) {
+				t.Errorf("FetchFeed() hints = %v, want %v", got.Hints, tt.want)
 			}
 		})
 	}
 }
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "90", want: 90 * time.Second},
+		{value: "Mon, 01 Jan 2001 00:05:00 GMT", want: 5 * time.Minute},
+		{value: "Sun, 31 Dec 2000 23:00:00 GMT", want: 0},
+		{value: "soon", want: 0},
+	}
+	for _, tt := range tests {
+		if got := retryAfter(tt.value, now); got != tt.want {
+			t.Errorf("retryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -5,7 +5,9 @@
 package mock_managers
 
 import (
+	context "context"
 	entity "news-aggregator/internal/entity"
+	managers "news-aggregator/server/managers"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,16 +37,28 @@ func (m *MockFeedManager) EXPECT() *MockFeedManagerMockRecorder {
 }
 
 // FetchFeed mocks base method.
-func (m *MockFeedManager) FetchFeed(path string) ([]entity.News, error) {
+func (m *MockFeedManager) FetchFeed(ctx context.Context, source entity.Source) (managers.Feed, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchFeed", path)
-	ret0, _ := ret[0].([]entity.News)
+	ret := m.ctrl.Call(m, "FetchFeed", ctx, source)
+	ret0, _ := ret[0].(managers.Feed)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FetchFeed indicates an expected call of FetchFeed.
-func (mr *MockFeedManagerMockRecorder) FetchFeed(path interface{}) *gomock.Call {
+func (mr *MockFeedManagerMockRecorder) FetchFeed(ctx, source interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchFeed", reflect.TypeOf((*MockFeedManager)(nil).FetchFeed), path)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchFeed", reflect.TypeOf((*MockFeedManager)(nil).FetchFeed), ctx, source)
+}
+
+// SaveValidators mocks base method.
+func (m *MockFeedManager) SaveValidators(source entity.Source, validators managers.Validators) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SaveValidators", source, validators)
+}
+
+// SaveValidators indicates an expected call of SaveValidators.
+func (mr *MockFeedManagerMockRecorder) SaveValidators(source, validators interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveValidators", reflect.TypeOf((*MockFeedManager)(nil).SaveValidators), source, validators)
 }
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: feed_cache.go
+
+// Package mock_managers is a generated GoMock package.
+package mock_managers
+
+import (
+	managers "news-aggregator/server/managers"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFeedCache is a mock of FeedCache interface.
+type MockFeedCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedCacheMockRecorder
+}
+
+// MockFeedCacheMockRecorder is the mock recorder for MockFeedCache.
+type MockFeedCacheMockRecorder struct {
+	mock *MockFeedCache
+}
+
+// NewMockFeedCache creates a new mock instance.
+func NewMockFeedCache(ctrl *gomock.Controller) *MockFeedCache {
+	mock := &MockFeedCache{ctrl: ctrl}
+	mock.recorder = &MockFeedCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedCache) EXPECT() *MockFeedCacheMockRecorder {
+	return m.recorder
+}
+
+// GetValidators mocks base method.
+func (m *MockFeedCache) GetValidators(sourceName string) (managers.Validators, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValidators", sourceName)
+	ret0, _ := ret[0].(managers.Validators)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetValidators indicates an expected call of GetValidators.
+func (mr *MockFeedCacheMockRecorder) GetValidators(sourceName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidators", reflect.TypeOf((*MockFeedCache)(nil).GetValidators), sourceName)
+}
+
+// SetValidators mocks base method.
+func (m *MockFeedCache) SetValidators(sourceName string, validators managers.Validators) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetValidators", sourceName, validators)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetValidators indicates an expected call of SetValidators.
+func (mr *MockFeedCacheMockRecorder) SetValidators(sourceName, validators interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetValidators", reflect.TypeOf((*MockFeedCache)(nil).SetValidators), sourceName, validators)
+}
@@ -5,7 +5,9 @@
 package mock_managers
 
 import (
+	context "context"
 	entity "news-aggregator/internal/entity"
+	managers "news-aggregator/server/managers"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,11 +37,12 @@ func (m *MockNewsManager) EXPECT() *MockNewsManagerMockRecorder {
 }
 
 // AddNews mocks base method.
-func (m *MockNewsManager) AddNews(newsToAdd []entity.News, newsSource string) error {
+func (m *MockNewsManager) AddNews(newsToAdd []entity.News, newsSource string) ([]entity.News, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddNews", newsToAdd, newsSource)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].([]entity.News)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // AddNews indicates an expected call of AddNews.
@@ -63,17 +66,47 @@ func (mr *MockNewsManagerMockRecorder) GetNewsFromFolder(folderName interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsFromFolder", reflect.TypeOf((*MockNewsManager)(nil).GetNewsFromFolder), folderName)
 }
 
-// GetNewsSourceFilePath mocks base method.
-func (m *MockNewsManager) GetNewsSourceFilePath(sourceName []string) (map[string][]string, error) {
+// ListSources mocks base method.
+func (m *MockNewsManager) ListSources() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSources")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSources indicates an expected call of ListSources.
+func (mr *MockNewsManagerMockRecorder) ListSources() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSources", reflect.TypeOf((*MockNewsManager)(nil).ListSources))
+}
+
+// Prune mocks base method.
+func (m *MockNewsManager) Prune(ctx context.Context, source string, options managers.PruneOptions) (managers.PruneResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", ctx, source, options)
+	ret0, _ := ret[0].(managers.PruneResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockNewsManagerMockRecorder) Prune(ctx, source, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockNewsManager)(nil).Prune), ctx, source, options)
+}
+
+// Query mocks base method.
+func (m *MockNewsManager) Query(ctx context.Context, query managers.NewsQuery) (managers.NewsPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNewsSourceFilePath", sourceName)
-	ret0, _ := ret[0].(map[string][]string)
+	ret := m.ctrl.Call(m, "Query", ctx, query)
+	ret0, _ := ret[0].(managers.NewsPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetNewsSourceFilePath indicates an expected call of GetNewsSourceFilePath.
-func (mr *MockNewsManagerMockRecorder) GetNewsSourceFilePath(sourceName interface{}) *gomock.Call {
+// Query indicates an expected call of Query.
+func (mr *MockNewsManagerMockRecorder) Query(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsSourceFilePath", reflect.TypeOf((*MockNewsManager)(nil).GetNewsSourceFilePath), sourceName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockNewsManager)(nil).Query), ctx, query)
 }
@@ -34,6 +34,21 @@ func (m *MockSourceManager) EXPECT() *MockSourceManagerMockRecorder {
 	return m.recorder
 }
 
+// AddSource mocks base method.
+func (m *MockSourceManager) AddSource(source entity.Source) (entity.Source, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSource", source)
+	ret0, _ := ret[0].(entity.Source)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddSource indicates an expected call of AddSource.
+func (mr *MockSourceManagerMockRecorder) AddSource(source interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSource", reflect.TypeOf((*MockSourceManager)(nil).AddSource), source)
+}
+
 // CreateSource mocks base method.
 func (m *MockSourceManager) CreateSource(name, url string) (entity.Source, error) {
 	m.ctrl.T.Helper()
@@ -93,6 +108,76 @@ func (mr *MockSourceManagerMockRecorder) RemoveSourceByName(sourceName interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSourceByName", reflect.TypeOf((*MockSourceManager)(nil).RemoveSourceByName), sourceName)
 }
 
+// RemoveSourceIfMatch mocks base method.
+func (m *MockSourceManager) RemoveSourceIfMatch(name, ifMatch string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSourceIfMatch", name, ifMatch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSourceIfMatch indicates an expected call of RemoveSourceIfMatch.
+func (mr *MockSourceManagerMockRecorder) RemoveSourceIfMatch(name, ifMatch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSourceIfMatch", reflect.TypeOf((*MockSourceManager)(nil).RemoveSourceIfMatch), name, ifMatch)
+}
+
+// SetInterval mocks base method.
+func (m *MockSourceManager) SetInterval(name string, interval entity.Interval) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInterval", name, interval)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInterval indicates an expected call of SetInterval.
+func (mr *MockSourceManagerMockRecorder) SetInterval(name, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInterval", reflect.TypeOf((*MockSourceManager)(nil).SetInterval), name, interval)
+}
+
+// SetLanguage mocks base method.
+func (m *MockSourceManager) SetLanguage(name, language string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLanguage", name, language)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLanguage indicates an expected call of SetLanguage.
+func (mr *MockSourceManagerMockRecorder) SetLanguage(name, language interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLanguage", reflect.TypeOf((*MockSourceManager)(nil).SetLanguage), name, language)
+}
+
+// SetRetention mocks base method.
+func (m *MockSourceManager) SetRetention(name string, retention *entity.Retention) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRetention", name, retention)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRetention indicates an expected call of SetRetention.
+func (mr *MockSourceManagerMockRecorder) SetRetention(name, retention interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRetention", reflect.TypeOf((*MockSourceManager)(nil).SetRetention), name, retention)
+}
+
+// SetScrapeProfile mocks base method.
+func (m *MockSourceManager) SetScrapeProfile(name string, profile *entity.ScrapeProfile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetScrapeProfile", name, profile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetScrapeProfile indicates an expected call of SetScrapeProfile.
+func (mr *MockSourceManagerMockRecorder) SetScrapeProfile(name, profile interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScrapeProfile", reflect.TypeOf((*MockSourceManager)(nil).SetScrapeProfile), name, profile)
+}
+
 // UpdateSource mocks base method.
 func (m *MockSourceManager) UpdateSource(name, newUrl string) error {
 	m.ctrl.T.Helper()
@@ -106,3 +191,18 @@ func (mr *MockSourceManagerMockRecorder) UpdateSource(name, newUrl interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSource", reflect.TypeOf((*MockSourceManager)(nil).UpdateSource), name, newUrl)
 }
+
+// UpdateSourceIfMatch mocks base method.
+func (m *MockSourceManager) UpdateSourceIfMatch(name, ifMatch string, change func(*entity.Source)) (entity.Source, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSourceIfMatch", name, ifMatch, change)
+	ret0, _ := ret[0].(entity.Source)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSourceIfMatch indicates an expected call of UpdateSourceIfMatch.
+func (mr *MockSourceManagerMockRecorder) UpdateSourceIfMatch(name, ifMatch, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSourceIfMatch", reflect.TypeOf((*MockSourceManager)(nil).UpdateSourceIfMatch), name, ifMatch, change)
+}
@@ -1,24 +1,44 @@
 package managers
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"log"
+	"news-aggregator/internal/canonical"
 	"news-aggregator/internal/entity"
+	"news-aggregator/internal/safefile"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
-var timeNow = time.Now().Format("2006-01-02")
+// timeNow is the clock naming the daily news files, replaced in tests.
+var timeNow = time.Now
+
+const (
+	// dayLayout names the daily news files and monthLayout their archives.
+	dayLayout   = "2006-01-02"
+	monthLayout = "2006-01"
+	// archiveExt is the extension of the gzip compressed monthly archives.
+	archiveExt = ".json.gz"
+)
 
 // NewsManager provides API for handling news data.
 //
 //go:generate mockgen -source=news.go -destination=mock_managers/mock_news.go
 type NewsManager interface {
-	AddNews(newsToAdd []entity.News, newsSource string) error
+	AddNews(newsToAdd []entity.News, newsSource string) ([]entity.News, error)
 	GetNewsFromFolder(folderName string) ([]entity.News, error)
-	GetNewsSourceFilePath(sourceName []string) (map[string][]string, error)
+	ListSources() ([]string, error)
+	Query(ctx context.Context, query NewsQuery) (NewsPage, error)
+	Prune(ctx context.Context, source string, options PruneOptions) (PruneResult, error)
 }
 
 // newsFolder implements the NewsManager for managing news data stored in folders.
@@ -32,43 +52,73 @@ func CreateNewsFolder(pathToNews string) NewsManager {
 }
 
 // AddNews in JSON format in the server's news folder,
-// organized by source and timestamp.
-func (folder newsFolder) AddNews(newsToAdd []entity.News, newsSource string) error {
-	finalFileName := fmt.Sprintf("%s/%s.json", newsSource, timeNow)
+// organized by source and the UTC day they are added. News with the canonical link of a news
+// stored for any source are skipped, the added news are returned. The file is replaced
+// atomically holding the locks of the links and of the source, which other processes
+// sharing the folder respect.
+func (folder newsFolder) AddNews(newsToAdd []entity.News, newsSource string) ([]entity.News, error) {
+	finalFileName := fmt.Sprintf("%s/%s.json", newsSource, timeNow().UTC().Format(dayLayout))
 	finalFilePath := filepath.Join(folder.path, finalFileName)
 	err := os.MkdirAll(filepath.Dir(finalFilePath), 0755)
 	log.Printf("Final file path: %s", finalFilePath)
 	if err != nil {
 		log.Printf("Error creating directory: %v", err)
-		return fmt.Errorf("failed to create directory: %w", err)
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	linksLock, err := folder.lockLinks()
+	if err != nil {
+		log.Printf("Error locking links of news: %v", err)
+		return nil, fmt.Errorf("failed to lock links: %w", err)
+	}
+	defer linksLock.Unlock()
+	links, err := folder.loadLinks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load links: %w", err)
+	}
+	lock, err := folder.lock(newsSource, true)
+	if err != nil {
+		log.Printf("Error locking news of %s: %v", newsSource, err)
+		return nil, fmt.Errorf("failed to lock news: %w", err)
 	}
+	defer lock.Unlock()
 	currentNews, err := loadNewsFromFile(finalFilePath)
 	if err != nil {
 		if !os.IsNotExist(err) {
 			log.Printf("Error loading current news: %v", err)
-			return fmt.Errorf("failed to load current news: %w", err)
+			return nil, fmt.Errorf("failed to load current news: %w", err)
 		}
 		log.Printf("File does not exist, сreate a new file ...")
 		currentNews = []entity.News{}
 	}
-	currentNews = append(currentNews, newsToAdd...)
-	jsonData, err := json.Marshal(currentNews)
-	if err != nil {
-		log.Printf("Error marshalling news to JSON: %v", err)
-		return fmt.Errorf("failed to marshal news to JSON: %w", err)
+	added := make([]entity.News, 0, len(newsToAdd))
+	for _, item := range newsToAdd {
+		if links.add(item, newsSource) {
+			added = append(added, item)
+		}
+	}
+	if len(added) == 0 {
+		return added, nil
 	}
-	err = os.WriteFile(finalFilePath, jsonData, 0644)
+	err = folder.changeLinks(links, func() error {
+		return writeNewsFile(finalFilePath, append(currentNews, added...))
+	})
 	if err != nil {
 		log.Printf("Error writing news to file: %v", err)
-		return fmt.Errorf("failed to write news to file: %w", err)
+		return nil, fmt.Errorf("failed to write news to file: %w", err)
 	}
-	log.Printf("Successfully added %d news items to %s", len(newsToAdd), finalFilePath)
-	return nil
+	log.Printf("Successfully added %d news items to %s", len(added), finalFilePath)
+	return added, nil
 }
 
 // GetNewsFromFolder retrieves news data from a specified folder
-// containing structured news resources.
+// containing structured news resources, daily files and monthly archives alike.
 func (folder newsFolder) GetNewsFromFolder(folderName string) ([]entity.News, error) {
+	lock, err := folder.lock(folderName, false)
+	if err != nil {
+		log.Printf("Error locking news of %s: %v", folderName, err)
+		return nil, err
+	}
+	defer lock.Unlock()
 	sourcePath := filepath.Join(folder.path, folderName)
 	resources, err := getNewsSources(sourcePath)
 	if err != nil {
@@ -76,19 +126,8 @@ func (folder newsFolder) GetNewsFromFolder(folderName string) ([]entity.News, er
 	}
 	allNews := make([]entity.News, 0)
 	for _, path := range resources {
-		file, err := os.Open(path)
+		articles, err := loadNewsFromFile(path)
 		if err != nil {
-			log.Printf("Failed to open file %s: %v", path, err)
-			return nil, fmt.Errorf("failed to open file: %w", err)
-		}
-		defer func(file *os.File) {
-			closeErr := file.Close()
-			if closeErr != nil && err == nil {
-				err = fmt.Errorf("error closing file: %w", closeErr)
-			}
-		}(file)
-		var articles []entity.News
-		if err := json.NewDecoder(file).Decode(&articles); err != nil {
 			log.Printf("Error decoding file %s: %v", path, err)
 			return nil, err
 		}
@@ -97,27 +136,235 @@ func (folder newsFolder) GetNewsFromFolder(folderName string) ([]entity.News, er
 	return allNews, nil
 }
 
-// GetNewsSourceFilePath provides the file paths associated with each news source,
-// helping in locating where news data for each source is stored.
-func (folder newsFolder) GetNewsSourceFilePath(sourceName []string) (map[string][]string, error) {
-	resources := make(map[string][]string)
-	for _, s := range sourceName {
-		sourcePath := filepath.Join(folder.path, s)
-		paths, err := getNewsSources(sourcePath)
+// ListSources with a news folder.
+func (folder newsFolder) ListSources() ([]string, error) {
+	entries, err := os.ReadDir(folder.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error reading news folder: %v", err)
+		return nil, err
+	}
+	var sources []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			sources = append(sources, entry.Name())
+		}
+	}
+	return sources, nil
+}
+
+// Query the news of the sources folders, the folders are read whole
+// and the matching news sorted in memory.
+func (folder newsFolder) Query(ctx context.Context, query NewsQuery) (NewsPage, error) {
+	match := query.matcher()
+	var items []storedNews
+	for _, source := range query.Sources {
+		if err := ctx.Err(); err != nil {
+			return NewsPage{}, err
+		}
+		news, err := folder.GetNewsFromFolder(source)
+		if err != nil {
+			return NewsPage{}, err
+		}
+		for _, n := range news {
+			if item := (storedNews{source: source, news: n}); match(item) {
+				items = append(items, item)
+			}
+		}
+	}
+	return query.page(items)
+}
+
+// revision of the news of the source folder from the names, sizes and modification times
+// of its files. Files are replaced on every change, which changes the revision.
+func (folder newsFolder) revision(source string) (string, error) {
+	entries, err := os.ReadDir(filepath.Join(folder.path, source))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		log.Printf("Error reading news folder of %s: %v", source, err)
+		return "", err
+	}
+	h := fnv.New64a()
+	for _, entry := range entries {
+		if entry.IsDir() || !isNewsFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s %d %d\n", entry.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return strconv.FormatUint(h.Sum64(), 16), nil
+}
+
+// lookup the stored news by their ids, the folders of their sources are read whole.
+// Ids of deleted news are skipped.
+func (folder newsFolder) lookup(ids map[string]bool) ([]storedNews, error) {
+	sources := make(map[string]bool)
+	for id := range ids {
+		source, _, _ := strings.Cut(id, keySeparator)
+		sources[source] = true
+	}
+	var items []storedNews
+	for source := range sources {
+		news, err := folder.GetNewsFromFolder(source)
 		if err != nil {
-			log.Print("Not found news")
 			return nil, err
 		}
-		if len(paths) != 0 {
-			resources[s] = paths
-			log.Printf("Found news paths for %s", s)
+		found := make(map[entity.Link]bool)
+		for _, item := range news {
+			if ids[string(newsKey(source, item.Link))] && !found[item.Link] {
+				found[item.Link] = true
+				items = append(items, storedNews{source: source, news: item})
+			}
+		}
+	}
+	return items, nil
+}
+
+// Prune the news of the source folder by the retention: files left without news are removed
+// and the others rewritten. Daily files dated before ArchiveBefore are then compacted into
+// the archive of their month, the archive is written before the daily files are removed.
+func (folder newsFolder) Prune(ctx context.Context, source string, options PruneOptions) (PruneResult, error) {
+	var links canonicalLinks
+	if !options.DryRun {
+		linksLock, err := folder.lockLinks()
+		if err != nil {
+			log.Printf("Error locking links of news: %v", err)
+			return PruneResult{}, err
+		}
+		defer linksLock.Unlock()
+		if links, err = folder.readLinks(); err != nil {
+			return PruneResult{}, err
+		}
+	}
+	lock, err := folder.lock(source, !options.DryRun)
+	if err != nil {
+		log.Printf("Error locking news of %s: %v", source, err)
+		return PruneResult{}, err
+	}
+	defer lock.Unlock()
+	paths, err := getNewsSources(filepath.Join(folder.path, source))
+	if err != nil {
+		log.Printf("Error reading news folder of %s: %v", source, err)
+		return PruneResult{}, err
+	}
+	files := make(map[string][]entity.News, len(paths))
+	var all []entity.News
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return PruneResult{}, err
+		}
+		news, err := loadNewsFromFile(path)
+		if err != nil {
+			return PruneResult{}, err
+		}
+		files[path] = news
+		all = append(all, news...)
+	}
+
+	var result PruneResult
+	expired := options.expired(all)
+	changed := make(map[string]bool)
+	i := 0
+	for _, path := range paths {
+		var kept []entity.News
+		for _, item := range files[path] {
+			if expired[i] {
+				result.Deleted = append(result.Deleted, item)
+				changed[path] = true
+			} else {
+				kept = append(kept, item)
+			}
+			i++
+		}
+		files[path] = kept
+	}
+	result.Kept = len(all) - len(result.Deleted)
+
+	for _, path := range paths {
+		day, ok := dailyFileDate(path)
+		if !ok || options.ArchiveBefore.IsZero() || !day.Before(options.ArchiveBefore) {
+			continue
+		}
+		archive := filepath.Join(filepath.Dir(path), day.Format(monthLayout)+archiveExt)
+		files[archive] = appendNew(files[archive], files[path])
+		files[path] = nil
+		changed[archive], changed[path] = true, true
+		result.Archived = append(result.Archived, filepath.Base(path))
+	}
+	if options.DryRun {
+		return result, nil
+	}
+
+	// Archives are written first, a failure in between leaves news in both files
+	// and appendNew drops them from the archive when it is compacted again.
+	write := func() error {
+		var removed []string
+		for path := range changed {
+			if len(files[path]) == 0 {
+				removed = append(removed, path)
+				continue
+			}
+			if err := writeNewsFile(path, files[path]); err != nil {
+				log.Printf("Error writing news file %s: %v", path, err)
+				return err
+			}
+		}
+		for _, path := range removed {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				log.Printf("Error removing news file %s: %v", path, err)
+				return err
+			}
+		}
+		return nil
+	}
+	if links == nil || len(result.Deleted) == 0 {
+		err = write()
+	} else {
+		for _, item := range result.Deleted {
+			links.remove(item, source)
 		}
+		err = folder.changeLinks(links, write)
+	}
+	if err != nil {
+		return PruneResult{}, err
+	}
+	log.Printf("Pruned %d news of %s and archived %d files", len(result.Deleted), source, len(result.Archived))
+	return result, nil
+}
+
+// lock the news folder of the source, exclusively to change its files.
+// The lock file is kept in the news folder next to the folder of the source.
+func (folder newsFolder) lock(source string, exclusive bool) (*safefile.Lock, error) {
+	path := filepath.Join(folder.path, "."+source)
+	if exclusive {
+		return safefile.LockFile(path)
+	}
+	return safefile.RLockFile(path)
+}
+
+// dailyFileDate parses the date of a daily news file named after its day.
+func dailyFileDate(path string) (time.Time, bool) {
+	name, ok := strings.CutSuffix(filepath.Base(path), ".json")
+	if !ok {
+		return time.Time{}, false
 	}
-	return resources, nil
+	day, err := time.Parse(dayLayout, name)
+	return day, err == nil
 }
 
 // getNewsSources analyzes the contents of a given directory.
-// It returns a slice of a full paths to a file .
+// It returns a slice of a full paths to the news files, daily files and archives,
+// leaving out temporary and quarantined files.
 func getNewsSources(sourceName string) ([]string, error) {
 	_, err := os.Stat(sourceName)
 	if os.IsNotExist(err) {
@@ -143,6 +390,9 @@ func getNewsSources(sourceName string) ([]string, error) {
 	}
 	var entries []string
 	for _, f := range files {
+		if f.IsDir() || !isNewsFile(f.Name()) {
+			continue
+		}
 		fullPath := filepath.Join(sourceName, f.Name())
 		entries = append(entries, fullPath)
 	}
@@ -150,13 +400,47 @@ func getNewsSources(sourceName string) ([]string, error) {
 	return entries, nil
 }
 
+// isNewsFile reports if the file name is of a daily news file or an archive.
+func isNewsFile(name string) bool {
+	return !safefile.IsTemp(name) && (strings.HasSuffix(name, ".json") || strings.HasSuffix(name, archiveExt))
+}
+
+// appendNew news to the current ones, skipping those with the canonical link of a current news.
+func appendNew(current, news []entity.News) []entity.News {
+	links := make(map[entity.Link]bool, len(current))
+	for _, item := range current {
+		links[canonical.Link(item)] = true
+	}
+	for _, item := range news {
+		link := canonical.Link(item)
+		if !links[link] {
+			links[link] = true
+			current = append(current, item)
+		}
+	}
+	return current
+}
+
+// loadNewsFromFile of a daily file or, decompressing it, of a monthly archive.
 func loadNewsFromFile(filePath string) ([]entity.News, error) {
-	var news []entity.News
 	jsonData, err := os.ReadFile(filePath)
 	if err != nil {
 		log.Printf("Failed to read file %v", err)
 		return nil, err
 	}
+	return decodeNewsFile(filePath, jsonData)
+}
+
+// decodeNewsFile read from the path, decompressing an archive.
+func decodeNewsFile(filePath string, jsonData []byte) ([]entity.News, error) {
+	var news []entity.News
+	var err error
+	if strings.HasSuffix(filePath, archiveExt) {
+		if jsonData, err = gunzip(jsonData); err != nil {
+			log.Printf("Failed to decompress file %s: %v", filePath, err)
+			return nil, err
+		}
+	}
 	err = json.Unmarshal(jsonData, &news)
 	if err != nil {
 		log.Printf("Failed to unmarshal JSON data from file %s: %v", filePath, err)
@@ -164,3 +448,33 @@ func loadNewsFromFile(filePath string) ([]entity.News, error) {
 	}
 	return news, nil
 }
+
+// writeNewsFile of a daily file or, compressing it, of a monthly archive.
+// The file is replaced atomically, a crash leaves its previous content.
+func writeNewsFile(filePath string, news []entity.News) error {
+	jsonData, err := json.Marshal(news)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(filePath, archiveExt) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(jsonData); err != nil {
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return err
+		}
+		jsonData = buf.Bytes()
+	}
+	return safefile.WriteFile(filePath, jsonData, 0644)
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return io.ReadAll(gz)
+}
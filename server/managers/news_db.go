@@ -0,0 +1,403 @@
+package managers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"news-aggregator/internal/canonical"
+	"news-aggregator/internal/entity"
+	"strconv"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// newsDB implements the NewsManager storing news in the embedded database.
+type newsDB struct {
+	db *bolt.DB
+}
+
+// CreateNewsDB in the given database opened with OpenDB.
+func CreateNewsDB(db *bolt.DB) NewsManager {
+	return newsDB{db}
+}
+
+// AddNews of the source to the database together with their link and date indexes.
+// News already stored for the source, or with the canonical link of a news stored
+// for any source, are skipped. The added news are returned.
+func (n newsDB) AddNews(newsToAdd []entity.News, newsSource string) ([]entity.News, error) {
+	var added []entity.News
+	err := n.db.Update(func(tx *bolt.Tx) error {
+		added = make([]entity.News, 0, len(newsToAdd))
+		news := tx.Bucket(newsBucket)
+		links := tx.Bucket(linksBucket)
+		dates := tx.Bucket(datesBucket)
+		canonicalLinks := tx.Bucket(canonicalBucket)
+		for _, item := range newsToAdd {
+			key := newsKey(newsSource, item.Link)
+			link := []byte(canonical.Link(item))
+			if news.Get(key) != nil || (len(link) > 0 && canonicalLinks.Get(link) != nil) {
+				continue
+			}
+			jsonData, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			if err := news.Put(key, jsonData); err != nil {
+				return err
+			}
+			if err := links.Put(joinKey(string(item.Link), newsSource), key); err != nil {
+				return err
+			}
+			if err := dates.Put(dateKey(item.Date, newsSource, item.Link), key); err != nil {
+				return err
+			}
+			if len(link) > 0 {
+				if err := canonicalLinks.Put(link, key); err != nil {
+					return err
+				}
+			}
+			added = append(added, item)
+		}
+		if len(added) == 0 {
+			return nil
+		}
+		return touchRevision(tx, newsSource)
+	})
+	if err != nil {
+		log.Printf("Error adding news of %s to database: %v", newsSource, err)
+		return nil, err
+	}
+	log.Printf("Successfully added %d news items of %s to database", len(added), newsSource)
+	return added, nil
+}
+
+// GetNewsFromFolder retrieves all news of the source.
+func (n newsDB) GetNewsFromFolder(folderName string) ([]entity.News, error) {
+	allNews := make([]entity.News, 0)
+	err := n.db.View(func(tx *bolt.Tx) error {
+		prefix := []byte(folderName + keySeparator)
+		c := tx.Bucket(newsBucket).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var item entity.News
+			if err := json.Unmarshal(v, &item); err != nil {
+				return err
+			}
+			allNews = append(allNews, item)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error reading news of %s from database: %v", folderName, err)
+		return nil, err
+	}
+	return allNews, nil
+}
+
+// Prune the news of the source by the retention together with their indexes.
+// The database keeps no daily files, ArchiveBefore is ignored.
+func (n newsDB) Prune(ctx context.Context, source string, options PruneOptions) (PruneResult, error) {
+	var result PruneResult
+	prune := func(tx *bolt.Tx) error {
+		var keys [][]byte
+		var all []entity.News
+		prefix := []byte(source + keySeparator)
+		c := tx.Bucket(newsBucket).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var item entity.News
+			if err := json.Unmarshal(v, &item); err != nil {
+				return err
+			}
+			keys = append(keys, bytes.Clone(k))
+			all = append(all, item)
+		}
+		for i, expired := range options.expired(all) {
+			if !expired {
+				result.Kept++
+				continue
+			}
+			result.Deleted = append(result.Deleted, all[i])
+			if !options.DryRun {
+				if err := deleteNews(tx, keys[i], source, all[i]); err != nil {
+					return err
+				}
+			}
+		}
+		if options.DryRun || len(result.Deleted) == 0 {
+			return nil
+		}
+		return touchRevision(tx, source)
+	}
+	var err error
+	if options.DryRun {
+		err = n.db.View(prune)
+	} else {
+		err = n.db.Update(prune)
+	}
+	if err != nil {
+		log.Printf("Error pruning news of %s in database: %v", source, err)
+		return PruneResult{}, err
+	}
+	return result, nil
+}
+
+// deleteNews stored under the key from the news bucket and the indexes.
+// The canonical link is kept when it refers to a news of another source.
+func deleteNews(tx *bolt.Tx, key []byte, source string, item entity.News) error {
+	if err := tx.Bucket(newsBucket).Delete(key); err != nil {
+		return err
+	}
+	if err := tx.Bucket(linksBucket).Delete(joinKey(string(item.Link), source)); err != nil {
+		return err
+	}
+	if err := tx.Bucket(datesBucket).Delete(dateKey(item.Date, source, item.Link)); err != nil {
+		return err
+	}
+	canonicalLinks := tx.Bucket(canonicalBucket)
+	link := []byte(canonical.Link(item))
+	if len(link) > 0 && bytes.Equal(canonicalLinks.Get(link), key) {
+		return canonicalLinks.Delete(link)
+	}
+	return nil
+}
+
+// touchRevision of the news of the source to the ID of the transaction changing them.
+func touchRevision(tx *bolt.Tx, source string) error {
+	return tx.Bucket(revisionsBucket).Put([]byte(source), []byte(strconv.Itoa(tx.ID())))
+}
+
+// revision of the news of the source, it changes with every transaction changing them.
+func (n newsDB) revision(source string) (string, error) {
+	var revision string
+	err := n.db.View(func(tx *bolt.Tx) error {
+		revision = string(tx.Bucket(revisionsBucket).Get([]byte(source)))
+		return nil
+	})
+	return revision, err
+}
+
+// lookup the stored news by their keys in the news bucket, keys of deleted news are skipped.
+func (n newsDB) lookup(ids map[string]bool) ([]storedNews, error) {
+	var items []storedNews
+	err := n.db.View(func(tx *bolt.Tx) error {
+		news := tx.Bucket(newsBucket)
+		for id := range ids {
+			jsonData := news.Get([]byte(id))
+			if jsonData == nil {
+				continue
+			}
+			source, _, _ := strings.Cut(id, keySeparator)
+			item, err := decodeNews(jsonData, source)
+			if err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error looking up news in database: %v", err)
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListSources with stored news.
+func (n newsDB) ListSources() ([]string, error) {
+	var sources []string
+	err := n.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(newsBucket).Cursor()
+		for k, _ := c.First(); k != nil; {
+			source, _, _ := strings.Cut(string(k), keySeparator)
+			sources = append(sources, source)
+			// Skip the other news of the source.
+			k, _ = c.Seek(append([]byte(source), keySeparator[0]+1))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error listing sources of news in database: %v", err)
+		return nil, err
+	}
+	return sources, nil
+}
+
+// Query the news through the date index: only keys within the date range are visited,
+// news of other sources are skipped without decoding them and, when sorting by date,
+// only the news of the page are decoded unless keywords, categories or authors have to be matched.
+func (n newsDB) Query(ctx context.Context, query NewsQuery) (NewsPage, error) {
+	after, err := decodeCursor(query.Cursor)
+	if err != nil {
+		return NewsPage{}, err
+	}
+	sources := make(map[string]bool, len(query.Sources))
+	for _, s := range query.Sources {
+		sources[s] = true
+	}
+	match := query.matcher()
+	page := NewsPage{News: make([]entity.News, 0)}
+	var items []storedNews
+	var last cursor
+	err = n.db.View(func(tx *bolt.Tx) error {
+		news := tx.Bucket(newsBucket)
+		scan := newDateScan(tx.Bucket(datesBucket).Cursor(), query)
+		for k, v := scan.first(); k != nil; k, v = scan.next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			date, source, link, ok := splitDateKey(k)
+			if !ok || !sources[source] {
+				continue
+			}
+			var item *storedNews
+			if query.matchesContent() || !query.byDate() {
+				decoded, err := decodeNews(news.Get(v), source)
+				if err != nil {
+					return err
+				}
+				if !match(decoded) {
+					continue
+				}
+				item = &decoded
+			}
+			if !query.byDate() {
+				items = append(items, *item)
+				continue
+			}
+			page.Total++
+			pos := cursor{Date: date, Source: source, Link: link}
+			if after != nil && !query.less(*after, pos) {
+				continue
+			}
+			if query.Limit > 0 && len(page.News) == query.Limit {
+				page.NextCursor = encodeCursor(last)
+				continue
+			}
+			if item == nil {
+				decoded, err := decodeNews(news.Get(v), source)
+				if err != nil {
+					return err
+				}
+				item = &decoded
+			}
+			page.News = append(page.News, item.news)
+			last = pos
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error querying news: %v", err)
+		return NewsPage{}, err
+	}
+	if !query.byDate() {
+		return query.page(items)
+	}
+	return page, nil
+}
+
+// dateScan walks the date index within the date range of a query in its sort order.
+type dateScan struct {
+	c          *bolt.Cursor
+	start, end []byte
+	desc       bool
+}
+
+func newDateScan(c *bolt.Cursor, query NewsQuery) *dateScan {
+	scan := &dateScan{c: c, desc: query.byDate() && query.desc()}
+	if !query.DateStart.IsZero() {
+		scan.start = []byte(query.DateStart.UTC().Format(dateKeyLayout))
+	}
+	if !query.DateEnd.IsZero() {
+		// Every key of the end date sorts before the date followed by the next separator byte.
+		scan.end = append([]byte(query.DateEnd.UTC().Format(dateKeyLayout)), keySeparator[0]+1)
+	}
+	return scan
+}
+
+func (s *dateScan) first() ([]byte, []byte) {
+	var k, v []byte
+	if s.desc {
+		if s.end == nil {
+			k, v = s.c.Last()
+		} else if k, v = s.c.Seek(s.end); k == nil {
+			k, v = s.c.Last()
+		} else {
+			k, v = s.c.Prev()
+		}
+	} else if s.start == nil {
+		k, v = s.c.First()
+	} else {
+		k, v = s.c.Seek(s.start)
+	}
+	return s.bound(k, v)
+}
+
+func (s *dateScan) next() ([]byte, []byte) {
+	if s.desc {
+		return s.bound(s.c.Prev())
+	}
+	return s.bound(s.c.Next())
+}
+
+// bound ends the scan outside of the date range.
+func (s *dateScan) bound(k, v []byte) ([]byte, []byte) {
+	if k == nil {
+		return nil, nil
+	}
+	if s.start != nil && bytes.Compare(k, s.start) < 0 {
+		return nil, nil
+	}
+	if s.end != nil && bytes.Compare(k, s.end) >= 0 {
+		return nil, nil
+	}
+	return k, v
+}
+
+// splitDateKey into the date, source and link of the news.
+func splitDateKey(key []byte) (time.Time, string, string, bool) {
+	parts := strings.SplitN(string(key), keySeparator, 3)
+	if len(parts) != 3 {
+		return time.Time{}, "", "", false
+	}
+	date, err := time.Parse(dateKeyLayout, parts[0])
+	if err != nil {
+		return time.Time{}, "", "", false
+	}
+	return date, parts[1], parts[2], true
+}
+
+// decodeNews stored for the source.
+func decodeNews(jsonData []byte, source string) (storedNews, error) {
+	var item entity.News
+	if err := json.Unmarshal(jsonData, &item); err != nil {
+		return storedNews{}, err
+	}
+	return storedNews{source: source, news: item}, nil
+}
+
+// newsKey of the news of the source in the news bucket.
+func newsKey(source string, link entity.Link) []byte {
+	return joinKey(source, string(link))
+}
+
+// dateKey of the news in the dates bucket.
+func dateKey(date time.Time, source string, link entity.Link) []byte {
+	return joinKey(date.UTC().Format(dateKeyLayout), source, string(link))
+}
+
+// joinKey parts into a composite key.
+func joinKey(parts ...string) []byte {
+	var key []byte
+	for i, part := range parts {
+		if i > 0 {
+			key = append(key, keySeparator...)
+		}
+		key = append(key, part...)
+	}
+	return key
+}
@@ -0,0 +1,57 @@
+package managers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	bolt "go.etcd.io/bbolt"
+	"news-aggregator/internal/entity"
+)
+
+func TestNewsDB_AddNews(t *testing.T) {
+	db := openTestDB(t)
+	n := CreateNewsDB(db)
+
+	news := []entity.News{
+		{Title: "Title 1", Link: "https://example.com/1", Date: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{Title: "Title 2", Link: "https://example.com/2", Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	added, err := n.AddNews(news, "source1")
+	assert.NoError(t, err)
+	assert.Equal(t, news, added)
+	// Stored news are skipped.
+	added, err = n.AddNews(news[:1], "source1")
+	assert.NoError(t, err)
+	assert.Empty(t, added)
+	// News of other sources with the same canonical link are skipped.
+	added, err = n.AddNews([]entity.News{
+		{Title: "Other", Link: "https://example.com/1", Date: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{Title: "Other", Link: "http://www.example.com/2/?utm_source=rss", Date: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)},
+	}, "source2")
+	assert.NoError(t, err)
+	assert.Empty(t, added)
+
+	got, err := n.GetNewsFromFolder("source1")
+	assert.NoError(t, err)
+	assert.Equal(t, news, got)
+
+	got, err = n.GetNewsFromFolder("source")
+	assert.NoError(t, err)
+	assert.Empty(t, got, "Expected no news of a source sharing the name prefix")
+
+	got, err = n.GetNewsFromFolder("source2")
+	assert.NoError(t, err)
+	assert.Empty(t, got, "Expected no duplicates of news of another source")
+
+	err = db.View(func(tx *bolt.Tx) error {
+		assert.Equal(t, 2, tx.Bucket(linksBucket).Stats().KeyN, "Expected link index entries")
+		assert.Equal(t, newsKey("source1", news[1].Link), tx.Bucket(canonicalBucket).Get([]byte("https://example.com/2")))
+		c := tx.Bucket(datesBucket).Cursor()
+		k, v := c.First()
+		assert.Equal(t, dateKey(news[1].Date, "source1", news[1].Link), k, "Expected oldest news first")
+		assert.Equal(t, newsKey("source1", news[1].Link), v)
+		return nil
+	})
+	assert.NoError(t, err)
+}
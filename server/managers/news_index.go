@@ -0,0 +1,148 @@
+package managers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"news-aggregator/internal/search"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// indexedStorage of news that can be followed by an index.
+type indexedStorage interface {
+	NewsManager
+	// revision of the stored news of the source, it changes whenever they change,
+	// also by other processes sharing the storage.
+	revision(source string) (string, error)
+	// lookup the stored news by their ids, ids of deleted news are skipped.
+	lookup(ids map[string]bool) ([]storedNews, error)
+}
+
+// indexedNews is a NewsManager answering keyword queries from an inverted index
+// of the stored news instead of scanning them. Only the ids of the news are kept
+// in memory with the index, the news themselves are read from the storage.
+// Before a query the index catches up with the sources changed in the storage
+// since they were indexed, whichever process changed them.
+type indexedNews struct {
+	NewsManager
+	storage indexedStorage
+	index   *search.Index
+	mu      sync.Mutex
+	// ids of the indexed news by source.
+	ids map[string]map[string]bool
+	// revisions of the sources when they were indexed.
+	revisions map[string]string
+}
+
+// IndexNews of the manager, a file or db storage. The index is built from the stored news of every source.
+func IndexNews(manager NewsManager) (NewsManager, error) {
+	storage, ok := manager.(indexedStorage)
+	if !ok {
+		return nil, fmt.Errorf("news of %T cannot be indexed", manager)
+	}
+	n := &indexedNews{
+		NewsManager: manager,
+		storage:     storage,
+		index:       search.NewIndex(),
+		ids:         make(map[string]map[string]bool),
+		revisions:   make(map[string]string),
+	}
+	sources, err := manager.ListSources()
+	if err != nil {
+		return nil, err
+	}
+	if err := n.sync(sources); err != nil {
+		return nil, err
+	}
+	log.Printf("Indexed %d news of %d sources", n.index.Len(), len(sources))
+	return n, nil
+}
+
+// sync the index with the stored news of the sources changed since they were indexed.
+// The news of a changed source are read again, news no longer stored are removed.
+func (n *indexedNews) sync(sources []string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, source := range sources {
+		revision, err := n.storage.revision(source)
+		if err != nil {
+			return err
+		}
+		if indexed, ok := n.revisions[source]; ok && indexed == revision {
+			continue
+		}
+		news, err := n.storage.GetNewsFromFolder(source)
+		if err != nil {
+			log.Printf("Error indexing news of %s: %v", source, err)
+			return err
+		}
+		ids := make(map[string]bool, len(news))
+		for _, item := range news {
+			id := string(newsKey(source, item.Link))
+			ids[id] = true
+			if !n.ids[source][id] {
+				n.index.Add(id, item)
+			}
+		}
+		for id := range n.ids[source] {
+			if !ids[id] {
+				n.index.Remove(id)
+			}
+		}
+		n.ids[source] = ids
+		n.revisions[source] = revision
+	}
+	return nil
+}
+
+// Query with keywords through the index, other queries are passed to the storage.
+// The matching news are read from the storage. Sorting by relevance orders the news by their BM25 score.
+func (n *indexedNews) Query(ctx context.Context, query NewsQuery) (NewsPage, error) {
+	if query.Keywords == nil {
+		return n.NewsManager.Query(ctx, query)
+	}
+	if err := n.sync(query.Sources); err != nil {
+		return NewsPage{}, err
+	}
+	candidates := n.index.Candidates(query.Keywords)
+	var scores map[string]float64
+	if query.byRelevance() {
+		scores = n.index.Scores(query.Keywords)
+	}
+
+	n.mu.Lock()
+	ids := make(map[string]bool)
+	if candidates == nil {
+		// The query can match news without any of its terms.
+		for _, source := range query.Sources {
+			for id := range n.ids[source] {
+				ids[id] = true
+			}
+		}
+	}
+	for id := range candidates {
+		source, _, _ := strings.Cut(id, keySeparator)
+		if n.ids[source][id] && slices.Contains(query.Sources, source) {
+			ids[id] = true
+		}
+	}
+	n.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return NewsPage{}, err
+	}
+	stored, err := n.storage.lookup(ids)
+	if err != nil {
+		return NewsPage{}, err
+	}
+	match := query.matcher()
+	items := make([]storedNews, 0, len(stored))
+	for _, item := range stored {
+		if match(item) {
+			item.score = scores[string(newsKey(item.source, item.news.Link))]
+			items = append(items, item)
+		}
+	}
+	return query.page(items)
+}
@@ -0,0 +1,133 @@
+package managers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/sort"
+)
+
+func TestNewsManager_ListSources(t *testing.T) {
+	for name, m := range queryTestManagers(t) {
+		t.Run(name, func(t *testing.T) {
+			sources, err := m.ListSources()
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []string{"bbc", "cnn"}, sources)
+		})
+	}
+}
+
+func TestIndexNews_RebuildsFromStorage(t *testing.T) {
+	storage := CreateNewsFolder(t.TempDir())
+	for source, news := range queryTestNews {
+		_, err := storage.AddNews(news, source)
+		assert.NoError(t, err)
+	}
+
+	indexed, err := IndexNews(storage)
+	assert.NoError(t, err)
+	query := NewsQuery{Sources: []string{"bbc", "cnn"}, Keywords: mustParseQuery(t, "england")}
+	page, err := indexed.Query(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Link{"https://bbc.com/1", "https://cnn.com/1"}, links(page.News))
+
+	// News added later are indexed as they are stored.
+	added := entity.News{Title: "England draws", Link: "https://bbc.com/4", Date: time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)}
+	_, err = indexed.AddNews([]entity.News{added}, "bbc")
+	assert.NoError(t, err)
+	page, err = indexed.Query(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Link{"https://bbc.com/1", "https://cnn.com/1", "https://bbc.com/4"}, links(page.News))
+}
+
+func TestIndexNews_FollowsStorage(t *testing.T) {
+	dir := t.TempDir()
+	db := openTestDB(t)
+	// Other managers of the storage change it like other processes sharing it.
+	storages := map[string]func() NewsManager{
+		"folder": func() NewsManager { return CreateNewsFolder(dir) },
+		"db":     func() NewsManager { return CreateNewsDB(db) },
+	}
+	for name, storage := range storages {
+		t.Run(name, func(t *testing.T) {
+			old := entity.News{Title: "England wins", Link: "https://bbc.com/1", Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+			_, err := storage().AddNews([]entity.News{old}, "bbc")
+			assert.NoError(t, err)
+			indexed, err := IndexNews(storage())
+			assert.NoError(t, err)
+			query := NewsQuery{Sources: []string{"bbc"}, Keywords: mustParseQuery(t, "england")}
+
+			added := entity.News{Title: "England draws", Link: "https://bbc.com/2", Date: time.Now()}
+			_, err = storage().AddNews([]entity.News{added}, "bbc")
+			assert.NoError(t, err)
+			page, err := indexed.Query(context.Background(), query)
+			assert.NoError(t, err)
+			assert.Equal(t, []entity.Link{"https://bbc.com/1", "https://bbc.com/2"}, links(page.News))
+
+			retention := entity.Retention{MaxAge: entity.Interval(24 * time.Hour)}
+			_, err = storage().Prune(context.Background(), "bbc", PruneOptions{Retention: retention, Now: time.Now()})
+			assert.NoError(t, err)
+			page, err = indexed.Query(context.Background(), query)
+			assert.NoError(t, err)
+			assert.Equal(t, []entity.Link{"https://bbc.com/2"}, links(page.News))
+		})
+	}
+}
+
+func TestIndexNews_SkipsCanonicalDuplicates(t *testing.T) {
+	storage := CreateNewsFolder(t.TempDir())
+	_, err := storage.AddNews([]entity.News{{Title: "England wins", Link: "https://bbc.com/1"}}, "bbc")
+	assert.NoError(t, err)
+	indexed, err := IndexNews(storage)
+	assert.NoError(t, err)
+
+	copied := entity.News{Title: "England wins", Link: "http://www.bbc.com/1/?utm_source=cnn"}
+	added := entity.News{Title: "England draws", Link: "https://cnn.com/2"}
+	_, err = indexed.AddNews([]entity.News{copied, added}, "cnn")
+	assert.NoError(t, err)
+
+	stored, err := storage.GetNewsFromFolder("cnn")
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.News{added}, stored)
+	page, err := indexed.Query(context.Background(), NewsQuery{Sources: []string{"bbc", "cnn"}, Keywords: mustParseQuery(t, "england")})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, page.Total)
+}
+
+func TestIndexNews_QueryByRelevance(t *testing.T) {
+	indexed, err := IndexNews(CreateNewsDB(openTestDB(t)))
+	assert.NoError(t, err)
+	news := []entity.News{
+		{Title: "Rates steady", Link: "https://bbc.com/1", Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{Title: "Central bank cuts rates", Description: "Rates cut by a quarter point.", Link: "https://bbc.com/2", Date: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{Title: "Weather", Link: "https://bbc.com/3", Date: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{Title: "Election and rates", Description: "Voters worry about the cut of rates and taxes ahead of the vote.", Link: "https://bbc.com/4", Date: time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC)},
+	}
+	_, err = indexed.AddNews(news, "bbc")
+	assert.NoError(t, err)
+
+	query := NewsQuery{Sources: []string{"bbc"}, Keywords: mustParseQuery(t, "rates OR cut"), Sort: sort.Options{Criterion: "relevance"}}
+	page, err := indexed.Query(context.Background(), query)
+	assert.NoError(t, err)
+	want := []entity.Link{"https://bbc.com/2", "https://bbc.com/4", "https://bbc.com/1"}
+	assert.Equal(t, want, links(page.News))
+
+	query.Sort.Order = "desc"
+	page, err = indexed.Query(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Link{"https://bbc.com/1", "https://bbc.com/4", "https://bbc.com/2"}, links(page.News))
+
+	// Pages continue in the order of relevance.
+	query.Sort.Order = ""
+	query.Limit = 2
+	page, err = indexed.Query(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, want[:2], links(page.News))
+	query.Cursor = page.NextCursor
+	page, err = indexed.Query(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, want[2:], links(page.News))
+}
@@ -0,0 +1,115 @@
+package managers
+
+import (
+	"encoding/json"
+	"log"
+	"news-aggregator/internal/canonical"
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/safefile"
+	"os"
+	"path/filepath"
+)
+
+// linksFile of the news folder with the canonical links of the stored news of all sources,
+// so that AddNews skips a news stored by another source without reading their news.
+const linksFile = ".links.json"
+
+// canonicalLinks of the stored news to the source storing them.
+type canonicalLinks map[entity.Link]string
+
+// add the link of the news of the source, it reports false if a news with the link is stored.
+func (links canonicalLinks) add(item entity.News, source string) bool {
+	link := canonical.Link(item)
+	if link == "" {
+		return true
+	}
+	if _, ok := links[link]; ok {
+		return false
+	}
+	links[link] = source
+	return true
+}
+
+// remove the link of the deleted news of the source, unless it refers to a news of another source.
+func (links canonicalLinks) remove(item entity.News, source string) {
+	link := canonical.Link(item)
+	if links[link] == source {
+		delete(links, link)
+	}
+}
+
+// linksPath of the links file of the news folder.
+func (folder newsFolder) linksPath() string {
+	return filepath.Join(folder.path, linksFile)
+}
+
+// lockLinks of the news folder exclusively. It is taken before the lock of a source.
+func (folder newsFolder) lockLinks() (*safefile.Lock, error) {
+	return safefile.LockFile(folder.linksPath())
+}
+
+// readLinks of the news folder, nil when the file is missing. The lock of the links must be held.
+func (folder newsFolder) readLinks() (canonicalLinks, error) {
+	jsonData, err := os.ReadFile(folder.linksPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error reading links of news: %v", err)
+		return nil, err
+	}
+	links := make(canonicalLinks)
+	if err := json.Unmarshal(jsonData, &links); err != nil {
+		log.Printf("Error decoding links of news: %v", err)
+		return nil, err
+	}
+	return links, nil
+}
+
+// loadLinks of the news folder, they are collected from the news of every source
+// when the file is missing. The lock of the links must be held.
+func (folder newsFolder) loadLinks() (canonicalLinks, error) {
+	links, err := folder.readLinks()
+	if err != nil || links != nil {
+		return links, err
+	}
+	sources, err := folder.ListSources()
+	if err != nil {
+		return nil, err
+	}
+	links = make(canonicalLinks)
+	for _, source := range sources {
+		news, err := folder.GetNewsFromFolder(source)
+		if err != nil {
+			log.Printf("Error collecting links of news of %s: %v", source, err)
+			return nil, err
+		}
+		for _, item := range news {
+			links.add(item, source)
+		}
+	}
+	log.Printf("Collected %d links of news of %d sources", len(links), len(sources))
+	return links, nil
+}
+
+// changeLinks to the given ones while the news files are changed by write. The links file
+// is removed meanwhile, so a failure or a crash in between leaves it to be collected again
+// from the news instead of out of date. The lock of the links must be held.
+func (folder newsFolder) changeLinks(links canonicalLinks, write func() error) error {
+	if err := os.Remove(folder.linksPath()); err != nil && !os.IsNotExist(err) {
+		log.Printf("Error removing links of news: %v", err)
+		return err
+	}
+	if err := write(); err != nil {
+		return err
+	}
+	jsonData, err := json.Marshal(links)
+	if err != nil {
+		return err
+	}
+	if err := safefile.WriteFile(folder.linksPath(), jsonData, 0644); err != nil {
+		log.Printf("Error writing links of news: %v", err)
+		return err
+	}
+	return nil
+}
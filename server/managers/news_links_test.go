@@ -0,0 +1,67 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"news-aggregator/internal/entity"
+)
+
+func TestNewsFolder_AddNewsSkipsCanonicalDuplicates(t *testing.T) {
+	folder := CreateNewsFolder(t.TempDir())
+	stored := []entity.News{{Title: "England wins", Link: "https://bbc.com/1"}}
+	added, err := folder.AddNews(stored, "bbc")
+	assert.NoError(t, err)
+	assert.Equal(t, stored, added)
+
+	news := []entity.News{
+		{Title: "England wins", Link: "http://www.bbc.com/1/?utm_source=cnn"},
+		{Title: "England draws", Link: "https://cnn.com/2"},
+		{Title: "England draws", Link: "https://cnn.com/2?utm_source=rss"},
+	}
+	added, err = folder.AddNews(news, "cnn")
+	assert.NoError(t, err)
+	assert.Equal(t, news[1:2], added)
+	got, err := folder.GetNewsFromFolder("cnn")
+	assert.NoError(t, err)
+	assert.Equal(t, news[1:2], got)
+}
+
+func TestNewsFolder_AddNewsCollectsMissingLinks(t *testing.T) {
+	dir := t.TempDir()
+	folder := newsFolder{dir}
+	// A news folder written before the links were kept.
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "bbc"), 0755))
+	assert.NoError(t, writeNewsFile(filepath.Join(dir, "bbc", "2024-05-01.json"), []entity.News{{Link: "https://bbc.com/1"}}))
+
+	added, err := folder.AddNews([]entity.News{{Link: "https://www.bbc.com/1"}, {Link: "https://cnn.com/1"}}, "cnn")
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.News{{Link: "https://cnn.com/1"}}, added)
+	links, err := folder.readLinks()
+	assert.NoError(t, err)
+	assert.Equal(t, canonicalLinks{"https://bbc.com/1": "bbc", "https://cnn.com/1": "cnn"}, links)
+}
+
+func TestNewsFolder_PruneRemovesLinks(t *testing.T) {
+	folder := newsFolder{t.TempDir()}
+	old := entity.News{Link: "https://bbc.com/1", Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+	_, err := folder.AddNews([]entity.News{old, {Link: "https://bbc.com/2", Date: time.Now()}}, "bbc")
+	assert.NoError(t, err)
+
+	_, err = folder.Prune(context.Background(), "bbc", PruneOptions{Retention: entity.Retention{MaxAge: entity.Interval(24 * time.Hour)}, Now: time.Now(), DryRun: true})
+	assert.NoError(t, err)
+	links, err := folder.readLinks()
+	assert.NoError(t, err)
+	assert.Len(t, links, 2, "Expected a dry run to keep the links")
+
+	result, err := folder.Prune(context.Background(), "bbc", PruneOptions{Retention: entity.Retention{MaxAge: entity.Interval(24 * time.Hour)}, Now: time.Now()})
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.News{old}, result.Deleted)
+	links, err = folder.readLinks()
+	assert.NoError(t, err)
+	assert.Equal(t, canonicalLinks{"https://bbc.com/2": "bbc"}, links)
+}
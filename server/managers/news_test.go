@@ -13,9 +13,8 @@ import (
 
 func TestAddNews(t *testing.T) {
 	NewsFolder, expectedNews := setupTestData(t)
-	defer cleanupTestData(t, NewsFolder)
 
-	finalFileName := fmt.Sprintf("test-source/%s.json", timeNow)
+	finalFileName := fmt.Sprintf("test-source/%s.json", timeNow().UTC().Format(dayLayout))
 	finalFilePath := filepath.Join(NewsFolder, finalFileName)
 
 	fileData, err := os.ReadFile(finalFilePath)
@@ -34,7 +33,7 @@ func TestAddNews(t *testing.T) {
 	}
 
 	invalidNewsHandler := newsFolder{path: string([]byte{0x00})}
-	err = invalidNewsHandler.AddNews(expectedNews, "invalid-source")
+	_, err = invalidNewsHandler.AddNews(expectedNews, "invalid-source")
 	if err == nil {
 		t.Errorf("Expected an error when creating a directory with an invalid path, but got none")
 	}
@@ -51,8 +50,46 @@ func TestAddNews(t *testing.T) {
 	//}
 }
 
+func TestAddNews_NextDay(t *testing.T) {
+	now := time.Date(2024, 5, 1, 23, 59, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+	folder := newsFolder{path: t.TempDir()}
+
+	if _, err := folder.AddNews([]entity.News{{Link: "https://example.com/1"}}, "bbc"); err != nil {
+		t.Fatalf("AddNews() error = %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := folder.AddNews([]entity.News{{Link: "https://example.com/2"}}, "bbc"); err != nil {
+		t.Fatalf("AddNews() error = %v", err)
+	}
+
+	for day, link := range map[string]entity.Link{"2024-05-01": "https://example.com/1", "2024-05-02": "https://example.com/2"} {
+		news, err := loadNewsFromFile(filepath.Join(folder.path, "bbc", day+".json"))
+		if err != nil {
+			t.Fatalf("Failed to load news of %s: %v", day, err)
+		}
+		if want := []entity.News{{Link: link}}; !reflect.DeepEqual(news, want) {
+			t.Errorf("News of %s = %v, want %v", day, news, want)
+		}
+	}
+}
+
+func TestAppendNew(t *testing.T) {
+	current := []entity.News{{Link: "https://example.com/1"}}
+	news := []entity.News{
+		{Link: "http://www.example.com/1?utm_source=rss"},
+		{Link: "https://example.com/2"},
+		{Link: "https://m.example.com/2/"},
+	}
+	want := []entity.News{{Link: "https://example.com/1"}, {Link: "https://example.com/2"}}
+	if got := appendNew(current, news); !reflect.DeepEqual(got, want) {
+		t.Errorf("appendNew() = %v, want %v", got, want)
+	}
+}
+
 func TestGetNewsFromFolder(t *testing.T) {
-	NewsFolder := "../../internal/testdata"
+	NewsFolder := copyTestNews(t, "bbc_news")
 	newsHandler := newsFolder{path: NewsFolder}
 	got, err := newsHandler.GetNewsFromFolder("bbc_news")
 	if err != nil {
@@ -101,28 +138,6 @@ func TestGetNewsFromFolder(t *testing.T) {
 	}
 }
 
-func TestGetNewsSourceFilePath(t *testing.T) {
-	NewsFolder, _ := setupTestData(t)
-	defer cleanupTestData(t, NewsFolder)
-
-	newsHandler := newsFolder{path: NewsFolder}
-	sourceNames := []string{"test-source"}
-
-	got, err := newsHandler.GetNewsSourceFilePath(sourceNames)
-	if err != nil {
-		t.Fatalf("GetNewsSourceFilePath() failed: %v", err)
-	}
-
-	finalFileName := fmt.Sprintf("test-source/%s.json", timeNow)
-	finalFilePath := filepath.Join(NewsFolder, finalFileName)
-	expected := map[string][]string{
-		"test-source": {finalFilePath},
-	}
-
-	if !reflect.DeepEqual(got, expected) {
-		t.Errorf("GetNewsSourceFilePath() = %v, want %v", got, expected)
-	}
-}
 func setupTestData(t *testing.T) (string, []entity.News) {
 	t.Helper()
 
@@ -144,9 +159,9 @@ func setupTestData(t *testing.T) (string, []entity.News) {
 		},
 	}
 
-	NewsFolder := "test-data"
+	NewsFolder := t.TempDir()
 	newsHandler := newsFolder{path: NewsFolder}
-	err := newsHandler.AddNews(news, "test-source")
+	_, err := newsHandler.AddNews(news, "test-source")
 	if err != nil {
 		t.Fatalf("AddNews() failed: %v", err)
 	}
@@ -154,11 +169,28 @@ func setupTestData(t *testing.T) (string, []entity.News) {
 	return NewsFolder, news
 }
 
-func cleanupTestData(t *testing.T, path string) {
+// copyTestNews folders of the sources from the test data to a temporary news folder,
+// so the lock files of the sources are left there instead of in the test data.
+func copyTestNews(t *testing.T, sources ...string) string {
 	t.Helper()
-
-	err := os.RemoveAll(path)
-	if err != nil {
-		t.Errorf("Failed to clean up test data folder: %v", err)
-	}
+	dir := t.TempDir()
+	for _, source := range sources {
+		entries, err := os.ReadDir(filepath.Join("../../internal/testdata", source))
+		if err != nil {
+			t.Fatalf("Failed to read test news of %s: %v", source, err)
+		}
+		if err := os.MkdirAll(filepath.Join(dir, source), 0755); err != nil {
+			t.Fatalf("Failed to create news folder of %s: %v", source, err)
+		}
+		for _, entry := range entries {
+			data, err := os.ReadFile(filepath.Join("../../internal/testdata", source, entry.Name()))
+			if err != nil {
+				t.Fatalf("Failed to read test news file %s: %v", entry.Name(), err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, source, entry.Name()), data, 0644); err != nil {
+				t.Fatalf("Failed to copy test news file %s: %v", entry.Name(), err)
+			}
+		}
+	}
+	return dir
 }
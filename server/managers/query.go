@@ -0,0 +1,193 @@
+package managers
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/filters"
+	"news-aggregator/internal/sort"
+	goSort "sort"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned by Query for a cursor it did not issue.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// NewsQuery selects a page of stored news.
+type NewsQuery struct {
+	// Sources whose news are queried, by their stored name.
+	Sources []string
+	// Keywords query the title and description must satisfy, nil matches all news.
+	Keywords *filters.Query
+	// Category and Author of the news, nil matches all news.
+	Category *filters.Category
+	Author   *filters.Author
+	// DateStart and DateEnd bound the news date inclusively, zero values leave the range open.
+	DateStart time.Time
+	DateEnd   time.Time
+	// Sort by "date" (default), "source" or "relevance", ascending unless the order is "desc".
+	// Relevance ascends by rank, the most relevant news first.
+	Sort sort.Options
+	// Cursor continues after the last news of the previous page.
+	Cursor string
+	// Limit of news in the page, zero returns all of them.
+	Limit int
+}
+
+// NewsPage is the result of a NewsQuery.
+type NewsPage struct {
+	News []entity.News
+	// Total number of news matching the query, in all pages.
+	Total int
+	// NextCursor of the following page, empty on the last page.
+	NextCursor string
+}
+
+// storedNews is a news together with the name of the source it is stored for.
+type storedNews struct {
+	source string
+	news   entity.News
+	// score of the relevance to the keywords when sorting by relevance.
+	score float64
+}
+
+// cursor is the position of the last news of a page in the sort order.
+type cursor struct {
+	Date       time.Time `json:"d"`
+	Source     string    `json:"s"`
+	Link       string    `json:"l"`
+	NewsSource string    `json:"n,omitempty"`
+	Score      float64   `json:"r,omitempty"`
+}
+
+// byDate reports if the query sorts news by date.
+func (q NewsQuery) byDate() bool {
+	return q.Sort.Criterion == "" || strings.EqualFold(q.Sort.Criterion, "date")
+}
+
+// byRelevance reports if the query sorts news by their relevance to the keywords.
+func (q NewsQuery) byRelevance() bool {
+	return strings.EqualFold(q.Sort.Criterion, "relevance")
+}
+
+// desc reports if the query sorts in descending order.
+func (q NewsQuery) desc() bool {
+	return strings.EqualFold(q.Sort.Order, "desc")
+}
+
+// matcher of the news to the sources, date range, category, author and keywords of the query.
+func (q NewsQuery) matcher() func(item storedNews) bool {
+	sources := make(map[string]bool, len(q.Sources))
+	for _, s := range q.Sources {
+		sources[s] = true
+	}
+	return func(item storedNews) bool {
+		if !sources[item.source] || !q.inRange(item.news.Date) {
+			return false
+		}
+		if q.Category != nil && !q.Category.Match(item.news) {
+			return false
+		}
+		if q.Author != nil && !q.Author.Match(item.news) {
+			return false
+		}
+		return q.Keywords == nil || q.Keywords.Match(item.news)
+	}
+}
+
+// matchesContent reports if the query filters news by more than their source and date.
+func (q NewsQuery) matchesContent() bool {
+	return q.Keywords != nil || q.Category != nil || q.Author != nil
+}
+
+// inRange reports if the date is within the date range of the query.
+func (q NewsQuery) inRange(date time.Time) bool {
+	if !q.DateStart.IsZero() && date.Before(q.DateStart) {
+		return false
+	}
+	return q.DateEnd.IsZero() || !date.After(q.DateEnd)
+}
+
+// less reports if a comes before b in the sort order of the query.
+// Ties are broken by date, source and link so that pages never overlap.
+func (q NewsQuery) less(a, b cursor) bool {
+	before := q.compare(a, b)
+	if q.desc() {
+		return before > 0
+	}
+	return before < 0
+}
+
+// compare two news positions, by descending score or by news source first unless sorting by date.
+func (q NewsQuery) compare(a, b cursor) int {
+	switch {
+	case q.byRelevance():
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+	case !q.byDate():
+		if c := strings.Compare(a.NewsSource, b.NewsSource); c != 0 {
+			return c
+		}
+	}
+	if c := a.Date.Compare(b.Date); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Source, b.Source); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Link, b.Link)
+}
+
+// position of the stored news in the sort order.
+func position(item storedNews) cursor {
+	return cursor{Date: item.news.Date, Source: item.source, Link: string(item.news.Link), NewsSource: item.news.Source, Score: item.score}
+}
+
+// page sorts the matching news and cuts the page at the cursor.
+func (q NewsQuery) page(items []storedNews) (NewsPage, error) {
+	after, err := decodeCursor(q.Cursor)
+	if err != nil {
+		return NewsPage{}, err
+	}
+	goSort.Slice(items, func(i, j int) bool {
+		return q.less(position(items[i]), position(items[j]))
+	})
+	page := NewsPage{Total: len(items), News: make([]entity.News, 0)}
+	for i, item := range items {
+		if after != nil && !q.less(*after, position(item)) {
+			continue
+		}
+		if q.Limit > 0 && len(page.News) == q.Limit {
+			page.NextCursor = encodeCursor(position(items[i-1]))
+			break
+		}
+		page.News = append(page.News, item.news)
+	}
+	return page, nil
+}
+
+// encodeCursor as an opaque string.
+func encodeCursor(c cursor) string {
+	jsonData, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(jsonData)
+}
+
+// decodeCursor issued by encodeCursor, an empty cursor is the start of the results.
+func decodeCursor(value string) (*cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	jsonData, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(jsonData, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
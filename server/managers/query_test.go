@@ -0,0 +1,181 @@
+package managers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/filters"
+	"news-aggregator/internal/sort"
+)
+
+// queryTestNews stored for the sources "bbc" and "cnn".
+var queryTestNews = map[string][]entity.News{
+	"bbc": {
+		{Title: "England wins", Link: "https://bbc.com/1", Source: "BBC", Date: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Categories: []string{"Sport"}, Authors: []string{"Jane Doe"}},
+		{Title: "Weather today", Link: "https://bbc.com/2", Source: "BBC", Date: time.Date(2024, 5, 3, 10, 0, 0, 0, time.UTC)},
+		{Title: "Markets rally", Link: "https://bbc.com/3", Source: "BBC", Date: time.Date(2024, 5, 5, 10, 0, 0, 0, time.UTC), Authors: []string{"John Roe"}},
+	},
+	"cnn": {
+		{Title: "England loses", Link: "https://cnn.com/1", Source: "CNN", Date: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC), Categories: []string{"sport", "Europe"}},
+		{Title: "Election news", Link: "https://cnn.com/2", Source: "CNN", Date: time.Date(2024, 5, 4, 10, 0, 0, 0, time.UTC)},
+	},
+}
+
+// queryTestManagers with queryTestNews stored by every backend, also through an index.
+func queryTestManagers(t *testing.T) map[string]NewsManager {
+	indexed, err := IndexNews(CreateNewsDB(openTestDB(t)))
+	assert.NoError(t, err)
+	managers := map[string]NewsManager{
+		"folder": CreateNewsFolder(t.TempDir()),
+		"db":     CreateNewsDB(openTestDB(t)),
+		"index":  indexed,
+	}
+	for _, m := range managers {
+		for source, news := range queryTestNews {
+			_, err := m.AddNews(news, source)
+			assert.NoError(t, err)
+		}
+	}
+	return managers
+}
+
+// links of the news in their order.
+func links(news []entity.News) []entity.Link {
+	result := make([]entity.Link, 0, len(news))
+	for _, n := range news {
+		result = append(result, n.Link)
+	}
+	return result
+}
+
+func TestNewsManager_Query(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     NewsQuery
+		want      []entity.Link
+		wantTotal int
+	}{
+		{
+			name:      "date ascending by default",
+			query:     NewsQuery{Sources: []string{"bbc", "cnn"}},
+			want:      []entity.Link{"https://bbc.com/1", "https://cnn.com/1", "https://bbc.com/2", "https://cnn.com/2", "https://bbc.com/3"},
+			wantTotal: 5,
+		},
+		{
+			name:      "date descending",
+			query:     NewsQuery{Sources: []string{"bbc", "cnn"}, Sort: sort.Options{Criterion: "date", Order: "desc"}},
+			want:      []entity.Link{"https://bbc.com/3", "https://cnn.com/2", "https://bbc.com/2", "https://cnn.com/1", "https://bbc.com/1"},
+			wantTotal: 5,
+		},
+		{
+			name:      "source descending",
+			query:     NewsQuery{Sources: []string{"bbc", "cnn"}, Sort: sort.Options{Criterion: "source", Order: "DESC"}},
+			want:      []entity.Link{"https://cnn.com/2", "https://cnn.com/1", "https://bbc.com/3", "https://bbc.com/2", "https://bbc.com/1"},
+			wantTotal: 5,
+		},
+		{
+			name:      "single source",
+			query:     NewsQuery{Sources: []string{"cnn"}},
+			want:      []entity.Link{"https://cnn.com/1", "https://cnn.com/2"},
+			wantTotal: 2,
+		},
+		{
+			name: "date range",
+			query: NewsQuery{
+				Sources:   []string{"bbc", "cnn"},
+				DateStart: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC),
+				DateEnd:   time.Date(2024, 5, 4, 10, 0, 0, 0, time.UTC),
+				Sort:      sort.Options{Order: "desc"},
+			},
+			want:      []entity.Link{"https://cnn.com/2", "https://bbc.com/2", "https://cnn.com/1"},
+			wantTotal: 3,
+		},
+		{
+			name:      "keywords",
+			query:     NewsQuery{Sources: []string{"bbc", "cnn"}, Keywords: mustParseQuery(t, "england,markets")},
+			want:      []entity.Link{"https://bbc.com/1", "https://cnn.com/1", "https://bbc.com/3"},
+			wantTotal: 3,
+		},
+		{
+			name:      "keyword query",
+			query:     NewsQuery{Sources: []string{"bbc", "cnn"}, Keywords: mustParseQuery(t, "england -loses")},
+			want:      []entity.Link{"https://bbc.com/1"},
+			wantTotal: 1,
+		},
+		{
+			name:      "category",
+			query:     NewsQuery{Sources: []string{"bbc", "cnn"}, Category: &filters.Category{Categories: []string{"Sport"}}, Limit: 1},
+			want:      []entity.Link{"https://bbc.com/1"},
+			wantTotal: 2,
+		},
+		{
+			name:      "author",
+			query:     NewsQuery{Sources: []string{"bbc", "cnn"}, Author: &filters.Author{Authors: []string{"jane doe", "John Roe"}}},
+			want:      []entity.Link{"https://bbc.com/1", "https://bbc.com/3"},
+			wantTotal: 2,
+		},
+		{
+			name:      "limit",
+			query:     NewsQuery{Sources: []string{"bbc", "cnn"}, Limit: 2},
+			want:      []entity.Link{"https://bbc.com/1", "https://cnn.com/1"},
+			wantTotal: 5,
+		},
+	}
+	for name, m := range queryTestManagers(t) {
+		for _, tt := range tests {
+			t.Run(name+" "+tt.name, func(t *testing.T) {
+				page, err := m.Query(context.Background(), tt.query)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, links(page.News))
+				assert.Equal(t, tt.wantTotal, page.Total)
+			})
+		}
+	}
+}
+
+func TestNewsManager_QueryPages(t *testing.T) {
+	for name, m := range queryTestManagers(t) {
+		for _, sortOptions := range []sort.Options{{Order: "desc"}, {Criterion: "source", Order: "asc"}} {
+			t.Run(name+" "+sortOptions.Criterion, func(t *testing.T) {
+				query := NewsQuery{Sources: []string{"bbc", "cnn"}, Sort: sortOptions}
+				all, err := m.Query(context.Background(), query)
+				assert.NoError(t, err)
+				assert.Empty(t, all.NextCursor)
+
+				var paged []entity.News
+				query.Limit = 2
+				for pages := 0; pages < 5; pages++ {
+					page, err := m.Query(context.Background(), query)
+					assert.NoError(t, err)
+					assert.Equal(t, 5, page.Total)
+					paged = append(paged, page.News...)
+					if page.NextCursor == "" {
+						break
+					}
+					query.Cursor = page.NextCursor
+				}
+				assert.Equal(t, links(all.News), links(paged))
+			})
+		}
+	}
+}
+
+func TestNewsManager_QueryInvalidCursor(t *testing.T) {
+	for name, m := range queryTestManagers(t) {
+		t.Run(name, func(t *testing.T) {
+			_, err := m.Query(context.Background(), NewsQuery{Sources: []string{"bbc"}, Cursor: "not a cursor"})
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
+
+func mustParseQuery(t *testing.T, text string) *filters.Query {
+	query, err := filters.ParseQuery(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return query
+}
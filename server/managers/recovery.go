@@ -0,0 +1,132 @@
+package managers
+
+import (
+	"encoding/json"
+	"log"
+	"news-aggregator/internal/entity"
+	"news-aggregator/internal/safefile"
+	"os"
+	"path/filepath"
+)
+
+// Recovery of the files of the file storage after a crash or a full disk.
+type Recovery struct {
+	PathToSources   string
+	PathToNews      string
+	PathToFeedCache string
+}
+
+// Run the recovery before the files are used: temporary files of interrupted writes are removed
+// and files that cannot be decoded are quarantined next to them, so that they no longer fail
+// every request. It holds the locks of the files and returns the quarantined ones.
+func (r Recovery) Run() ([]string, error) {
+	var quarantined []string
+	files := []struct {
+		path  string
+		value any
+	}{
+		{r.PathToSources, &[]entity.Source{}},
+		{r.PathToFeedCache, &map[string]Validators{}},
+	}
+	for _, file := range files {
+		if file.path == "" {
+			continue
+		}
+		moved, err := recoverFile(file.path, file.value)
+		if err != nil {
+			return quarantined, err
+		}
+		quarantined = append(quarantined, moved...)
+	}
+	if r.PathToNews == "" {
+		return quarantined, nil
+	}
+	folder := newsFolder{r.PathToNews}
+	moved, err := recoverFile(folder.linksPath(), &canonicalLinks{})
+	if err != nil {
+		return quarantined, err
+	}
+	quarantined = append(quarantined, moved...)
+	sources, err := folder.ListSources()
+	if err != nil {
+		return quarantined, err
+	}
+	for _, source := range sources {
+		moved, err := folder.recover(source)
+		if err != nil {
+			return quarantined, err
+		}
+		quarantined = append(quarantined, moved...)
+	}
+	return quarantined, nil
+}
+
+// recoverFile quarantines the file if it cannot be decoded into the value, a missing file is fine.
+func recoverFile(path string, value any) ([]string, error) {
+	lock, err := safefile.LockFile(path)
+	if err != nil {
+		log.Printf("Error locking %s: %v", path, err)
+		return nil, err
+	}
+	defer lock.Unlock()
+	if _, err := safefile.CleanTemp(path); err != nil {
+		log.Printf("Error removing temporary files of %s: %v", path, err)
+		return nil, err
+	}
+	jsonData, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error reading %s: %v", path, err)
+		return nil, err
+	}
+	if err := json.Unmarshal(jsonData, value); err == nil {
+		return nil, nil
+	}
+	moved, err := safefile.Quarantine(path)
+	if err != nil {
+		log.Printf("Error quarantining %s: %v", path, err)
+		return nil, err
+	}
+	log.Printf("Quarantined corrupt file %s as %s", path, moved)
+	return []string{moved}, nil
+}
+
+// recover the news files of the source, holding the lock of the source.
+func (folder newsFolder) recover(source string) ([]string, error) {
+	lock, err := folder.lock(source, true)
+	if err != nil {
+		log.Printf("Error locking news of %s: %v", source, err)
+		return nil, err
+	}
+	defer lock.Unlock()
+	sourcePath := filepath.Join(folder.path, source)
+	if _, err := safefile.CleanTempDir(sourcePath); err != nil {
+		log.Printf("Error removing temporary files of %s: %v", source, err)
+		return nil, err
+	}
+	paths, err := getNewsSources(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+	var quarantined []string
+	for _, path := range paths {
+		jsonData, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Error reading %s: %v", path, err)
+			return quarantined, err
+		}
+		if _, err := decodeNewsFile(path, jsonData); err == nil {
+			continue
+		}
+		moved, err := safefile.Quarantine(path)
+		if err != nil {
+			log.Printf("Error quarantining %s: %v", path, err)
+			return quarantined, err
+		}
+		log.Printf("Quarantined corrupt news file %s as %s", path, moved)
+		quarantined = append(quarantined, moved)
+	}
+	return quarantined, nil
+}
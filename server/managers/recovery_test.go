@@ -0,0 +1,71 @@
+package managers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"news-aggregator/internal/entity"
+)
+
+func TestRecovery_Run(t *testing.T) {
+	dir := t.TempDir()
+	pathToSources := filepath.Join(dir, "sources.json")
+	pathToFeedCache := filepath.Join(dir, "feed_cache.json")
+	pathToNews := filepath.Join(dir, "news")
+	sourcePath := filepath.Join(pathToNews, "bbc")
+	assert.NoError(t, os.MkdirAll(sourcePath, 0755))
+	files := map[string]string{
+		pathToSources:                                        `[{"Name": "bbc", "PathTo`,
+		pathToFeedCache:                                      `{"bbc": {"URL": "https://bbc.com/rss"}}`,
+		filepath.Join(pathToNews, linksFile):                 `{"https://bbc.com/1": "bb`,
+		filepath.Join(sourcePath, "2024-05-01.json"):         `[{"Link": "https://bbc.com/1"}]`,
+		filepath.Join(sourcePath, "2024-05-02.json"):         `[{"Link": "https://bbc.com/2"`,
+		filepath.Join(sourcePath, ".2024-05-03.json.tmp-42"): `[{"Link"`,
+	}
+	for path, content := range files {
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	quarantined, err := Recovery{PathToSources: pathToSources, PathToNews: pathToNews, PathToFeedCache: pathToFeedCache}.Run()
+	assert.NoError(t, err)
+	assert.Len(t, quarantined, 3)
+	for i, path := range []string{pathToSources, filepath.Join(pathToNews, linksFile), filepath.Join(sourcePath, "2024-05-02.json")} {
+		assert.True(t, strings.HasPrefix(quarantined[i], path+".corrupt-"), quarantined[i])
+	}
+	_, err = os.Stat(filepath.Join(sourcePath, ".2024-05-03.json.tmp-42"))
+	assert.True(t, os.IsNotExist(err), "Expected the temporary file to be removed")
+
+	sources, err := CreateSourceFolder(pathToSources).GetSources()
+	assert.NoError(t, err)
+	assert.Empty(t, sources)
+	news, err := CreateNewsFolder(pathToNews).GetNewsFromFolder("bbc")
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.News{{Link: "https://bbc.com/1"}}, news)
+	_, err = os.Stat(pathToFeedCache)
+	assert.NoError(t, err, "Expected the valid feed cache to be kept")
+}
+
+func TestNewsFolder_AddNewsConcurrently(t *testing.T) {
+	dir := t.TempDir()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Every writer has its own manager like separate processes sharing the folder.
+			item := entity.News{Link: entity.Link(fmt.Sprintf("https://bbc.com/%d", i)), Date: time.Now()}
+			_, err := CreateNewsFolder(dir).AddNews([]entity.News{item}, "bbc")
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+	news, err := CreateNewsFolder(dir).GetNewsFromFolder("bbc")
+	assert.NoError(t, err)
+	assert.Len(t, news, 10, "Expected no news to be lost")
+}
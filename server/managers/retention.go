@@ -0,0 +1,55 @@
+package managers
+
+import (
+	"cmp"
+	"news-aggregator/internal/entity"
+	"slices"
+	"time"
+)
+
+// PruneOptions select the stored news of a source to delete.
+type PruneOptions struct {
+	Retention entity.Retention
+	// Now is the time the age of the news is measured from.
+	Now time.Time
+	// ArchiveBefore compacts the daily files of the news folder dated before it into
+	// gzip archives of their month, the zero time compacts none. Other storages ignore it.
+	ArchiveBefore time.Time
+	// DryRun reports what would be deleted and archived without changing the storage.
+	DryRun bool
+}
+
+// PruneResult of the news of a source.
+type PruneResult struct {
+	// Kept is the number of news left.
+	Kept int `json:"kept"`
+	// Deleted news, or the news a dry run would delete.
+	Deleted []entity.News `json:"deleted,omitempty"`
+	// Archived names of the files compacted into archives, or a dry run would compact.
+	Archived []string `json:"archived,omitempty"`
+}
+
+// expired flags the news older than the max age of the retention or beyond
+// its max count of the newest news; ties in date keep the first stored news.
+func (o PruneOptions) expired(news []entity.News) []bool {
+	expired := make([]bool, len(news))
+	if o.Retention.MaxAge > 0 {
+		cutoff := o.Now.Add(-time.Duration(o.Retention.MaxAge))
+		for i, item := range news {
+			expired[i] = item.Date.Before(cutoff)
+		}
+	}
+	if o.Retention.MaxCount > 0 && len(news) > o.Retention.MaxCount {
+		order := make([]int, len(news))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int {
+			return cmp.Compare(news[b].Date.UnixNano(), news[a].Date.UnixNano())
+		})
+		for _, i := range order[o.Retention.MaxCount:] {
+			expired[i] = true
+		}
+	}
+	return expired
+}
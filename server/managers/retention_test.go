@@ -0,0 +1,118 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"news-aggregator/internal/entity"
+)
+
+func TestPruneOptions_expired(t *testing.T) {
+	now := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	news := []entity.News{
+		{Link: "1", Date: now.Add(-72 * time.Hour)},
+		{Link: "2", Date: now.Add(-24 * time.Hour)},
+		{Link: "3", Date: now.Add(-48 * time.Hour)},
+		{Link: "4", Date: now.Add(-24 * time.Hour)},
+	}
+	tests := []struct {
+		name      string
+		retention entity.Retention
+		want      []bool
+	}{
+		{name: "keep all", want: []bool{false, false, false, false}},
+		{name: "max age", retention: entity.Retention{MaxAge: entity.Interval(36 * time.Hour)}, want: []bool{true, false, true, false}},
+		{name: "max count keeps the newest", retention: entity.Retention{MaxCount: 3}, want: []bool{true, false, false, false}},
+		{name: "max count ties keep the first", retention: entity.Retention{MaxCount: 1}, want: []bool{true, false, true, true}},
+		{name: "both", retention: entity.Retention{MaxAge: entity.Interval(60 * time.Hour), MaxCount: 2}, want: []bool{true, false, true, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := PruneOptions{Retention: tt.retention, Now: now}
+			assert.Equal(t, tt.want, options.expired(news))
+		})
+	}
+}
+
+func TestNewsManager_Prune(t *testing.T) {
+	for name, m := range queryTestManagers(t) {
+		t.Run(name, func(t *testing.T) {
+			options := PruneOptions{Retention: entity.Retention{MaxCount: 2}, Now: time.Now(), DryRun: true}
+			result, err := m.Prune(context.Background(), "bbc", options)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, result.Kept)
+			assert.Equal(t, []entity.Link{"https://bbc.com/1"}, links(result.Deleted))
+			news, err := m.GetNewsFromFolder("bbc")
+			assert.NoError(t, err)
+			assert.Len(t, news, 3, "Expected a dry run to keep the news")
+
+			options.DryRun = false
+			result, err = m.Prune(context.Background(), "bbc", options)
+			assert.NoError(t, err)
+			assert.Equal(t, []entity.Link{"https://bbc.com/1"}, links(result.Deleted))
+			news, err = m.GetNewsFromFolder("bbc")
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []entity.Link{"https://bbc.com/2", "https://bbc.com/3"}, links(news))
+
+			page, err := m.Query(context.Background(), NewsQuery{Sources: []string{"bbc", "cnn"}, Keywords: mustParseQuery(t, "england")})
+			assert.NoError(t, err)
+			assert.Equal(t, []entity.Link{"https://cnn.com/1"}, links(page.News))
+
+			// The link of a deleted news can be stored again.
+			_, err = m.AddNews(queryTestNews["bbc"][:1], "bbc")
+			assert.NoError(t, err)
+			news, err = m.GetNewsFromFolder("bbc")
+			assert.NoError(t, err)
+			assert.Len(t, news, 3)
+		})
+	}
+}
+
+func TestNewsFolder_PruneArchives(t *testing.T) {
+	dir := t.TempDir()
+	folder := newsFolder{dir}
+	sourcePath := filepath.Join(dir, "bbc")
+	assert.NoError(t, os.MkdirAll(sourcePath, 0755))
+	files := map[string][]entity.News{
+		"2024-04.json.gz": {{Link: "https://bbc.com/1", Date: time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)}},
+		"2024-04-29.json": {{Link: "https://bbc.com/2", Date: time.Date(2024, 4, 29, 10, 0, 0, 0, time.UTC)}},
+		"2024-04-30.json": {
+			{Link: "https://bbc.com/3", Date: time.Date(2024, 4, 30, 10, 0, 0, 0, time.UTC)},
+			{Link: "https://bbc.com/1", Date: time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)},
+		},
+		"2024-05-01.json": {{Link: "https://bbc.com/4", Date: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}},
+	}
+	for name, news := range files {
+		assert.NoError(t, writeNewsFile(filepath.Join(sourcePath, name), news))
+	}
+	options := PruneOptions{Now: time.Now(), ArchiveBefore: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), DryRun: true}
+
+	result, err := folder.Prune(context.Background(), "bbc", options)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"2024-04-29.json", "2024-04-30.json"}, result.Archived)
+	entries, err := os.ReadDir(sourcePath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4, "Expected a dry run to keep the files")
+
+	options.DryRun = false
+	_, err = folder.Prune(context.Background(), "bbc", options)
+	assert.NoError(t, err)
+	entries, err = os.ReadDir(sourcePath)
+	assert.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"2024-04.json.gz", "2024-05-01.json"}, names)
+
+	archived, err := loadNewsFromFile(filepath.Join(sourcePath, "2024-04.json.gz"))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []entity.Link{"https://bbc.com/1", "https://bbc.com/2", "https://bbc.com/3"}, links(archived))
+	news, err := folder.GetNewsFromFolder("bbc")
+	assert.NoError(t, err)
+	assert.Len(t, news, 4)
+}
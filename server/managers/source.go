@@ -6,19 +6,73 @@ import (
 	"fmt"
 	"log"
 	"news-aggregator/internal/entity"
+	"news-aggregator/internal/safefile"
 	"os"
 	_ "slices"
 )
 
+var (
+	// ErrSourceNotFound is returned for a name without a source.
+	ErrSourceNotFound = errors.New("source not found")
+	// ErrSourceExists is returned when creating a source with the name of another source.
+	ErrSourceExists = errors.New("source already exists")
+	// ErrSourceChanged is returned when the ETag of a source does not match the If-Match value.
+	ErrSourceChanged = errors.New("source changed")
+)
+
+// sourceError is an error of one of the kinds above with its own message.
+type sourceError struct {
+	kind    error
+	message string
+}
+
+func (e sourceError) Error() string { return e.message }
+
+func (e sourceError) Unwrap() error { return e.kind }
+
+func sourceNotFound(name string) error {
+	return sourceError{ErrSourceNotFound, fmt.Sprintf("source with name %s not found", name)}
+}
+
+func sourceExists(name string) error {
+	return sourceError{ErrSourceExists, fmt.Sprintf("Source with name %s already exists", name)}
+}
+
+func sourceChanged(name string) error {
+	return sourceError{ErrSourceChanged, fmt.Sprintf("source with name %s was changed, its ETag does not match", name)}
+}
+
+// removedError of a source that does not exist, when removing it with the If-Match value.
+func removedError(name, ifMatch string) error {
+	if ifMatch == "" {
+		return sourceNotFound(name)
+	}
+	return sourceChanged(name)
+}
+
 // SourceManager provides API for handling news sources.
+// Every change of a source increments its Version.
 //
 //go:generate mockgen -source=source.go -destination=mock_managers/mock_source.go
 type SourceManager interface {
 	CreateSource(name, url string) (entity.Source, error)
+	// AddSource creates the source with its settings at once, at version 1.
+	// It returns ErrSourceExists when the name is taken.
+	AddSource(source entity.Source) (entity.Source, error)
 	GetSource(name string) (entity.Source, error)
 	GetSources() ([]entity.Source, error)
 	UpdateSource(name, newUrl string) error
+	SetScrapeProfile(name string, profile *entity.ScrapeProfile) error
+	SetInterval(name string, interval entity.Interval) error
+	SetLanguage(name, language string) error
+	SetRetention(name string, retention *entity.Retention) error
+	// RemoveSourceByName returns ErrSourceNotFound when there is no source with the name.
 	RemoveSourceByName(sourceName string) error
+	// UpdateSourceIfMatch changes the source atomically when its ETag matches the If-Match value,
+	// "*" or a list of ETags, an empty value matches any source. It returns the changed source.
+	UpdateSourceIfMatch(name, ifMatch string, change func(source *entity.Source)) (entity.Source, error)
+	// RemoveSourceIfMatch removes the source when its ETag matches the If-Match value.
+	RemoveSourceIfMatch(name, ifMatch string) error
 }
 
 // sourceFolder implements SourceManager using a folder-based storage for sources.
@@ -33,24 +87,22 @@ func CreateSourceFolder(pathToSources string) SourceManager {
 
 // CreateSource creates a new source with the provided name and URL.
 func (sourceManager sourceFolder) CreateSource(name, url string) (entity.Source, error) {
-	sources, err := readFromFile(sourceManager.path)
-	if err != nil {
-		log.Printf("Error reading from file: %v", err)
-		return entity.Source{}, err
-	}
-	for _, source := range sources {
-		if string(source.Name) == name {
-			return entity.Source{}, errors.New(fmt.Sprintf("Source with name %s already exists", name))
+	return sourceManager.AddSource(entity.Source{Name: entity.SourceName(name), PathToFile: entity.PathToFile(url)})
+}
+
+// AddSource creates the source with its settings in a single change of the resource file.
+func (sourceManager sourceFolder) AddSource(newSource entity.Source) (entity.Source, error) {
+	newSource.Retention = nonZero(newSource.Retention)
+	newSource.Version = 1
+	err := sourceManager.modify(func(sources []entity.Source) ([]entity.Source, error) {
+		for _, source := range sources {
+			if source.Name == newSource.Name {
+				return nil, sourceExists(string(newSource.Name))
+			}
 		}
-	}
-	newSource := entity.Source{
-		Name:       entity.SourceName(name),
-		PathToFile: entity.PathToFile(url),
-	}
-	sources = append(sources, newSource)
-	err = writeToFile(sourceManager.path, sources)
+		return append(sources, newSource), nil
+	})
 	if err != nil {
-		log.Printf("Error writing to file: %v", err)
 		return entity.Source{}, err
 	}
 	log.Printf("Created new resource: %v", newSource)
@@ -69,7 +121,7 @@ func (sourceManager sourceFolder) GetSource(name string) (entity.Source, error)
 			return source, nil
 		}
 	}
-	return entity.Source{}, errors.New("no resources found for name: " + name)
+	return entity.Source{}, sourceError{ErrSourceNotFound, "no resources found for name: " + name}
 }
 
 // GetSources from source file.
@@ -84,43 +136,125 @@ func (sourceManager sourceFolder) GetSources() ([]entity.Source, error) {
 
 // UpdateSource identified by its old URL.
 func (sourceManager sourceFolder) UpdateSource(name, newUrl string) error {
-	sources, err := readFromFile(sourceManager.path)
+	_, err := sourceManager.UpdateSourceIfMatch(name, "", func(source *entity.Source) {
+		source.PathToFile = entity.PathToFile(newUrl)
+	})
+	return err
+}
+
+// SetScrapeProfile of the source identified by name.
+// A nil profile makes the source be parsed by format detection again.
+func (sourceManager sourceFolder) SetScrapeProfile(name string, profile *entity.ScrapeProfile) error {
+	_, err := sourceManager.UpdateSourceIfMatch(name, "", func(source *entity.Source) {
+		source.Scrape = profile
+	})
+	return err
+}
+
+// SetInterval between fetches of the source identified by name.
+// A zero interval makes the source be fetched at the default interval.
+func (sourceManager sourceFolder) SetInterval(name string, interval entity.Interval) error {
+	_, err := sourceManager.UpdateSourceIfMatch(name, "", func(source *entity.Source) {
+		source.Interval = interval
+	})
+	return err
+}
+
+// SetLanguage of the news of the source identified by name.
+// An empty language keeps the language given by the feed of the source.
+func (sourceManager sourceFolder) SetLanguage(name, language string) error {
+	_, err := sourceManager.UpdateSourceIfMatch(name, "", func(source *entity.Source) {
+		source.Language = language
+	})
+	return err
+}
+
+// SetRetention of the news of the source identified by name.
+// A nil or zero retention makes the news of the source be kept by the default retention.
+func (sourceManager sourceFolder) SetRetention(name string, retention *entity.Retention) error {
+	_, err := sourceManager.UpdateSourceIfMatch(name, "", func(source *entity.Source) {
+		source.Retention = retention
+	})
+	return err
+}
+
+// RemoveSourceByName from the resource file.
+func (sourceManager sourceFolder) RemoveSourceByName(sourceName string) error {
+	return sourceManager.RemoveSourceIfMatch(sourceName, "")
+}
+
+// UpdateSourceIfMatch changes the source identified by name when its ETag matches.
+func (sourceManager sourceFolder) UpdateSourceIfMatch(name, ifMatch string, change func(source *entity.Source)) (entity.Source, error) {
+	var changed entity.Source
+	err := sourceManager.modify(func(sources []entity.Source) ([]entity.Source, error) {
+		for i, source := range sources {
+			if string(source.Name) == name {
+				if !MatchETag(ifMatch, SourceETag(source)) {
+					return nil, sourceChanged(name)
+				}
+				change(&sources[i])
+				sources[i].Retention = nonZero(sources[i].Retention)
+				sources[i].Version = source.Version + 1
+				changed = sources[i]
+				return sources, nil
+			}
+		}
+		return nil, sourceNotFound(name)
+	})
+	return changed, err
+}
+
+// RemoveSourceIfMatch from the resource file when its ETag matches.
+// A source that does not exist is not found without an If-Match value and changed with one.
+func (sourceManager sourceFolder) RemoveSourceIfMatch(name, ifMatch string) error {
+	err := sourceManager.modify(func(sources []entity.Source) ([]entity.Source, error) {
+		kept := make([]entity.Source, 0, len(sources))
+		for _, source := range sources {
+			if string(source.Name) != name {
+				kept = append(kept, source)
+			} else if !MatchETag(ifMatch, SourceETag(source)) {
+				return nil, sourceChanged(name)
+			}
+		}
+		if len(kept) == len(sources) {
+			return nil, removedError(name, ifMatch)
+		}
+		return kept, nil
+	})
 	if err != nil {
-		log.Printf("Error reading from file: %v", err)
 		return err
 	}
-	for i, source := range sources {
-		if string(source.Name) == name {
-			sources[i].PathToFile = entity.PathToFile(newUrl)
-			return writeToFile(sourceManager.path, sources)
-		}
-	}
-	return fmt.Errorf("source with name %s not found", name)
+	log.Printf("Removed source with name: %s", name)
+	return nil
 }
 
-// RemoveSourceByName from the resource file.
-func (sourceManager sourceFolder) RemoveSourceByName(sourceName string) error {
+// modify the sources holding the lock of the sources file, so that changes of other
+// processes sharing the file are not lost. The changed sources are written back.
+func (sourceManager sourceFolder) modify(change func(sources []entity.Source) ([]entity.Source, error)) error {
+	lock, err := safefile.LockFile(sourceManager.path)
+	if err != nil {
+		log.Printf("Error locking sources file: %v", err)
+		return err
+	}
+	defer lock.Unlock()
 	sources, err := readFromFile(sourceManager.path)
 	if err != nil {
 		log.Printf("Error reading from file: %v", err)
 		return err
 	}
-	deletedSources := make([]entity.Source, 0)
-	for _, s := range sources {
-		if string(s.Name) != sourceName {
-			deletedSources = append(deletedSources, s)
-		}
+	sources, err = change(sources)
+	if err != nil {
+		return err
 	}
-	err = writeToFile(sourceManager.path, deletedSources)
+	err = writeToFile(sourceManager.path, sources)
 	if err != nil {
 		log.Printf("Error writing to file: %v", err)
 		return err
 	}
-	log.Printf("Removed source with name: %s", sourceName)
 	return nil
 }
 
-// writeToFile sources in JSON format.
+// writeToFile sources in JSON format, replacing the file atomically.
 func writeToFile(path string, sources []entity.Source) error {
 	jsonData, err := json.MarshalIndent(sources, "", "  ")
 	if err != nil {
@@ -128,7 +262,7 @@ func writeToFile(path string, sources []entity.Source) error {
 		return err
 	}
 
-	err = os.WriteFile(path, jsonData, 0644)
+	err = safefile.WriteFile(path, jsonData, 0644)
 	if err != nil {
 		log.Printf("Error writing to file: %v", err)
 		return err
@@ -142,17 +276,6 @@ func readFromFile(path string) ([]entity.Source, error) {
 	if err != nil {
 		if os.IsNotExist(err) {
 			log.Printf("Source file does not exist, creating a new one: %v", path)
-			newFile, err := os.Create(path)
-			if err != nil {
-				log.Printf("Error creating new source file: %v", err)
-				return nil, err
-			}
-			defer func(newFile *os.File) {
-				err := newFile.Close()
-				if err != nil {
-					log.Printf("failed to close file: %v", err)
-				}
-			}(newFile)
 			var emptySources []entity.Source
 			if err := writeToFile(path, emptySources); err != nil {
 				log.Printf("Error initializing new source file: %v", err)
@@ -177,3 +300,11 @@ func readFromFile(path string) ([]entity.Source, error) {
 	}
 	return sources, nil
 }
+
+// nonZero retention, nil for a zero one.
+func nonZero(retention *entity.Retention) *entity.Retention {
+	if retention == nil || retention.IsZero() {
+		return nil
+	}
+	return retention
+}
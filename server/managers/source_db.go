@@ -0,0 +1,191 @@
+package managers
+
+import (
+	"encoding/json"
+	"log"
+	"news-aggregator/internal/entity"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// sourceDB implements SourceManager storing sources in the embedded database.
+type sourceDB struct {
+	db *bolt.DB
+}
+
+// CreateSourceDB in the given database opened with OpenDB.
+func CreateSourceDB(db *bolt.DB) SourceManager {
+	return sourceDB{db}
+}
+
+// CreateSource creates a new source with the provided name and URL.
+func (s sourceDB) CreateSource(name, url string) (entity.Source, error) {
+	return s.AddSource(entity.Source{Name: entity.SourceName(name), PathToFile: entity.PathToFile(url)})
+}
+
+// AddSource creates the source with its settings in a single transaction.
+func (s sourceDB) AddSource(newSource entity.Source) (entity.Source, error) {
+	newSource.Retention = nonZero(newSource.Retention)
+	newSource.Version = 1
+	name := string(newSource.Name)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sourcesBucket)
+		if bucket.Get([]byte(name)) != nil {
+			return sourceExists(name)
+		}
+		return putSource(bucket, newSource)
+	})
+	if err != nil {
+		log.Printf("Error creating source %s: %v", name, err)
+		return entity.Source{}, err
+	}
+	log.Printf("Created new resource: %v", newSource)
+	return newSource, nil
+}
+
+// GetSource by given name from the database.
+func (s sourceDB) GetSource(name string) (entity.Source, error) {
+	var source entity.Source
+	err := s.db.View(func(tx *bolt.Tx) error {
+		jsonData := tx.Bucket(sourcesBucket).Get([]byte(name))
+		if jsonData == nil {
+			return sourceError{ErrSourceNotFound, "no resources found for name: " + name}
+		}
+		return json.Unmarshal(jsonData, &source)
+	})
+	if err != nil {
+		return entity.Source{}, err
+	}
+	return source, nil
+}
+
+// GetSources from the database ordered by name.
+func (s sourceDB) GetSources() ([]entity.Source, error) {
+	var sources []entity.Source
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(sourcesBucket).ForEach(func(_, v []byte) error {
+			var source entity.Source
+			if err := json.Unmarshal(v, &source); err != nil {
+				return err
+			}
+			sources = append(sources, source)
+			return nil
+		})
+	})
+	if err != nil {
+		log.Printf("Error reading sources from database: %v", err)
+		return nil, err
+	}
+	return sources, nil
+}
+
+// UpdateSource URL of the source identified by name.
+func (s sourceDB) UpdateSource(name, newUrl string) error {
+	_, err := s.UpdateSourceIfMatch(name, "", func(source *entity.Source) {
+		source.PathToFile = entity.PathToFile(newUrl)
+	})
+	return err
+}
+
+// SetScrapeProfile of the source identified by name.
+// A nil profile makes the source be parsed by format detection again.
+func (s sourceDB) SetScrapeProfile(name string, profile *entity.ScrapeProfile) error {
+	_, err := s.UpdateSourceIfMatch(name, "", func(source *entity.Source) {
+		source.Scrape = profile
+	})
+	return err
+}
+
+// SetInterval between fetches of the source identified by name.
+// A zero interval makes the source be fetched at the default interval.
+func (s sourceDB) SetInterval(name string, interval entity.Interval) error {
+	_, err := s.UpdateSourceIfMatch(name, "", func(source *entity.Source) {
+		source.Interval = interval
+	})
+	return err
+}
+
+// SetLanguage of the news of the source identified by name.
+// An empty language keeps the language given by the feed of the source.
+func (s sourceDB) SetLanguage(name, language string) error {
+	_, err := s.UpdateSourceIfMatch(name, "", func(source *entity.Source) {
+		source.Language = language
+	})
+	return err
+}
+
+// SetRetention of the news of the source identified by name.
+// A nil or zero retention makes the news of the source be kept by the default retention.
+func (s sourceDB) SetRetention(name string, retention *entity.Retention) error {
+	_, err := s.UpdateSourceIfMatch(name, "", func(source *entity.Source) {
+		source.Retention = retention
+	})
+	return err
+}
+
+// RemoveSourceByName from the database, its news are kept.
+func (s sourceDB) RemoveSourceByName(sourceName string) error {
+	return s.RemoveSourceIfMatch(sourceName, "")
+}
+
+// UpdateSourceIfMatch changes the source identified by name within a transaction when its ETag matches.
+func (s sourceDB) UpdateSourceIfMatch(name, ifMatch string, change func(source *entity.Source)) (entity.Source, error) {
+	var source entity.Source
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sourcesBucket)
+		jsonData := bucket.Get([]byte(name))
+		if jsonData == nil {
+			return sourceNotFound(name)
+		}
+		if err := json.Unmarshal(jsonData, &source); err != nil {
+			return err
+		}
+		if !MatchETag(ifMatch, SourceETag(source)) {
+			return sourceChanged(name)
+		}
+		version := source.Version
+		change(&source)
+		source.Retention = nonZero(source.Retention)
+		source.Version = version + 1
+		return putSource(bucket, source)
+	})
+	if err != nil {
+		return entity.Source{}, err
+	}
+	return source, nil
+}
+
+// RemoveSourceIfMatch from the database when its ETag matches, its news are kept.
+// A source that does not exist is not found without an If-Match value and changed with one.
+func (s sourceDB) RemoveSourceIfMatch(name, ifMatch string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sourcesBucket)
+		jsonData := bucket.Get([]byte(name))
+		if jsonData == nil {
+			return removedError(name, ifMatch)
+		}
+		var source entity.Source
+		if err := json.Unmarshal(jsonData, &source); err != nil {
+			return err
+		}
+		if !MatchETag(ifMatch, SourceETag(source)) {
+			return sourceChanged(name)
+		}
+		return bucket.Delete([]byte(name))
+	})
+	if err != nil {
+		log.Printf("Error removing source %s: %v", name, err)
+		return err
+	}
+	log.Printf("Removed source with name: %s", name)
+	return nil
+}
+
+// putSource into the sources bucket.
+func putSource(bucket *bolt.Bucket, source entity.Source) error {
+	jsonData, err := json.Marshal(source)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(source.Name), jsonData)
+}
@@ -0,0 +1,41 @@
+package managers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"news-aggregator/internal/entity"
+)
+
+func TestSourceDB(t *testing.T) {
+	s := CreateSourceDB(openTestDB(t))
+
+	created, err := s.CreateSource("source1", "path1")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Source{Name: "source1", PathToFile: "path1", Version: 1}, created)
+	_, err = s.CreateSource("source1", "path1")
+	assert.EqualError(t, err, "Source with name source1 already exists")
+	_, err = s.CreateSource("source2", "path2")
+	assert.NoError(t, err)
+
+	profile := &entity.ScrapeProfile{ItemSelector: "article"}
+	assert.NoError(t, s.UpdateSource("source1", "path3"))
+	assert.NoError(t, s.SetScrapeProfile("source1", profile))
+	assert.NoError(t, s.SetInterval("source1", entity.Interval(time.Minute)))
+	assert.NoError(t, s.SetLanguage("source1", "de"))
+	assert.NoError(t, s.SetRetention("source1", &entity.Retention{MaxCount: 10}))
+	assert.EqualError(t, s.UpdateSource("nonexistent", "path"), "source with name nonexistent not found")
+
+	source, err := s.GetSource("source1")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Source{Name: "source1", PathToFile: "path3", Scrape: profile, Interval: entity.Interval(time.Minute), Language: "de", Retention: &entity.Retention{MaxCount: 10}, Version: 6}, source)
+	_, err = s.GetSource("nonexistent")
+	assert.EqualError(t, err, "no resources found for name: nonexistent")
+
+	assert.NoError(t, s.RemoveSourceByName("source1"))
+	assert.ErrorIs(t, s.RemoveSourceByName("source1"), ErrSourceNotFound)
+	sources, err := s.GetSources()
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Source{{Name: "source2", PathToFile: "path2", Version: 1}}, sources)
+}
@@ -3,37 +3,36 @@ package managers
 import (
 	"encoding/json"
 	"github.com/stretchr/testify/assert"
-	"log"
 	"news-aggregator/internal/entity"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestGetSources(t *testing.T) {
-	setupTestFile()
-	defer cleanupTestFile()
+	path := setupTestFile(t)
 
 	sources := []entity.Source{
 		{Name: "source1", PathToFile: entity.PathToFile("path1")},
 		{Name: "source2", PathToFile: entity.PathToFile("path2")},
 	}
-	writeTestDataToFile(sources)
-	s := CreateSourceFolder("test_sources.json")
+	writeTestDataToFile(t, path, sources)
+	s := CreateSourceFolder(path)
 	result, err := s.GetSources()
 	assert.Nil(t, err, "Expected no error")
 	assert.Equal(t, sources, result, "Expected sources to match")
 }
 
 func TestGetSource(t *testing.T) {
-	setupTestFile()
-	defer cleanupTestFile()
+	path := setupTestFile(t)
 
 	sources := []entity.Source{
 		{Name: "source1", PathToFile: entity.PathToFile("path1")},
 		{Name: "source2", PathToFile: entity.PathToFile("path3")},
 	}
-	writeTestDataToFile(sources)
-	s := CreateSourceFolder("test_sources.json")
+	writeTestDataToFile(t, path, sources)
+	s := CreateSourceFolder(path)
 	result, err := s.GetSource("source1")
 	assert.Nil(t, err, "Expected no error")
 	assert.Equal(t, sources[0], result, "Expected source to match")
@@ -45,12 +44,11 @@ func TestGetSource(t *testing.T) {
 }
 
 func TestCreateSource(t *testing.T) {
-	setupTestFile()
-	defer cleanupTestFile()
-	s := CreateSourceFolder("test_sources.json")
+	path := setupTestFile(t)
+	s := CreateSourceFolder(path)
 	result, err := s.CreateSource("source1", "path1")
 	assert.Nil(t, err, "Expected no error")
-	assert.Equal(t, entity.Source{Name: "source1", PathToFile: "path1"}, result, "Expected source to match")
+	assert.Equal(t, entity.Source{Name: "source1", PathToFile: "path1", Version: 1}, result, "Expected source to match")
 
 	result, err = s.CreateSource("source1", "path2")
 	assert.NotNil(t, err, "Expected an error")
@@ -58,16 +56,87 @@ func TestCreateSource(t *testing.T) {
 	assert.Equal(t, entity.Source{}, result, "Expected empty source")
 }
 
+func TestSetScrapeProfile(t *testing.T) {
+	path := setupTestFile(t)
+
+	writeTestDataToFile(t, path, []entity.Source{{Name: "source1", PathToFile: "path1"}})
+	s := CreateSourceFolder(path)
+	profile := &entity.ScrapeProfile{ItemSelector: "article", DateLayout: "2006-01-02"}
+
+	err := s.SetScrapeProfile("source1", profile)
+	assert.Nil(t, err, "Expected no error")
+	result, err := s.GetSource("source1")
+	assert.Nil(t, err, "Expected no error")
+	assert.Equal(t, profile, result.Scrape, "Expected scrape profile to be stored")
+
+	err = s.SetScrapeProfile("nonexistent", profile)
+	assert.EqualError(t, err, "source with name nonexistent not found", "Expected specific error message")
+}
+
+func TestSetInterval(t *testing.T) {
+	path := setupTestFile(t)
+
+	writeTestDataToFile(t, path, []entity.Source{{Name: "source1", PathToFile: "path1"}})
+	s := CreateSourceFolder(path)
+
+	err := s.SetInterval("source1", entity.Interval(15*time.Minute))
+	assert.Nil(t, err, "Expected no error")
+	result, err := s.GetSource("source1")
+	assert.Nil(t, err, "Expected no error")
+	assert.Equal(t, entity.Interval(15*time.Minute), result.Interval, "Expected interval to be stored")
+
+	err = s.SetInterval("nonexistent", 0)
+	assert.EqualError(t, err, "source with name nonexistent not found", "Expected specific error message")
+}
+
+func TestSetLanguage(t *testing.T) {
+	path := setupTestFile(t)
+
+	writeTestDataToFile(t, path, []entity.Source{{Name: "source1", PathToFile: "path1"}})
+	s := CreateSourceFolder(path)
+
+	err := s.SetLanguage("source1", "uk")
+	assert.Nil(t, err, "Expected no error")
+	result, err := s.GetSource("source1")
+	assert.Nil(t, err, "Expected no error")
+	assert.Equal(t, "uk", result.Language, "Expected language to be stored")
+
+	err = s.SetLanguage("nonexistent", "uk")
+	assert.EqualError(t, err, "source with name nonexistent not found", "Expected specific error message")
+}
+
+func TestSetRetention(t *testing.T) {
+	path := setupTestFile(t)
+
+	writeTestDataToFile(t, path, []entity.Source{{Name: "source1", PathToFile: "path1"}})
+	s := CreateSourceFolder(path)
+
+	retention := &entity.Retention{MaxAge: entity.Interval(720 * time.Hour), MaxCount: 100}
+	err := s.SetRetention("source1", retention)
+	assert.Nil(t, err, "Expected no error")
+	result, err := s.GetSource("source1")
+	assert.Nil(t, err, "Expected no error")
+	assert.Equal(t, retention, result.Retention, "Expected retention to be stored")
+
+	err = s.SetRetention("source1", &entity.Retention{})
+	assert.Nil(t, err, "Expected no error")
+	result, err = s.GetSource("source1")
+	assert.Nil(t, err, "Expected no error")
+	assert.Nil(t, result.Retention, "Expected zero retention to be reset")
+
+	err = s.SetRetention("nonexistent", nil)
+	assert.EqualError(t, err, "source with name nonexistent not found", "Expected specific error message")
+}
+
 func TestRemoveSourceByName(t *testing.T) {
-	setupTestFile()
-	defer cleanupTestFile()
+	path := setupTestFile(t)
 
 	sources := []entity.Source{
 		{Name: "source1", PathToFile: entity.PathToFile("path1")},
 		{Name: "source2", PathToFile: entity.PathToFile("path3")},
 	}
-	writeTestDataToFile(sources)
-	s := CreateSourceFolder("test_sources.json")
+	writeTestDataToFile(t, path, sources)
+	s := CreateSourceFolder(path)
 	err := s.RemoveSourceByName("source1")
 	assert.Nil(t, err, "Expected no error")
 
@@ -76,19 +145,18 @@ func TestRemoveSourceByName(t *testing.T) {
 	assert.Equal(t, "source2", string(remainingSources[0].Name), "Expected remaining source to be 'source2'")
 
 	err = s.RemoveSourceByName("nonexistent")
-	assert.Nil(t, err, "Expected no error for removing nonexistent source")
+	assert.ErrorIs(t, err, ErrSourceNotFound, "Expected an error for removing nonexistent source")
 }
 
 func TestUpdateSource(t *testing.T) {
-	setupTestFile()
-	defer cleanupTestFile()
+	path := setupTestFile(t)
 
 	sources := []entity.Source{
 		{Name: "source1", PathToFile: entity.PathToFile("path1")},
 		{Name: "source2", PathToFile: entity.PathToFile("path3")},
 	}
-	writeTestDataToFile(sources)
-	s := CreateSourceFolder("test_sources.json")
+	writeTestDataToFile(t, path, sources)
+	s := CreateSourceFolder(path)
 	err := s.UpdateSource("source1", "newpath")
 	assert.Nil(t, err, "Expected no error")
 
@@ -101,62 +169,77 @@ func TestUpdateSource(t *testing.T) {
 }
 
 func TestReadFromFileNonExistent(t *testing.T) {
-	path := "non_existent_file.json"
-	s := CreateSourceFolder(path)
+	s := CreateSourceFolder(filepath.Join(t.TempDir(), "non_existent_file.json"))
 
 	sources, err := s.GetSources()
 	assert.Nil(t, err, "Expected no error when file does not exist")
 	assert.Equal(t, 0, len(sources), "Expected no sources from non-existent file")
-
-	os.Remove(path)
 }
 
 func TestWriteToFileError(t *testing.T) {
-	setupTestFile()
-	defer cleanupTestFile()
-	os.Chmod("test_sources.json", 0444)
+	path := setupTestFile(t)
+	os.Chmod(path, 0444)
 
-	s := CreateSourceFolder("test_sources.json")
+	s := CreateSourceFolder(path)
 	_, err := s.CreateSource("source1", "path1")
 	assert.NotNil(t, err, "Expected an error due to write protection")
 }
 
 func TestReadFromFileError(t *testing.T) {
-	setupTestFile()
-	defer cleanupTestFile()
+	path := setupTestFile(t)
 	invalidJSON := []byte(`invalid json`)
-	os.WriteFile("test_sources.json", invalidJSON, 0644)
+	os.WriteFile(path, invalidJSON, 0644)
 
-	s := CreateSourceFolder("test_sources.json")
+	s := CreateSourceFolder(path)
 	_, err := s.GetSources()
 	assert.NotNil(t, err, "Expected an error due to invalid JSON")
 }
 
-func setupTestFile() {
-	data := []byte(`[]`)
-	pathToResources := "test_sources.json"
-	err := os.WriteFile(pathToResources, data, 0644)
-	if err != nil {
-		log.Fatalf("Error setting up test file: %v", err)
+// setupTestFile of empty sources in a temporary directory, so the lock files of the
+// sources file are left there too.
+func setupTestFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test_sources.json")
+	if err := os.WriteFile(path, []byte(`[]`), 0644); err != nil {
+		t.Fatalf("Error setting up test file: %v", err)
 	}
+	return path
 }
 
-func cleanupTestFile() {
-	pathToResources := "test_sources.json"
-	err := os.Remove(pathToResources)
+func writeTestDataToFile(t *testing.T, path string, sources []entity.Source) {
+	t.Helper()
+	jsonData, err := json.MarshalIndent(sources, "", "  ")
 	if err != nil {
-		log.Fatalf("Error cleaning up test file: %v", err)
+		t.Fatalf("Error marshalling JSON: %v", err)
+	}
+	if err := os.WriteFile(path, jsonData, 0644); err != nil {
+		t.Fatalf("Error writing test data to file: %v", err)
 	}
 }
 
-func writeTestDataToFile(sources []entity.Source) {
-	jsonData, err := json.MarshalIndent(sources, "", "  ")
-	if err != nil {
-		log.Fatalf("Error marshalling JSON: %v", err)
-	}
-	pathToResources := "test_sources.json"
-	err = os.WriteFile(pathToResources, jsonData, 0644)
-	if err != nil {
-		log.Fatalf("Error writing test data to file: %v", err)
+func TestSourceManager_AddSource(t *testing.T) {
+	for name, s := range sourceTestManagers(t) {
+		t.Run(name, func(t *testing.T) {
+			source := entity.Source{
+				Name:       "bbc",
+				PathToFile: "https://bbc.com/rss",
+				Scrape:     &entity.ScrapeProfile{ItemSelector: "article"},
+				Interval:   entity.Interval(time.Hour),
+				Language:   "en",
+				Retention:  &entity.Retention{},
+			}
+			added, err := s.AddSource(source)
+			assert.NoError(t, err)
+			want := source
+			want.Retention = nil
+			want.Version = 1
+			assert.Equal(t, want, added)
+			got, err := s.GetSource("bbc")
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+
+			_, err = s.AddSource(entity.Source{Name: "bbc", PathToFile: "https://bbc.co.uk/rss"})
+			assert.ErrorIs(t, err, ErrSourceExists)
+		})
 	}
 }
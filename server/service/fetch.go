@@ -1,75 +1,183 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"news-aggregator/internal/entity"
 	"news-aggregator/server/managers"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultWorkers is the number of sources fetched at once when Fetch.Workers is not set.
+	defaultWorkers = 4
+	// defaultTimeout for fetching a single source when Fetch.Timeout is not set.
+	defaultTimeout = 30 * time.Second
 )
 
 type Fetch struct {
 	SourceManager managers.SourceManager
 	NewsManager   managers.NewsManager
 	FeedManager   managers.FeedManager
+	// Workers limits the number of sources fetched concurrently.
+	Workers int
+	// Timeout limits fetching and storing the news of a single source.
+	Timeout time.Duration
+}
+
+// SourceReport summarises fetching news of a single source.
+type SourceReport struct {
+	Source    entity.SourceName `json:"source"`
+	Fetched   int               `json:"fetched"`
+	New       int               `json:"new"`
+	Duplicate int               `json:"duplicate"`
+	// Hints of the source on when to fetch it next.
+	Hints managers.Hints `json:"-"`
+	// NotModified is set when the feed did not change since the previous fetch.
+	NotModified bool          `json:"notModified,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	Err         error         `json:"-"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// Report of a single UpdateNews run, with one entry per source in the order of the sources.
+type Report struct {
+	Started time.Time      `json:"started"`
+	Sources []SourceReport `json:"sources"`
+}
+
+// Failed returns the number of sources that could not be updated.
+func (r Report) Failed() int {
+	failed := 0
+	for _, s := range r.Sources {
+		if s.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Err joins the errors of all failed sources, it is nil if every source was updated.
+func (r Report) Err() error {
+	var errs []error
+	for _, s := range r.Sources {
+		if s.Err != nil {
+			errs = append(errs, fmt.Errorf("source %s: %w", s.Source, s.Err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // UpdateNews from all registered sources and updates the local storage.
-func (f Fetch) UpdateNews() error {
+// Sources are fetched concurrently by a bounded pool of workers, each with its own timeout.
+// A failing source does not stop the others: the returned error joins the failures
+// and the report lists the outcome of every source.
+func (f Fetch) UpdateNews(ctx context.Context) (Report, error) {
+	report := Report{Started: time.Now()}
 	sources, err := f.SourceManager.GetSources()
 	if err != nil {
 		log.Printf("Error fetching sources: %v", err)
-		return err
+		return report, err
 	}
-	for _, s := range sources {
-		err := f.fetchNewsFromSource(entity.Source{
-			Name:       s.Name,
-			PathToFile: s.PathToFile,
-		})
-		if err != nil {
-			log.Printf("Error fetching news from resource %s: %v", s, err)
-			return err
+	report.Sources = make([]SourceReport, len(sources))
+
+	workers := f.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(sources); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				report.Sources[i] = f.UpdateSource(ctx, sources[i])
+			}
+		}()
+	}
+	for i := range sources {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, s := range report.Sources {
+		if s.Err != nil {
+			log.Printf("Error fetching news from resource %s: %v", s.Source, s.Err)
 		}
 	}
-	return nil
+	return report, report.Err()
 }
 
-// fetchNewsFromSource and updates local storage if the news is not already present.
-func (f Fetch) fetchNewsFromSource(resource entity.Source) error {
-	news, err := f.FeedManager.FetchFeed(string(resource.PathToFile))
-	if err != nil {
-		log.Printf("Failed to fetch news from %s: %v", resource.PathToFile, err)
-		return err
+// UpdateSource fetches a single source within its timeout and reports the outcome.
+func (f Fetch) UpdateSource(ctx context.Context, source entity.Source) SourceReport {
+	timeout := f.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
 	}
-	allNews, err := f.NewsManager.GetNewsFromFolder(string(resource.Name))
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	started := time.Now()
+	report, err := f.fetchRecovered(ctx, source)
+	report.Source = source.Name
+	report.Duration = time.Since(started)
 	if err != nil {
-		log.Printf("Failed to get existing news for %s: %v", resource.Name, err)
-		return err
-	}
-	allNewsLink := make([]entity.Link, 0)
-	for _, n := range allNews {
-		allNewsLink = append(allNewsLink, n.Link)
+		report.Err = err
+		report.Error = err.Error()
 	}
+	return report
+}
 
-	newsWithoutRepeat := make([]entity.News, 0)
-	for _, loadedNews := range news {
-		if !articleExists(allNewsLink, loadedNews) {
-			newsWithoutRepeat = append(newsWithoutRepeat, loadedNews)
+// fetchRecovered fetches the source and turns a panic, for instance of a parser
+// on a malformed feed, into an error of the source.
+func (f Fetch) fetchRecovered(ctx context.Context, source entity.Source) (report SourceReport, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic fetching news from %s: %v\n%s", source.Name, r, debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
 		}
+	}()
+	return f.fetchNewsFromSource(ctx, source)
+}
+
+// fetchNewsFromSource and updates local storage if the news is not already present.
+func (f Fetch) fetchNewsFromSource(ctx context.Context, resource entity.Source) (SourceReport, error) {
+	var report SourceReport
+	feed, err := f.FeedManager.FetchFeed(ctx, resource)
+	report.Hints = feed.Hints
+	if errors.Is(err, managers.ErrNotModified) {
+		report.NotModified = true
+		return report, nil
 	}
-	if len(newsWithoutRepeat) > 0 {
-		err = f.NewsManager.AddNews(newsWithoutRepeat, string(resource.Name))
-		if err != nil {
-			log.Printf("Failed to add news for %s: %v", resource.Name, err)
-			return err
+	if err != nil {
+		log.Printf("Failed to fetch news from %s: %v", resource.PathToFile, err)
+		return report, err
+	}
+	news := feed.News
+	if resource.Language != "" {
+		for i := range news {
+			news[i].Language = resource.Language
 		}
 	}
-	return nil
-}
-
-func articleExists(existingLinks []entity.Link, newArticle entity.News) bool {
-	for _, link := range existingLinks {
-		if newArticle.Link == link {
-			return true
+	report.Fetched = len(news)
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	if len(news) > 0 {
+		added, err := f.NewsManager.AddNews(news, string(resource.Name))
+		if err != nil {
+			log.Printf("Failed to add news for %s: %v", resource.Name, err)
+			return report, err
 		}
+		report.New = len(added)
 	}
-	return false
+	report.Duplicate = report.Fetched - report.New
+	f.FeedManager.SaveValidators(resource, feed.Validators)
+	return report, nil
 }
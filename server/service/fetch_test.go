@@ -1,12 +1,15 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"news-aggregator/internal/entity"
+	"news-aggregator/server/managers"
 	"news-aggregator/server/managers/mock_managers"
 )
 
@@ -26,15 +29,13 @@ func TestFetch_UpdateNews(t *testing.T) {
 	}
 
 	mockSourceManager.EXPECT().GetSources().Return(sources, nil).Times(1)
-	mockNewsManager.EXPECT().GetNewsFromFolder("Source1").Return([]entity.News{
-		{Link: "link1"},
-	}, nil).Times(1)
-	mockFeedManager.EXPECT().FetchFeed("file1.xml").Return([]entity.News{
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), sources[0]).Return(managers.Feed{News: []entity.News{
 		{Link: "link2"},
-	}, nil).Times(1)
+	}}, nil).Times(1)
 	mockNewsManager.EXPECT().AddNews([]entity.News{
 		{Link: "link2"},
-	}, "Source1").Return(nil).Times(1)
+	}, "Source1").Return([]entity.News{{Link: "link2"}}, nil).Times(1)
+	mockFeedManager.EXPECT().SaveValidators(sources[0], managers.Validators{}).Times(1)
 
 	fetchService := Fetch{
 		SourceManager: mockSourceManager,
@@ -42,8 +43,9 @@ func TestFetch_UpdateNews(t *testing.T) {
 		FeedManager:   mockFeedManager,
 	}
 
-	err := fetchService.UpdateNews()
+	report, err := fetchService.UpdateNews(context.Background())
 	assert.NoError(t, err)
+	assert.Equal(t, []SourceReport{{Source: "Source1", Fetched: 1, New: 1, Duration: report.Sources[0].Duration}}, report.Sources)
 }
 func TestFetch_UpdateNews_FetchError(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -61,9 +63,9 @@ func TestFetch_UpdateNews_FetchError(t *testing.T) {
 	}
 
 	mockSourceManager.EXPECT().GetSources().Return(sources, nil).Times(1)
-	mockFeedManager.EXPECT().FetchFeed("file1.xml").Return(nil, errors.New("fetch error")).Times(1)
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), sources[0]).Return(managers.Feed{}, errors.New("fetch error")).Times(1)
 
-	// Не ожидать вызова GetNewsFromFolder, потому что FetchFeed возвращает ошибку
+	// GetNewsFromFolder is not expected to be called, because FetchFeed returns an error
 
 	fetchService := Fetch{
 		SourceManager: mockSourceManager,
@@ -71,8 +73,9 @@ func TestFetch_UpdateNews_FetchError(t *testing.T) {
 		FeedManager:   mockFeedManager,
 	}
 
-	err := fetchService.UpdateNews()
+	report, err := fetchService.UpdateNews(context.Background())
 	assert.Error(t, err, "fetch error")
+	assert.Equal(t, 1, report.Failed())
 }
 func TestFetch_fetchNewsFromSource_FetchError(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -87,7 +90,7 @@ func TestFetch_fetchNewsFromSource_FetchError(t *testing.T) {
 		PathToFile: entity.PathToFile("file1.xml"),
 	}
 
-	mockFeedManager.EXPECT().FetchFeed("file1.xml").Return(nil, errors.New("fetch error")).Times(1)
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), resource).Return(managers.Feed{}, errors.New("fetch error")).Times(1)
 
 	fetchService := Fetch{
 		SourceManager: mockSourceManager,
@@ -95,7 +98,7 @@ func TestFetch_fetchNewsFromSource_FetchError(t *testing.T) {
 		FeedManager:   mockFeedManager,
 	}
 
-	err := fetchService.fetchNewsFromSource(resource)
+	_, err := fetchService.fetchNewsFromSource(context.Background(), resource)
 	assert.Error(t, err, "fetch error")
 }
 
@@ -112,21 +115,17 @@ func TestFetch_fetchNewsFromSource_Success(t *testing.T) {
 		PathToFile: entity.PathToFile("file1.xml"),
 	}
 
-	existingNews := []entity.News{
-		{
-			Link: "existing_link",
-		},
-	}
-
 	newFeed := []entity.News{
 		{
 			Link: "new_link",
 		},
 	}
 
-	mockFeedManager.EXPECT().FetchFeed("file1.xml").Return(newFeed, nil).Times(1)
-	mockNewsManager.EXPECT().GetNewsFromFolder("Source1").Return(existingNews, nil).Times(1)
-	mockNewsManager.EXPECT().AddNews([]entity.News{{Link: "new_link"}}, "Source1").Return(nil).Times(1)
+	validators := managers.Validators{URL: "file1.xml", ETag: `"v1"`}
+
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), resource).Return(managers.Feed{News: newFeed, Validators: validators}, nil).Times(1)
+	mockNewsManager.EXPECT().AddNews(newFeed, "Source1").Return(newFeed, nil).Times(1)
+	mockFeedManager.EXPECT().SaveValidators(resource, validators).Times(1)
 
 	fetchService := Fetch{
 		SourceManager: mockSourceManager,
@@ -134,6 +133,194 @@ func TestFetch_fetchNewsFromSource_Success(t *testing.T) {
 		FeedManager:   mockFeedManager,
 	}
 
-	err := fetchService.fetchNewsFromSource(resource)
+	report, err := fetchService.fetchNewsFromSource(context.Background(), resource)
 	assert.NoError(t, err, "Expected no error from fetchNewsFromSource")
+	assert.Equal(t, SourceReport{Fetched: 1, New: 1}, report)
+}
+
+func TestFetch_fetchNewsFromSource_SourceLanguage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockNewsManager := mock_managers.NewMockNewsManager(ctrl)
+	mockFeedManager := mock_managers.NewMockFeedManager(ctrl)
+
+	resource := entity.Source{Name: "Source1", PathToFile: "file1.xml", Language: "uk"}
+	newFeed := []entity.News{{Link: "new_link", Language: "en"}}
+
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), resource).Return(managers.Feed{News: newFeed}, nil)
+	mockNewsManager.EXPECT().AddNews([]entity.News{{Link: "new_link", Language: "uk"}}, "Source1").Return(newFeed, nil)
+	mockFeedManager.EXPECT().SaveValidators(resource, managers.Validators{})
+
+	fetchService := Fetch{NewsManager: mockNewsManager, FeedManager: mockFeedManager}
+	_, err := fetchService.fetchNewsFromSource(context.Background(), resource)
+	assert.NoError(t, err)
+}
+
+func TestFetch_fetchNewsFromSource_Duplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockNewsManager := mock_managers.NewMockNewsManager(ctrl)
+	mockFeedManager := mock_managers.NewMockFeedManager(ctrl)
+
+	resource := entity.Source{Name: "Source1", PathToFile: "file1.xml"}
+	newFeed := []entity.News{
+		{Link: "http://www.example.com/a/?utm_source=rss"},
+		{Link: "https://m.example.com/b"},
+		{Link: "https://example.com/c", Canonical: "https://example.com/articles/c"},
+		{Link: "https://example.com/d?utm_medium=feed"},
+		{Link: "https://example.com/d"},
+	}
+
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), resource).Return(managers.Feed{News: newFeed}, nil)
+	// The storage skips the news it already has.
+	mockNewsManager.EXPECT().AddNews(newFeed, "Source1").Return(newFeed[3:4], nil)
+	mockFeedManager.EXPECT().SaveValidators(resource, managers.Validators{})
+
+	fetchService := Fetch{NewsManager: mockNewsManager, FeedManager: mockFeedManager}
+	report, err := fetchService.fetchNewsFromSource(context.Background(), resource)
+	assert.NoError(t, err)
+	assert.Equal(t, SourceReport{Fetched: 5, New: 1, Duplicate: 4}, report)
+}
+
+func TestFetch_fetchNewsFromSource_AddNewsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockNewsManager := mock_managers.NewMockNewsManager(ctrl)
+	mockFeedManager := mock_managers.NewMockFeedManager(ctrl)
+
+	resource := entity.Source{Name: "Source1", PathToFile: "file1.xml"}
+	feed := managers.Feed{News: []entity.News{{Link: "new_link"}}, Validators: managers.Validators{URL: "file1.xml", ETag: `"v1"`}}
+
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), resource).Return(feed, nil)
+	mockNewsManager.EXPECT().AddNews(feed.News, "Source1").Return(nil, errors.New("disk full"))
+	// SaveValidators is not expected to be called, so the feed is downloaded in full next time
+
+	fetchService := Fetch{NewsManager: mockNewsManager, FeedManager: mockFeedManager}
+	_, err := fetchService.fetchNewsFromSource(context.Background(), resource)
+	assert.EqualError(t, err, "disk full")
+}
+
+func TestFetch_UpdateNews_FailingSourceDoesNotStopOthers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	mockNewsManager := mock_managers.NewMockNewsManager(ctrl)
+	mockFeedManager := mock_managers.NewMockFeedManager(ctrl)
+
+	sources := []entity.Source{
+		{Name: "Broken", PathToFile: "broken.xml"},
+		{Name: "Working", PathToFile: "working.xml"},
+	}
+	mockSourceManager.EXPECT().GetSources().Return(sources, nil)
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), sources[0]).Return(managers.Feed{}, errors.New("fetch error"))
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), sources[1]).Return(managers.Feed{News: []entity.News{
+		{Link: "link1"}, {Link: "link2"},
+	}}, nil)
+	mockNewsManager.EXPECT().AddNews([]entity.News{{Link: "link1"}, {Link: "link2"}}, "Working").Return([]entity.News{{Link: "link2"}}, nil)
+	mockFeedManager.EXPECT().SaveValidators(sources[1], managers.Validators{})
+
+	fetchService := Fetch{
+		SourceManager: mockSourceManager,
+		NewsManager:   mockNewsManager,
+		FeedManager:   mockFeedManager,
+		Workers:       2,
+	}
+
+	report, err := fetchService.UpdateNews(context.Background())
+	assert.ErrorContains(t, err, "source Broken: fetch error")
+	assert.Equal(t, 1, report.Failed())
+	assert.Equal(t, "fetch error", report.Sources[0].Error)
+	assert.Equal(t, entity.SourceName("Working"), report.Sources[1].Source)
+	assert.Equal(t, 2, report.Sources[1].Fetched)
+	assert.Equal(t, 1, report.Sources[1].New)
+	assert.Equal(t, 1, report.Sources[1].Duplicate)
+}
+
+func TestFetch_UpdateNews_PanickingSourceDoesNotStopOthers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	mockNewsManager := mock_managers.NewMockNewsManager(ctrl)
+	mockFeedManager := mock_managers.NewMockFeedManager(ctrl)
+
+	sources := []entity.Source{
+		{Name: "Broken", PathToFile: "broken.xml"},
+		{Name: "Working", PathToFile: "working.xml"},
+	}
+	mockSourceManager.EXPECT().GetSources().Return(sources, nil)
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), sources[0]).DoAndReturn(func(context.Context, entity.Source) (managers.Feed, error) {
+		panic("nil pointer dereference")
+	})
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), sources[1]).Return(managers.Feed{News: []entity.News{{Link: "link1"}}}, nil)
+	mockNewsManager.EXPECT().AddNews([]entity.News{{Link: "link1"}}, "Working").Return([]entity.News{{Link: "link1"}}, nil)
+	mockFeedManager.EXPECT().SaveValidators(sources[1], managers.Validators{})
+
+	fetchService := Fetch{
+		SourceManager: mockSourceManager,
+		NewsManager:   mockNewsManager,
+		FeedManager:   mockFeedManager,
+		Workers:       1,
+	}
+
+	report, err := fetchService.UpdateNews(context.Background())
+	assert.ErrorContains(t, err, "source Broken: panic: nil pointer dereference")
+	assert.Equal(t, 1, report.Failed())
+	assert.Equal(t, 1, report.Sources[1].New)
+}
+
+func TestFetch_UpdateNews_SourceTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	mockNewsManager := mock_managers.NewMockNewsManager(ctrl)
+	mockFeedManager := mock_managers.NewMockFeedManager(ctrl)
+
+	sources := []entity.Source{{Name: "Slow", PathToFile: "slow.xml"}}
+	mockSourceManager.EXPECT().GetSources().Return(sources, nil)
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), sources[0]).
+		DoAndReturn(func(ctx context.Context, _ entity.Source) (managers.Feed, error) {
+			<-ctx.Done()
+			return managers.Feed{}, ctx.Err()
+		})
+
+	fetchService := Fetch{
+		SourceManager: mockSourceManager,
+		NewsManager:   mockNewsManager,
+		FeedManager:   mockFeedManager,
+		Timeout:       10 * time.Millisecond,
+	}
+
+	report, err := fetchService.UpdateNews(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, report.Failed())
+}
+
+func TestFetch_fetchNewsFromSource_NotModified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockNewsManager := mock_managers.NewMockNewsManager(ctrl)
+	mockFeedManager := mock_managers.NewMockFeedManager(ctrl)
+
+	resource := entity.Source{
+		Name:       "Source1",
+		PathToFile: entity.PathToFile("file1.xml"),
+	}
+
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), resource).Return(managers.Feed{}, managers.ErrNotModified).Times(1)
+
+	fetchService := Fetch{
+		NewsManager: mockNewsManager,
+		FeedManager: mockFeedManager,
+	}
+
+	report, err := fetchService.fetchNewsFromSource(context.Background(), resource)
+	assert.NoError(t, err, "Expected not modified feed to be no error")
+	assert.Equal(t, SourceReport{NotModified: true}, report)
 }
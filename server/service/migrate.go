@@ -0,0 +1,91 @@
+package service
+
+import (
+	"log"
+	"news-aggregator/internal/entity"
+	"news-aggregator/server/managers"
+	"os"
+)
+
+// Migration copies sources and news stored in files into another storage backend.
+type Migration struct {
+	// PathToSources is the sources file to import.
+	PathToSources string
+	// PathToNews is the news folder to import, with a sub folder per source.
+	PathToNews    string
+	SourceManager managers.SourceManager
+	NewsManager   managers.NewsManager
+}
+
+// MigrationReport counts the imported sources and news.
+type MigrationReport struct {
+	Sources int
+	News    int
+}
+
+// Run the migration. Every sub folder of the news folder is imported, also of sources
+// that are no longer registered. Sources already present in the target are kept,
+// so the migration can be run again.
+func (m Migration) Run() (MigrationReport, error) {
+	var report MigrationReport
+	sources, err := managers.CreateSourceFolder(m.PathToSources).GetSources()
+	if err != nil {
+		log.Printf("Error reading sources to migrate: %v", err)
+		return report, err
+	}
+	for _, source := range sources {
+		imported, err := m.importSource(source)
+		if err != nil {
+			return report, err
+		}
+		if imported {
+			report.Sources++
+		}
+	}
+
+	folders, err := os.ReadDir(m.PathToNews)
+	if err != nil {
+		log.Printf("Error reading news folder to migrate: %v", err)
+		return report, err
+	}
+	newsFolder := managers.CreateNewsFolder(m.PathToNews)
+	for _, folder := range folders {
+		if !folder.IsDir() {
+			continue
+		}
+		news, err := newsFolder.GetNewsFromFolder(folder.Name())
+		if err != nil {
+			return report, err
+		}
+		if len(news) == 0 {
+			continue
+		}
+		added, err := m.NewsManager.AddNews(news, folder.Name())
+		if err != nil {
+			return report, err
+		}
+		report.News += len(added)
+	}
+	return report, nil
+}
+
+// importSource unless the target already has a source with the same name.
+func (m Migration) importSource(source entity.Source) (bool, error) {
+	if _, err := m.SourceManager.GetSource(string(source.Name)); err == nil {
+		return false, nil
+	}
+	name := string(source.Name)
+	if _, err := m.SourceManager.CreateSource(name, string(source.PathToFile)); err != nil {
+		return false, err
+	}
+	_, err := m.SourceManager.UpdateSourceIfMatch(name, "", func(imported *entity.Source) {
+		imported.Scrape = source.Scrape
+		imported.Interval = source.Interval
+		imported.Language = source.Language
+		imported.Retention = source.Retention
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
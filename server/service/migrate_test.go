@@ -0,0 +1,71 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"news-aggregator/internal/entity"
+	"news-aggregator/server/managers"
+)
+
+func TestMigration_Run(t *testing.T) {
+	dir := t.TempDir()
+	pathToSources := filepath.Join(dir, "sources.json")
+	pathToNews := filepath.Join(dir, "news")
+
+	sources := []entity.Source{
+		{Name: "bbc", PathToFile: "https://bbc.com/rss", Interval: entity.Interval(time.Minute), Language: "en"},
+		{Name: "cnn", PathToFile: "https://cnn.com/rss", Scrape: &entity.ScrapeProfile{ItemSelector: "article"}, Retention: &entity.Retention{MaxCount: 10}},
+	}
+	jsonData, err := json.Marshal(sources)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(pathToSources, jsonData, 0644))
+
+	news := map[string][]entity.News{
+		"bbc":     {{Title: "Title", Link: "https://bbc.com/1", Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}},
+		"removed": {{Title: "Title", Link: "https://bbc.com/2", Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}},
+	}
+	_, err = managers.CreateNewsFolder(pathToNews).AddNews(news["bbc"], "bbc")
+	assert.NoError(t, err)
+	_, err = managers.CreateNewsFolder(pathToNews).AddNews(news["removed"], "removed")
+	assert.NoError(t, err)
+
+	db, err := managers.OpenDB(filepath.Join(dir, "news.db"))
+	assert.NoError(t, err)
+	defer db.Close()
+	m := Migration{
+		PathToSources: pathToSources,
+		PathToNews:    pathToNews,
+		SourceManager: managers.CreateSourceDB(db),
+		NewsManager:   managers.CreateNewsDB(db),
+	}
+
+	report, err := m.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, MigrationReport{Sources: 2, News: 2}, report)
+
+	migrated, err := m.SourceManager.GetSources()
+	assert.NoError(t, err)
+	for i := range sources {
+		// Created and updated once with the settings.
+		sources[i].Version = 2
+	}
+	assert.Equal(t, sources, migrated)
+	for _, name := range []string{"bbc", "removed"} {
+		got, err := m.NewsManager.GetNewsFromFolder(name)
+		assert.NoError(t, err)
+		assert.Equal(t, news[name], got)
+	}
+
+	// Running again keeps the migrated sources and news.
+	report, err = m.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Sources)
+	got, err := m.NewsManager.GetNewsFromFolder("bbc")
+	assert.NoError(t, err)
+	assert.Equal(t, news["bbc"], got)
+}
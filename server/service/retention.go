@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"news-aggregator/internal/entity"
+	"news-aggregator/server/managers"
+	"time"
+)
+
+// Retention deletes the stored news exceeding the retention of their source
+// and compacts old daily news files into monthly archives.
+type Retention struct {
+	SourceManager managers.SourceManager
+	NewsManager   managers.NewsManager
+	// MaxAge and MaxCount are the default retention of the sources without their own,
+	// also of the news of removed sources. Zero values keep all news.
+	MaxAge   time.Duration
+	MaxCount int
+	// ArchiveAfter is the age of the daily news files compacted into archives, zero compacts none.
+	ArchiveAfter time.Duration
+}
+
+// SourceRetention summarises the retention of the news of a single source.
+type SourceRetention struct {
+	Source    string           `json:"source"`
+	Retention entity.Retention `json:"retention"`
+	Kept      int              `json:"kept"`
+	// Deleted links of the news, on a dry run of the news that would be deleted.
+	Deleted []entity.Link `json:"deleted,omitempty"`
+	// Archived daily files, on a dry run the files that would be archived.
+	Archived []string `json:"archived,omitempty"`
+	Err      error    `json:"-"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// RetentionReport of a single Run, with one entry per source with stored news.
+type RetentionReport struct {
+	Started time.Time         `json:"started"`
+	DryRun  bool              `json:"dryRun,omitempty"`
+	Sources []SourceRetention `json:"sources"`
+}
+
+// Err joins the errors of all sources that could not be pruned.
+func (r RetentionReport) Err() error {
+	var errs []error
+	for _, s := range r.Sources {
+		if s.Err != nil {
+			errs = append(errs, fmt.Errorf("source %s: %w", s.Source, s.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run the retention over the stored news of every source. A dry run reports what would be
+// deleted and archived without changing the storage. A failing source does not stop the others.
+func (r Retention) Run(ctx context.Context, dryRun bool) (RetentionReport, error) {
+	report := RetentionReport{Started: time.Now(), DryRun: dryRun}
+	sources, err := r.SourceManager.GetSources()
+	if err != nil {
+		log.Printf("Error getting sources: %v", err)
+		return report, err
+	}
+	retentions := make(map[string]entity.Retention, len(sources))
+	for _, source := range sources {
+		if source.Retention != nil {
+			retentions[string(source.Name)] = *source.Retention
+		}
+	}
+	stored, err := r.NewsManager.ListSources()
+	if err != nil {
+		log.Printf("Error listing sources of news: %v", err)
+		return report, err
+	}
+
+	options := managers.PruneOptions{Now: report.Started, DryRun: dryRun}
+	if r.ArchiveAfter > 0 {
+		options.ArchiveBefore = report.Started.Add(-r.ArchiveAfter)
+	}
+	for _, source := range stored {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		retention, ok := retentions[source]
+		if !ok {
+			retention = entity.Retention{MaxAge: entity.Interval(r.MaxAge), MaxCount: r.MaxCount}
+		}
+		options.Retention = retention
+		entry := SourceRetention{Source: source, Retention: retention}
+		result, err := r.NewsManager.Prune(ctx, source, options)
+		if err != nil {
+			log.Printf("Error pruning news of %s: %v", source, err)
+			entry.Err = err
+			entry.Error = err.Error()
+		}
+		entry.Kept = result.Kept
+		entry.Archived = result.Archived
+		for _, item := range result.Deleted {
+			entry.Deleted = append(entry.Deleted, item.Link)
+		}
+		report.Sources = append(report.Sources, entry)
+	}
+	return report, report.Err()
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"news-aggregator/internal/entity"
+	"news-aggregator/server/managers"
+	"news-aggregator/server/managers/mock_managers"
+)
+
+func TestRetention_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	mockNewsManager := mock_managers.NewMockNewsManager(ctrl)
+
+	own := entity.Retention{MaxCount: 10}
+	mockSourceManager.EXPECT().GetSources().Return([]entity.Source{
+		{Name: "bbc", Retention: &own},
+		{Name: "cnn"},
+	}, nil)
+	mockNewsManager.EXPECT().ListSources().Return([]string{"bbc", "cnn", "removed"}, nil)
+
+	defaultRetention := entity.Retention{MaxAge: entity.Interval(720 * time.Hour)}
+	var archiveBefore time.Time
+	expectPrune := func(source string, retention entity.Retention, result managers.PruneResult, err error) {
+		mockNewsManager.EXPECT().Prune(gomock.Any(), source, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, options managers.PruneOptions) (managers.PruneResult, error) {
+				assert.Equal(t, retention, options.Retention)
+				assert.True(t, options.DryRun)
+				archiveBefore = options.ArchiveBefore
+				return result, err
+			})
+	}
+	expectPrune("bbc", own, managers.PruneResult{Kept: 10, Deleted: []entity.News{{Link: "https://bbc.com/1"}}}, nil)
+	expectPrune("cnn", defaultRetention, managers.PruneResult{Kept: 2, Archived: []string{"2024-04-30.json"}}, nil)
+	expectPrune("removed", defaultRetention, managers.PruneResult{}, errors.New("permission denied"))
+
+	retention := Retention{
+		SourceManager: mockSourceManager,
+		NewsManager:   mockNewsManager,
+		MaxAge:        720 * time.Hour,
+		ArchiveAfter:  24 * time.Hour,
+	}
+	report, err := retention.Run(context.Background(), true)
+	assert.EqualError(t, err, "source removed: permission denied")
+	assert.True(t, report.DryRun)
+	assert.Equal(t, report.Started.Add(-24*time.Hour), archiveBefore)
+	assert.Equal(t, []SourceRetention{
+		{Source: "bbc", Retention: own, Kept: 10, Deleted: []entity.Link{"https://bbc.com/1"}},
+		{Source: "cnn", Retention: defaultRetention, Kept: 2, Archived: []string{"2024-04-30.json"}},
+		{Source: "removed", Retention: defaultRetention, Err: report.Sources[2].Err, Error: "permission denied"},
+	}, report.Sources)
+}
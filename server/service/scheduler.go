@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"news-aggregator/internal/entity"
+	"news-aggregator/server/managers"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultInterval between fetches of sources without their own interval.
+	defaultInterval = time.Hour
+	// defaultMaxBackoff caps the delay after consecutive failures.
+	defaultMaxBackoff = 24 * time.Hour
+	// defaultJitter is the fraction of the delay randomly added to spread the fetches.
+	defaultJitter = 0.1
+	// defaultResolution is how often the scheduler looks for due and new sources.
+	defaultResolution = 10 * time.Second
+)
+
+// Scheduler fetches every source on its own schedule.
+// Sources are polled at their own interval or the default one, never more often than
+// the TTL of their feed and not in the skip hours of the feed. Consecutive failures
+// back off exponentially and a Retry-After of the server is honoured.
+type Scheduler struct {
+	Fetch Fetch
+	// Interval between fetches of sources without their own interval.
+	Interval time.Duration
+	// MaxBackoff caps the delay after consecutive failures.
+	MaxBackoff time.Duration
+	// Jitter is the fraction of the delay randomly added to every delay, negative disables it.
+	Jitter float64
+	// Resolution is how often the scheduler looks for due and new sources.
+	Resolution time.Duration
+
+	mu     sync.Mutex
+	states map[entity.SourceName]*SourceStatus
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	random func() float64
+}
+
+// SourceStatus is the schedule of a single source.
+type SourceStatus struct {
+	Source    entity.SourceName `json:"source"`
+	Interval  entity.Interval   `json:"interval"`
+	LastRun   time.Time         `json:"lastRun,omitempty"`
+	NextRun   time.Time         `json:"nextRun"`
+	Failures  int               `json:"failures"`
+	LastError string            `json:"lastError,omitempty"`
+	Running   bool              `json:"running"`
+}
+
+// NewScheduler for the sources of the fetch service with the default interval.
+func NewScheduler(fetch Fetch, interval time.Duration) *Scheduler {
+	return &Scheduler{Fetch: fetch, Interval: interval}
+}
+
+// Start scheduling in the background until ctx is cancelled or Stop is called.
+// Every source is fetched right after start.
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.cancel = cancel
+	s.mu.Unlock()
+
+	resolution := s.Resolution
+	if resolution <= 0 {
+		resolution = defaultResolution
+	}
+	log.Println("Starting fetch scheduler with default interval:", s.interval())
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(resolution)
+		defer ticker.Stop()
+		for {
+			s.runDue(ctx, time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop scheduling and wait for the running fetches to finish.
+// Running fetches are cancelled.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	s.wg.Wait()
+	log.Println("Fetch scheduler stopped")
+}
+
+// Status of all known sources ordered by name.
+func (s *Scheduler) Status() []SourceStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := make([]SourceStatus, 0, len(s.states))
+	for _, state := range s.states {
+		status = append(status, *state)
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Source < status[j].Source
+	})
+	return status
+}
+
+// runDue syncs the schedule with the current sources and fetches the due ones.
+// At most Fetch.Workers sources are fetched at once.
+func (s *Scheduler) runDue(ctx context.Context, now time.Time) {
+	sources, err := s.Fetch.SourceManager.GetSources()
+	if err != nil {
+		log.Printf("Error fetching sources: %v", err)
+		return
+	}
+	workers := s.Fetch.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.states == nil {
+		s.states = make(map[entity.SourceName]*SourceStatus)
+	}
+	known := make(map[entity.SourceName]bool, len(sources))
+	running := 0
+	for _, state := range s.states {
+		if state.Running {
+			running++
+		}
+	}
+	for _, source := range sources {
+		known[source.Name] = true
+		state, ok := s.states[source.Name]
+		if !ok {
+			state = &SourceStatus{Source: source.Name, NextRun: now}
+			s.states[source.Name] = state
+		}
+		state.Interval = entity.Interval(s.sourceInterval(source))
+		if state.Running || now.Before(state.NextRun) || running >= workers {
+			continue
+		}
+		state.Running = true
+		running++
+		s.wg.Add(1)
+		go func(source entity.Source) {
+			defer s.wg.Done()
+			report := s.Fetch.UpdateSource(ctx, source)
+			s.finish(source, report, time.Now())
+		}(source)
+	}
+	for name, state := range s.states {
+		if !known[name] && !state.Running {
+			delete(s.states, name)
+		}
+	}
+}
+
+// finish records the outcome of a fetch and schedules the next one.
+func (s *Scheduler) finish(source entity.Source, report SourceReport, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[source.Name]
+	if !ok {
+		return
+	}
+	state.Running = false
+	state.LastRun = now
+	state.LastError = report.Error
+	if report.Err != nil {
+		state.Failures++
+		log.Printf("Error fetching news from resource %s: %v", source.Name, report.Err)
+	} else {
+		state.Failures = 0
+		log.Printf("Source %s: fetched %d, new %d, duplicate %d, not modified %t",
+			source.Name, report.Fetched, report.New, report.Duplicate, report.NotModified)
+	}
+	state.NextRun = s.nextRun(now, source, state.Failures, report.Hints)
+}
+
+// nextRun of the source after a fetch with the given number of consecutive failures.
+func (s *Scheduler) nextRun(now time.Time, source entity.Source, failures int, hints managers.Hints) time.Time {
+	delay := s.sourceInterval(source)
+	if hints.TTL > delay {
+		delay = hints.TTL
+	}
+	maxBackoff := s.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+	for i := 0; i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if failures > 0 && delay > maxBackoff {
+		delay = maxBackoff
+	}
+	if hints.RetryAfter > delay {
+		delay = hints.RetryAfter
+	}
+	delay += s.jitter(delay)
+
+	next := now.Add(delay)
+	for i := 0; i < 24 && slices.Contains(hints.SkipHours, next.UTC().Hour()); i++ {
+		next = next.Truncate(time.Hour).Add(time.Hour)
+	}
+	return next
+}
+
+// sourceInterval is the own interval of the source or the default one.
+func (s *Scheduler) sourceInterval(source entity.Source) time.Duration {
+	if source.Interval > 0 {
+		return time.Duration(source.Interval)
+	}
+	return s.interval()
+}
+
+func (s *Scheduler) interval() time.Duration {
+	if s.Interval > 0 {
+		return s.Interval
+	}
+	return defaultInterval
+}
+
+// jitter randomly added to the delay.
+func (s *Scheduler) jitter(delay time.Duration) time.Duration {
+	fraction := s.Jitter
+	if fraction == 0 {
+		fraction = defaultJitter
+	}
+	if fraction < 0 {
+		return 0
+	}
+	random := s.random
+	if random == nil {
+		random = rand.Float64
+	}
+	return time.Duration(float64(delay) * fraction * random())
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"news-aggregator/internal/entity"
+	"news-aggregator/server/managers"
+	"news-aggregator/server/managers/mock_managers"
+)
+
+func TestScheduler_nextRun(t *testing.T) {
+	now := time.Date(2001, 1, 1, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		source   entity.Source
+		failures int
+		hints    managers.Hints
+		want     time.Time
+	}{
+		{
+			name: "default interval",
+			want: now.Add(time.Hour),
+		},
+		{
+			name:   "source interval",
+			source: entity.Source{Interval: entity.Interval(15 * time.Minute)},
+			want:   now.Add(15 * time.Minute),
+		},
+		{
+			name:  "ttl longer than interval",
+			hints: managers.Hints{TTL: 2 * time.Hour},
+			want:  now.Add(2 * time.Hour),
+		},
+		{
+			name:     "exponential backoff",
+			failures: 3,
+			want:     now.Add(8 * time.Hour),
+		},
+		{
+			name:     "backoff capped",
+			failures: 10,
+			want:     now.Add(12 * time.Hour),
+		},
+		{
+			name:     "retry after",
+			failures: 1,
+			hints:    managers.Hints{RetryAfter: 5 * time.Hour},
+			want:     now.Add(5 * time.Hour),
+		},
+		{
+			name:  "skip hours",
+			hints: managers.Hints{SkipHours: []int{11, 12}},
+			want:  time.Date(2001, 1, 1, 13, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Scheduler{Interval: time.Hour, MaxBackoff: 12 * time.Hour, Jitter: -1}
+			assert.Equal(t, tt.want, s.nextRun(now, tt.source, tt.failures, tt.hints))
+		})
+	}
+}
+
+func TestScheduler_jitter(t *testing.T) {
+	s := Scheduler{Jitter: 0.5, random: func() float64 { return 0.5 }}
+	assert.Equal(t, 25*time.Minute, s.jitter(100*time.Minute))
+}
+
+func TestScheduler_runDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	mockNewsManager := mock_managers.NewMockNewsManager(ctrl)
+	mockFeedManager := mock_managers.NewMockFeedManager(ctrl)
+
+	sources := []entity.Source{
+		{Name: "Broken", PathToFile: "broken.xml"},
+		{Name: "Working", PathToFile: "working.xml", Interval: entity.Interval(10 * time.Minute)},
+	}
+	mockSourceManager.EXPECT().GetSources().Return(sources, nil).Times(2)
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), sources[0]).Return(managers.Feed{}, errors.New("fetch error")).Times(1)
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), sources[1]).Return(managers.Feed{}, managers.ErrNotModified).Times(1)
+
+	s := Scheduler{
+		Fetch: Fetch{
+			SourceManager: mockSourceManager,
+			NewsManager:   mockNewsManager,
+			FeedManager:   mockFeedManager,
+		},
+		Interval: time.Hour,
+		Jitter:   -1,
+	}
+	now := time.Now()
+	s.runDue(context.Background(), now)
+	s.wg.Wait()
+	// Nothing is due right after the fetch.
+	s.runDue(context.Background(), now)
+	s.wg.Wait()
+
+	status := s.Status()
+	assert.Len(t, status, 2)
+	assert.Equal(t, entity.SourceName("Broken"), status[0].Source)
+	assert.Equal(t, 1, status[0].Failures)
+	assert.Equal(t, "fetch error", status[0].LastError)
+	assert.WithinDuration(t, status[0].LastRun.Add(2*time.Hour), status[0].NextRun, time.Millisecond)
+	assert.Equal(t, entity.SourceName("Working"), status[1].Source)
+	assert.Equal(t, 0, status[1].Failures)
+	assert.Equal(t, entity.Interval(10*time.Minute), status[1].Interval)
+	assert.WithinDuration(t, status[1].LastRun.Add(10*time.Minute), status[1].NextRun, time.Millisecond)
+}
+
+func TestScheduler_RemovedSource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	mockFeedManager := mock_managers.NewMockFeedManager(ctrl)
+
+	source := entity.Source{Name: "Removed", PathToFile: "removed.xml"}
+	gomock.InOrder(
+		mockSourceManager.EXPECT().GetSources().Return([]entity.Source{source}, nil),
+		mockSourceManager.EXPECT().GetSources().Return(nil, nil),
+	)
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), source).Return(managers.Feed{}, managers.ErrNotModified)
+
+	s := Scheduler{Fetch: Fetch{SourceManager: mockSourceManager, FeedManager: mockFeedManager}}
+	s.runDue(context.Background(), time.Now())
+	s.wg.Wait()
+	assert.Len(t, s.Status(), 1)
+	s.runDue(context.Background(), time.Now())
+	assert.Empty(t, s.Status())
+}
+
+func TestScheduler_StopCancelsRunningFetch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSourceManager := mock_managers.NewMockSourceManager(ctrl)
+	mockFeedManager := mock_managers.NewMockFeedManager(ctrl)
+
+	source := entity.Source{Name: "Slow", PathToFile: "slow.xml"}
+	started := make(chan struct{})
+	mockSourceManager.EXPECT().GetSources().Return([]entity.Source{source}, nil).AnyTimes()
+	mockFeedManager.EXPECT().FetchFeed(gomock.Any(), source).
+		DoAndReturn(func(ctx context.Context, _ entity.Source) (managers.Feed, error) {
+			close(started)
+			<-ctx.Done()
+			return managers.Feed{}, ctx.Err()
+		})
+
+	s := NewScheduler(Fetch{SourceManager: mockSourceManager, FeedManager: mockFeedManager}, time.Hour)
+	s.Resolution = time.Millisecond
+	s.Start(context.Background())
+	<-started
+	s.Stop()
+
+	status := s.Status()
+	assert.Len(t, status, 1)
+	assert.False(t, status[0].Running)
+	assert.Equal(t, 1, status[0].Failures)
+}